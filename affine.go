@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Affine implements an affine transform stored as the top three rows
+// of a 4×4 matrix in row-major order. The bottom row is always
+// (0, 0, 0, 1) and is neither stored nor computed, which saves memory
+// and arithmetic compared to Mat4 when projections are not needed.
+type Affine [3][4]float64
+
+// IdentityAffine returns the identity transform.
+func IdentityAffine() Affine {
+	return Affine{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}}
+}
+
+// AffineFromLinear returns the transform with the linear part m
+// followed by the translation t.
+func AffineFromLinear(m Mat3, t Vec3) Affine {
+	return Affine{
+		{m[0][0], m[0][1], m[0][2], t.X},
+		{m[1][0], m[1][1], m[1][2], t.Y},
+		{m[2][0], m[2][1], m[2][2], t.Z},
+	}
+}
+
+// AffineFromMat4 returns the top three rows of m.
+// The bottom row of m is assumed to be (0, 0, 0, 1).
+func AffineFromMat4(m Mat4) Affine {
+	return Affine{m[0], m[1], m[2]}
+}
+
+// ApproxEqual reports whether each element of a is within epsilon of
+// the corresponding element of b.
+func (a Affine) ApproxEqual(b Affine, epsilon float64) bool {
+	for i := range a {
+		for j := range a[i] {
+			if !ApproxEqual(a[i][j], b[i][j], epsilon) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Inverse returns the inverse transform.
+// It returns false if the linear part is singular.
+//
+//	[M t]⁻¹ = [M⁻¹ −M⁻¹t]
+func (a Affine) Inverse() (Affine, bool) {
+	inv, ok := a.Linear().Inverse()
+	if !ok {
+		return Affine{}, false
+	}
+	return AffineFromLinear(inv, inv.MulVec3(a.Translation()).Mul(-1)), true
+}
+
+// Linear returns the 3×3 linear part of the transform.
+func (a Affine) Linear() Mat3 {
+	return Mat3{
+		{a[0][0], a[0][1], a[0][2]},
+		{a[1][0], a[1][1], a[1][2]},
+		{a[2][0], a[2][1], a[2][2]},
+	}
+}
+
+// Mat4 returns the transform as a 4×4 matrix.
+func (a Affine) Mat4() Mat4 {
+	return Mat4{a[0], a[1], a[2], {0, 0, 0, 1}}
+}
+
+// Mul returns the composition a×b, which applies b first and then a.
+func (a Affine) Mul(b Affine) Affine {
+	var p Affine
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			p[i][j] = a[i][0]*b[0][j] + a[i][1]*b[1][j] + a[i][2]*b[2][j]
+		}
+		p[i][3] += a[i][3]
+	}
+	return p
+}
+
+// TransformDirection applies the transform to a direction.
+// Translation does not affect directions.
+func (a Affine) TransformDirection(v Vec3) Vec3 {
+	return Vec3{
+		X: a[0][0]*v.X + a[0][1]*v.Y + a[0][2]*v.Z,
+		Y: a[1][0]*v.X + a[1][1]*v.Y + a[1][2]*v.Z,
+		Z: a[2][0]*v.X + a[2][1]*v.Y + a[2][2]*v.Z,
+	}
+}
+
+// TransformPoint applies the transform to a point, including translation.
+func (a Affine) TransformPoint(p Point) Point {
+	return Point{
+		X: a[0][0]*p.X + a[0][1]*p.Y + a[0][2]*p.Z + a[0][3],
+		Y: a[1][0]*p.X + a[1][1]*p.Y + a[1][2]*p.Z + a[1][3],
+		Z: a[2][0]*p.X + a[2][1]*p.Y + a[2][2]*p.Z + a[2][3],
+	}
+}
+
+// Translation returns the translation part of the transform.
+func (a Affine) Translation() Vec3 {
+	return Vec3{X: a[0][3], Y: a[1][3], Z: a[2][3]}
+}
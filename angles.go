@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Functions in this package take and return plain float64 angles in
+// radians. Radians and Degrees are distinct types for callers who want
+// the compiler to catch a mix-up of units at API boundaries.
+
+// Radians is an angle measured in radians.
+type Radians float64
+
+// Degrees is an angle measured in degrees.
+type Degrees float64
+
+// Degrees returns the angle r converted to degrees.
+func (r Radians) Degrees() Degrees {
+	return Degrees(float64(r) * 180 / math.Pi)
+}
+
+// Wrap returns the angle r wrapped to the range (−π, π].
+func (r Radians) Wrap() Radians {
+	return Radians(WrapAngle(float64(r)))
+}
+
+// Radians returns the angle d converted to radians.
+func (d Degrees) Radians() Radians {
+	return Radians(float64(d) * math.Pi / 180)
+}
+
+// Wrap returns the angle d wrapped to the range (−180, 180].
+func (d Degrees) Wrap() Degrees {
+	w := math.Remainder(float64(d), 360)
+	if w <= -180 {
+		w += 360
+	}
+	return Degrees(w)
+}
+
+// DeltaAngle returns the signed difference to − from between two angles
+// in radians, measured along the shorter arc. The result is in (−π, π],
+// so adding it to from turns towards to by the least amount.
+func DeltaAngle(from, to float64) float64 {
+	return WrapAngle(to - from)
+}
+
+// WrapAngle returns the angle a in radians wrapped to the range (−π, π].
+func WrapAngle(a float64) float64 {
+	w := math.Remainder(a, 2*math.Pi)
+	if w <= -math.Pi {
+		w += 2 * math.Pi
+	}
+	return w
+}
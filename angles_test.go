@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestAngles(t *testing.T) {
+	if got := math3d.Degrees(180).Radians(); got != math.Pi {
+		t.Errorf("Degrees.Radians: want %v, got %v\n", math.Pi, got)
+	}
+	if got := math3d.Radians(math.Pi / 2).Degrees(); math.Abs(float64(got)-90) > 1e-12 {
+		t.Errorf("Radians.Degrees: want %v, got %v\n", 90, got)
+	}
+
+	for _, tt := range []struct {
+		a, want float64
+	}{
+		{0, 0},
+		{math.Pi, math.Pi},
+		{-math.Pi, math.Pi},
+		{3 * math.Pi / 2, -math.Pi / 2},
+		{-7 * math.Pi / 2, math.Pi / 2},
+		{20 * math.Pi, 0},
+	} {
+		if got := math3d.WrapAngle(tt.a); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("WrapAngle(%v): want %v, got %v\n", tt.a, tt.want, got)
+		}
+	}
+	if got := math3d.Degrees(-540).Wrap(); got != 180 {
+		t.Errorf("Degrees.Wrap: want %v, got %v\n", 180, got)
+	}
+	if got := math3d.Degrees(350).Wrap(); got != -10 {
+		t.Errorf("Degrees.Wrap: want %v, got %v\n", -10, got)
+	}
+
+	for _, tt := range []struct {
+		from, to, want float64
+	}{
+		{0, 1, 1},
+		{1, 0, -1},
+		{math.Pi - 0.1, -math.Pi + 0.1, 0.2},
+		{-math.Pi + 0.1, math.Pi - 0.1, -0.2},
+		{0.5, 0.5 + 4*math.Pi, 0},
+	} {
+		if got := math3d.DeltaAngle(tt.from, tt.to); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("DeltaAngle(%v, %v): want %v, got %v\n", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// AxisAngle is a rotation by Angle radians, counter-clockwise when
+// looking down Axis towards the origin. The axis should be a unit vector.
+type AxisAngle struct {
+	Axis  Vec3
+	Angle float64
+}
+
+// AxisAngleFromQuaternion returns the axis-angle form of the unit
+// quaternion q, with an angle in [0, π]. The identity rotation has an
+// angle of zero and the x-axis as its axis.
+func AxisAngleFromQuaternion(q Quaternion) AxisAngle {
+	return AxisAngleFromRotationVector(q.RotationVector())
+}
+
+// AxisAngleFromRotationVector returns the axis-angle form of a rotation
+// vector. A zero vector has an angle of zero and the x-axis as its axis.
+func AxisAngleFromRotationVector(omega Vec3) AxisAngle {
+	angle := omega.Length()
+	if angle == 0 {
+		return AxisAngle{Axis: Vec3{X: 1}}
+	}
+	return AxisAngle{Axis: omega.Div(angle), Angle: angle}
+}
+
+// RotateAboutAxis returns v rotated counter-clockwise by angle radians
+// about the given unit axis using Rodrigues' rotation formula.
+//
+//	v' = v cosθ + (k × v) sinθ + k (k·v)(1 − cosθ)
+func RotateAboutAxis(v, axis Vec3, angle float64) Vec3 {
+	sin, cos := math.Sincos(angle)
+	return v.Mul(cos).Add(axis.Cross(v).Mul(sin)).Add(axis.Mul(axis.Dot(v) * (1 - cos)))
+}
+
+// RotateVec3 returns v rotated by the axis-angle rotation.
+func (a AxisAngle) RotateVec3(v Vec3) Vec3 {
+	return RotateAboutAxis(v, a.Axis, a.Angle)
+}
+
+// RotationVector returns the rotation vector, the axis scaled by the angle.
+func (a AxisAngle) RotationVector() Vec3 {
+	return a.Axis.Mul(a.Angle)
+}
+
+// ToMat3 returns the rotation matrix for the axis-angle rotation.
+func (a AxisAngle) ToMat3() Mat3 {
+	return Mat3FromAxisAngle(a.Axis, a.Angle)
+}
+
+// ToQuaternion returns the unit quaternion for the axis-angle rotation.
+func (a AxisAngle) ToQuaternion() Quaternion {
+	return QuaternionFromAxisAngle(a.Axis, a.Angle)
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// AABB is an axis-aligned bounding box spanning the points
+// from Min to Max inclusive.
+type AABB struct {
+	Min, Max Point
+}
+
+// BoundsAccumulator finds the bounds of points that arrive one at a
+// time, such as points streamed from a file, without storing them. The
+// zero value holds no points and is ready to use.
+type BoundsAccumulator struct {
+	bounds AABB
+	count  int
+}
+
+// AABBFromPoints returns the smallest box containing all the points.
+// It returns false if there are no points.
+func AABBFromPoints(points ...Point) (AABB, bool) {
+	return BoundsOf(points), len(points) != 0
+}
+
+// BoundsOf returns the smallest box containing all the points in a
+// single pass over them, or the empty box if there are none.
+func BoundsOf(points []Point) AABB {
+	var acc BoundsAccumulator
+	for _, p := range points {
+		acc.Add(p)
+	}
+	return acc.Bounds()
+}
+
+// BoundsOfVec3 returns the smallest box containing the points at the
+// ends of the vectors, or the empty box if there are none.
+func BoundsOfVec3(vectors []Vec3) AABB {
+	var acc BoundsAccumulator
+	for _, v := range vectors {
+		acc.Add(v.Point())
+	}
+	return acc.Bounds()
+}
+
+// ClosestPointOnAABB returns the point in the box nearest to p.
+func ClosestPointOnAABB(p Point, b AABB) Point {
+	return b.ClosestPointTo(p)
+}
+
+// EmptyAABB returns a box that contains no points, with Min at +∞ and Max
+// at −∞. It is the identity for Union and Include, so it is a convenient
+// starting value when accumulating bounds.
+func EmptyAABB() AABB {
+	inf := math.Inf(1)
+	return AABB{Min: Point{X: inf, Y: inf, Z: inf}, Max: Point{X: -inf, Y: -inf, Z: -inf}}
+}
+
+// Center returns the point midway between the corners of the box.
+func (b AABB) Center() Point {
+	return b.Min.Lerp(b.Max, 0.5)
+}
+
+// ClosestPointTo returns the point in the box nearest to p.
+// Points inside the box are returned unchanged.
+func (b AABB) ClosestPointTo(p Point) Point {
+	return p.Clamp(b.Min, b.Max)
+}
+
+// ContainsPoint reports whether p is inside the box or on its boundary.
+func (b AABB) ContainsPoint(p Point) bool {
+	return b.Min.X <= p.X && p.X <= b.Max.X &&
+		b.Min.Y <= p.Y && p.Y <= b.Max.Y &&
+		b.Min.Z <= p.Z && p.Z <= b.Max.Z
+}
+
+// Corners returns the eight corners of the box. Bits 0, 1, and 2 of the
+// index select the maximum x, y, and z coordinate respectively, so the
+// first corner is Min and the last is Max.
+func (b AABB) Corners() [8]Point {
+	var corners [8]Point
+	for i := range corners {
+		p := b.Min
+		if i&1 != 0 {
+			p.X = b.Max.X
+		}
+		if i&2 != 0 {
+			p.Y = b.Max.Y
+		}
+		if i&4 != 0 {
+			p.Z = b.Max.Z
+		}
+		corners[i] = p
+	}
+	return corners
+}
+
+// Expand returns the box grown by margin on every side.
+// A negative margin shrinks the box.
+func (b AABB) Expand(margin float64) AABB {
+	m := Vec3{X: margin, Y: margin, Z: margin}
+	return AABB{Min: b.Min.Add(m.Mul(-1)), Max: b.Max.Add(m)}
+}
+
+// Extents returns the half-size of the box along each axis,
+// the vector from the center to Max.
+func (b AABB) Extents() Vec3 {
+	return b.Max.Sub(b.Min).Mul(0.5)
+}
+
+// Include returns the smallest box containing both b and p.
+func (b AABB) Include(p Point) AABB {
+	return AABB{Min: b.Min.Min(p), Max: b.Max.Max(p)}
+}
+
+// Intersect returns the box shared by b and b2.
+// It returns false if the boxes do not overlap or touch.
+func (b AABB) Intersect(b2 AABB) (AABB, bool) {
+	i := AABB{Min: b.Min.Max(b2.Min), Max: b.Max.Min(b2.Max)}
+	if i.IsEmpty() {
+		return EmptyAABB(), false
+	}
+	return i, true
+}
+
+// IntersectsAABB reports whether the boxes overlap or touch.
+func (b AABB) IntersectsAABB(b2 AABB) bool {
+	return b.Min.X <= b2.Max.X && b2.Min.X <= b.Max.X &&
+		b.Min.Y <= b2.Max.Y && b2.Min.Y <= b.Max.Y &&
+		b.Min.Z <= b2.Max.Z && b2.Min.Z <= b.Max.Z
+}
+
+// IsEmpty reports whether the box contains no points, which is when Min
+// is greater than Max along any axis.
+func (b AABB) IsEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y || b.Min.Z > b.Max.Z
+}
+
+// Size returns the length of the box along each axis.
+func (b AABB) Size() Vec3 {
+	return b.Max.Sub(b.Min)
+}
+
+// Support returns the corner of the box furthest in the direction d.
+// It implements the ConvexShape interface.
+func (b AABB) Support(d Vec3) Point {
+	return b.pVertex(d)
+}
+
+// SurfaceArea returns the total area of the faces of the box, or 0 if
+// the box is empty.
+func (b AABB) SurfaceArea() float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+	s := b.Size()
+	return 2 * (s.X*s.Y + s.Y*s.Z + s.Z*s.X)
+}
+
+// Transform returns the smallest box containing the box transformed by
+// the affine matrix m, using Arvo's method from Graphics Gems.
+func (b AABB) Transform(m Mat4) AABB {
+	if b.IsEmpty() {
+		return b
+	}
+	t := m.Translation()
+	lo, hi := [3]float64{t.X, t.Y, t.Z}, [3]float64{t.X, t.Y, t.Z}
+	min, max := [3]float64{b.Min.X, b.Min.Y, b.Min.Z}, [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			e, f := m[i][j]*min[j], m[i][j]*max[j]
+			if e > f {
+				e, f = f, e
+			}
+			lo[i] += e
+			hi[i] += f
+		}
+	}
+	return AABB{Min: Point{X: lo[0], Y: lo[1], Z: lo[2]}, Max: Point{X: hi[0], Y: hi[1], Z: hi[2]}}
+}
+
+// Union returns the smallest box containing both b and b2.
+func (b AABB) Union(b2 AABB) AABB {
+	return AABB{Min: b.Min.Min(b2.Min), Max: b.Max.Max(b2.Max)}
+}
+
+// Add grows the bounds to include p.
+func (a *BoundsAccumulator) Add(p Point) {
+	if a.count == 0 {
+		a.bounds = AABB{Min: p, Max: p}
+	} else {
+		a.bounds.Min.X, a.bounds.Max.X = math.Min(a.bounds.Min.X, p.X), math.Max(a.bounds.Max.X, p.X)
+		a.bounds.Min.Y, a.bounds.Max.Y = math.Min(a.bounds.Min.Y, p.Y), math.Max(a.bounds.Max.Y, p.Y)
+		a.bounds.Min.Z, a.bounds.Max.Z = math.Min(a.bounds.Min.Z, p.Z), math.Max(a.bounds.Max.Z, p.Z)
+	}
+	a.count++
+}
+
+// Bounds returns the smallest box containing the points added so far,
+// or the empty box if there are none.
+func (a *BoundsAccumulator) Bounds() AABB {
+	if a.count == 0 {
+		return EmptyAABB()
+	}
+	return a.bounds
+}
+
+// Count returns the number of points added so far.
+func (a *BoundsAccumulator) Count() int {
+	return a.count
+}
+
+// Reset removes all the points.
+func (a *BoundsAccumulator) Reset() {
+	*a = BoundsAccumulator{}
+}
+
+// pVertex returns the corner of the box furthest along the direction n.
+func (b AABB) pVertex(n Vec3) Point {
+	p := b.Min
+	if n.X >= 0 {
+		p.X = b.Max.X
+	}
+	if n.Y >= 0 {
+		p.Y = b.Max.Y
+	}
+	if n.Z >= 0 {
+		p.Z = b.Max.Z
+	}
+	return p
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"testing"
+)
+
+func TestAABB(t *testing.T) {
+	b, ok := math3d.AABBFromPoints(math3d.Point{X: 1, Y: 5, Z: -1}, math3d.Point{X: -2, Y: 0, Z: 3}, math3d.Point{X: 0, Y: 2, Z: 1})
+	if want := (math3d.AABB{Min: math3d.Point{X: -2, Y: 0, Z: -1}, Max: math3d.Point{X: 1, Y: 5, Z: 3}}); !ok || b != want {
+		t.Errorf("AABBFromPoints: want %v, got %v %v\n", want, b, ok)
+	}
+	if _, ok := math3d.AABBFromPoints(); ok {
+		t.Errorf("AABBFromPoints: no points: want !ok, got ok\n")
+	}
+	if got, want := b.Center(), (math3d.Point{X: -0.5, Y: 2.5, Z: 1}); got != want {
+		t.Errorf("Center: want %v, got %v\n", want, got)
+	}
+	if got, want := b.Extents(), math3d.NewVec3(1.5, 2.5, 2); got != want {
+		t.Errorf("Extents: want %v, got %v\n", want, got)
+	}
+	if got, want := b.Size(), math3d.NewVec3(3, 5, 4); got != want {
+		t.Errorf("Size: want %v, got %v\n", want, got)
+	}
+	if got := b.SurfaceArea(); got != 94 {
+		t.Errorf("SurfaceArea: want 94, got %v\n", got)
+	}
+	corners := b.Corners()
+	if corners[0] != b.Min || corners[7] != b.Max || corners[5] != (math3d.Point{X: 1, Y: 0, Z: 3}) {
+		t.Errorf("Corners: want Min first, Max last, got %v\n", corners)
+	}
+	if !b.ContainsPoint(math3d.Point{X: 1, Y: 0, Z: 0}) || b.ContainsPoint(math3d.Point{X: 1.1, Y: 0, Z: 0}) {
+		t.Errorf("ContainsPoint: want boundary in and outside out\n")
+	}
+	if got, want := b.Expand(1), (math3d.AABB{Min: math3d.Point{X: -3, Y: -1, Z: -2}, Max: math3d.Point{X: 2, Y: 6, Z: 4}}); got != want {
+		t.Errorf("Expand: want %v, got %v\n", want, got)
+	}
+
+	c := math3d.AABB{Min: math3d.Point{X: 0, Y: 4, Z: 2}, Max: math3d.Point{X: 5, Y: 9, Z: 9}}
+	if got, want := b.Union(c), (math3d.AABB{Min: math3d.Point{X: -2, Y: 0, Z: -1}, Max: math3d.Point{X: 5, Y: 9, Z: 9}}); got != want {
+		t.Errorf("Union: want %v, got %v\n", want, got)
+	}
+	if got, ok := b.Intersect(c); !ok || got != (math3d.AABB{Min: math3d.Point{X: 0, Y: 4, Z: 2}, Max: math3d.Point{X: 1, Y: 5, Z: 3}}) {
+		t.Errorf("Intersect: want overlap, got %v %v\n", got, ok)
+	}
+	d := math3d.AABB{Min: math3d.Point{X: 2, Y: 0, Z: 0}, Max: math3d.Point{X: 3, Y: 1, Z: 1}}
+	if _, ok := b.Intersect(d); ok || b.IntersectsAABB(d) {
+		t.Errorf("Intersect: disjoint: want !ok, got ok\n")
+	}
+	if !b.IntersectsAABB(c) {
+		t.Errorf("IntersectsAABB: want true, got false\n")
+	}
+
+	empty := math3d.EmptyAABB()
+	if !empty.IsEmpty() || empty.Union(b) != b || empty.Include(b.Min) != (math3d.AABB{Min: b.Min, Max: b.Min}) {
+		t.Errorf("EmptyAABB: want identity for Union and Include\n")
+	}
+
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(10, 0, 0)).Mul(math3d.Mat4FromRotationZ(0.6))
+	tb := b.Transform(m)
+	want := math3d.EmptyAABB()
+	for _, p := range corners {
+		want = want.Include(m.TransformPoint(p))
+	}
+	if !tb.Min.ApproxEqual(want.Min, 1e-12) || !tb.Max.ApproxEqual(want.Max, 1e-12) {
+		t.Errorf("Transform: want %v, got %v\n", want, tb)
+	}
+}
+
+func TestBoundsOf(t *testing.T) {
+	points := []math3d.Point{{X: 1, Y: 5, Z: -1}, {X: -2, Y: 0, Z: 3}, {X: 0, Y: 2, Z: 1}}
+	want := math3d.AABB{Min: math3d.Point{X: -2, Y: 0, Z: -1}, Max: math3d.Point{X: 1, Y: 5, Z: 3}}
+	if got := math3d.BoundsOf(points); got != want {
+		t.Errorf("BoundsOf: want %v, got %v\n", want, got)
+	}
+	vectors := []math3d.Vec3{points[0].Vec3(), points[1].Vec3(), points[2].Vec3()}
+	if got := math3d.BoundsOfVec3(vectors); got != want {
+		t.Errorf("BoundsOfVec3: want %v, got %v\n", want, got)
+	}
+	if got := math3d.BoundsOf(nil); !got.IsEmpty() {
+		t.Errorf("BoundsOf: no points: want empty, got %v\n", got)
+	}
+
+	var acc math3d.BoundsAccumulator
+	if got := acc.Bounds(); !got.IsEmpty() || acc.Count() != 0 {
+		t.Errorf("BoundsAccumulator: zero: want empty, got %v %d\n", got, acc.Count())
+	}
+	for _, p := range points {
+		acc.Add(p)
+	}
+	if got := acc.Bounds(); got != want || acc.Count() != 3 {
+		t.Errorf("BoundsAccumulator: want %v 3, got %v %d\n", want, got, acc.Count())
+	}
+	acc.Reset()
+	acc.Add(math3d.Point{X: 7, Y: 8, Z: 9})
+	if got, want := acc.Bounds(), (math3d.AABB{Min: math3d.Point{X: 7, Y: 8, Z: 9}, Max: math3d.Point{X: 7, Y: 8, Z: 9}}); got != want || acc.Count() != 1 {
+		t.Errorf("BoundsAccumulator: Reset: want %v 1, got %v %d\n", want, got, acc.Count())
+	}
+}
+
+func TestClosestPointOnAABB(t *testing.T) {
+	b := math3d.AABB{Min: math3d.Point{X: -1, Y: -1, Z: -1}, Max: math3d.Point{X: 1, Y: 2, Z: 3}}
+	for _, tt := range []struct {
+		p, want math3d.Point
+	}{
+		{math3d.Point{X: 0, Y: 0, Z: 0}, math3d.Point{X: 0, Y: 0, Z: 0}},
+		{math3d.Point{X: 5, Y: 0, Z: 0}, math3d.Point{X: 1, Y: 0, Z: 0}},
+		{math3d.Point{X: -5, Y: 9, Z: 9}, math3d.Point{X: -1, Y: 2, Z: 3}},
+	} {
+		if got := math3d.ClosestPointOnAABB(tt.p, b); got != tt.want {
+			t.Errorf("ClosestPointOnAABB(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// BVH is a bounding volume hierarchy over a fixed set of triangles or
+// boxes for fast ray casting. It is built once with the surface area
+// heuristic and is not changed by queries, so it may be searched from
+// several goroutines at once.
+type BVH struct {
+	nodes     []bvhNode
+	order     []int  // primitive indexes, arranged so each leaf is a range
+	bounds    []AABB // the bounds of each primitive
+	centers   []Point
+	triangles []Triangle // nil when built from boxes
+}
+
+// bvhNode is a node of the flattened tree. A leaf holds the primitives
+// order[first:first+count]; an inner node has count 0 and its children
+// at nodes[first] and nodes[first+1].
+type bvhNode struct {
+	bounds       AABB
+	first, count int
+}
+
+// BVHHit describes the nearest primitive that a ray hits: its Index in
+// the slice the BVH was built from, and the parameter T of the hit along
+// the ray. For triangles, Barycentric holds the weights of the hit point
+// with respect to the corners A, B, and C; for boxes it is zero.
+type BVHHit struct {
+	Index       int
+	T           float64
+	Barycentric Vec3
+}
+
+const (
+	// bvhLeafSize is the largest number of primitives kept in a leaf when
+	// splitting it would cost more.
+	bvhLeafSize = 4
+	// bvhBins is the number of candidate split positions per node.
+	bvhBins = 12
+)
+
+// BVHFromAABBs builds a BVH over the boxes. Rays hit a box where they
+// enter it, or at their origin when it is inside the box.
+func BVHFromAABBs(boxes []AABB) *BVH {
+	b := &BVH{bounds: append([]AABB(nil), boxes...)}
+	b.build()
+	return b
+}
+
+// BVHFromTriangles builds a BVH over the triangles. Rays hit them as in
+// Ray.IntersectTriangle.
+func BVHFromTriangles(triangles []Triangle) *BVH {
+	b := &BVH{triangles: append([]Triangle(nil), triangles...), bounds: make([]AABB, len(triangles))}
+	for i, tr := range triangles {
+		b.bounds[i], _ = AABBFromPoints(tr.A, tr.B, tr.C)
+	}
+	b.build()
+	return b
+}
+
+// Bounds returns the box that holds every primitive.
+// It returns an empty box if there are none.
+func (b *BVH) Bounds() AABB {
+	if len(b.nodes) == 0 {
+		return EmptyAABB()
+	}
+	return b.nodes[0].bounds
+}
+
+// Raycast returns the nearest primitive that the ray hits at or after
+// its origin. It returns false if the ray hits nothing.
+func (b *BVH) Raycast(r Ray) (BVHHit, bool) {
+	best := BVHHit{Index: -1}
+	if len(b.nodes) == 0 {
+		return best, false
+	}
+	if _, ok := r.intersectAABB(b.nodes[0].bounds); !ok {
+		return best, false
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if t, ok := r.intersectAABB(n.bounds); !ok || (best.Index >= 0 && t > best.T) {
+			continue
+		}
+		if n.count > 0 {
+			for _, i := range b.order[n.first : n.first+n.count] {
+				if h, ok := b.intersect(r, i); ok && (best.Index < 0 || h.T < best.T) {
+					best = h
+				}
+			}
+			continue
+		}
+		// visit the nearer child first by pushing it last
+		t0, ok0 := r.intersectAABB(b.nodes[n.first].bounds)
+		t1, ok1 := r.intersectAABB(b.nodes[n.first+1].bounds)
+		near, far := n.first, n.first+1
+		if ok1 && (!ok0 || t1 < t0) {
+			near, far = far, near
+			ok0, ok1 = ok1, ok0
+		}
+		if ok1 {
+			stack = append(stack, far)
+		}
+		if ok0 {
+			stack = append(stack, near)
+		}
+	}
+	return best, best.Index >= 0
+}
+
+// RaycastBatch casts each of the rays as in Raycast, spreading the work
+// over the available processors. The hit for a ray that misses has an
+// Index of −1.
+func (b *BVH) RaycastBatch(rays []Ray) []BVHHit {
+	hits := make([]BVHHit, len(rays))
+	workers := runtime.GOMAXPROCS(0)
+	chunk := (len(rays) + workers - 1) / workers
+	if chunk < 64 {
+		chunk = 64
+	}
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(rays); lo += chunk {
+		hi := lo + chunk
+		if hi > len(rays) {
+			hi = len(rays)
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				hits[i], _ = b.Raycast(rays[i])
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+	return hits
+}
+
+// build creates the tree over b.bounds.
+func (b *BVH) build() {
+	if len(b.bounds) == 0 {
+		return
+	}
+	b.order = make([]int, len(b.bounds))
+	b.centers = make([]Point, len(b.bounds))
+	for i, box := range b.bounds {
+		b.order[i] = i
+		b.centers[i] = box.Center()
+	}
+	b.nodes = make([]bvhNode, 1, 2*len(b.bounds))
+	b.split(0, 0, len(b.order))
+	b.centers = nil
+}
+
+// split makes node n hold order[lo:hi], dividing it in two where the
+// surface area heuristic estimates that rays will test the fewest
+// primitives.
+func (b *BVH) split(n, lo, hi int) {
+	bounds, cbounds := EmptyAABB(), EmptyAABB()
+	for _, i := range b.order[lo:hi] {
+		bounds = bounds.Union(b.bounds[i])
+		cbounds = cbounds.Include(b.centers[i])
+	}
+	b.nodes[n] = bvhNode{bounds: bounds, first: lo, count: hi - lo}
+	count := hi - lo
+	if count <= 1 {
+		return
+	}
+
+	// sort the centers into bins along the axis on which they spread most
+	size := cbounds.Size()
+	axis := 0
+	if size.Y > size.X && size.Y >= size.Z {
+		axis = 1
+	} else if size.Z > size.X && size.Z > size.Y {
+		axis = 2
+	}
+	lo0, extent := pointAxis(cbounds.Min, axis), pointAxis(cbounds.Max, axis)-pointAxis(cbounds.Min, axis)
+	bin := func(i int) int {
+		k := int(bvhBins * (pointAxis(b.centers[i], axis) - lo0) / extent)
+		if k >= bvhBins {
+			k = bvhBins - 1
+		}
+		return k
+	}
+
+	mid := -1
+	if extent > 0 {
+		var boxes [bvhBins]AABB
+		var counts [bvhBins]int
+		for k := range boxes {
+			boxes[k] = EmptyAABB()
+		}
+		for _, i := range b.order[lo:hi] {
+			k := bin(i)
+			boxes[k] = boxes[k].Union(b.bounds[i])
+			counts[k]++
+		}
+		// the cost of splitting after each bin, from sweeps in both directions
+		var right [bvhBins]float64
+		acc, nacc := EmptyAABB(), 0
+		for k := bvhBins - 1; k > 0; k-- {
+			acc, nacc = acc.Union(boxes[k]), nacc+counts[k]
+			right[k-1] = acc.SurfaceArea() * float64(nacc)
+		}
+		best, bestCost := -1, bounds.SurfaceArea()*float64(count) // the cost of a leaf
+		acc, nacc = EmptyAABB(), 0
+		for k := 0; k < bvhBins-1; k++ {
+			acc, nacc = acc.Union(boxes[k]), nacc+counts[k]
+			if cost := acc.SurfaceArea()*float64(nacc) + right[k]; nacc > 0 && nacc < count && cost < bestCost {
+				best, bestCost = k, cost
+			}
+		}
+		if best < 0 && count <= bvhLeafSize {
+			return
+		}
+		if best >= 0 {
+			// partition the primitives by bin
+			sub := b.order[lo:hi]
+			j := 0
+			for k, i := range sub {
+				if bin(i) <= best {
+					sub[j], sub[k] = sub[k], sub[j]
+					j++
+				}
+			}
+			mid = lo + j
+		}
+	} else if count <= bvhLeafSize {
+		return
+	}
+	if mid < 0 {
+		// no useful split: halve the primitives by their centers
+		sub := b.order[lo:hi]
+		sort.Slice(sub, func(x, y int) bool {
+			return pointAxis(b.centers[sub[x]], axis) < pointAxis(b.centers[sub[y]], axis)
+		})
+		mid = lo + count/2
+	}
+
+	first := len(b.nodes)
+	b.nodes = append(b.nodes, bvhNode{}, bvhNode{})
+	b.nodes[n] = bvhNode{bounds: bounds, first: first}
+	b.split(first, lo, mid)
+	b.split(first+1, mid, hi)
+}
+
+// intersect tests the ray against primitive i.
+func (b *BVH) intersect(r Ray, i int) (BVHHit, bool) {
+	if b.triangles == nil {
+		t, ok := r.intersectAABB(b.bounds[i])
+		return BVHHit{Index: i, T: t}, ok
+	}
+	h, ok := r.IntersectTriangle(b.triangles[i])
+	return BVHHit{Index: i, T: h.T, Barycentric: h.Barycentric}, ok
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"testing"
+)
+
+func TestBVH(t *testing.T) {
+	rng := rand.New(rand.NewSource(839))
+	random := func(scale float64) math3d.Point {
+		return math3d.Point{X: (rng.Float64()*2 - 1) * scale, Y: (rng.Float64()*2 - 1) * scale, Z: (rng.Float64()*2 - 1) * scale}
+	}
+	triangles := make([]math3d.Triangle, 300)
+	boxes := make([]math3d.AABB, 300)
+	for i := range triangles {
+		c := random(10).Vec3()
+		triangles[i] = math3d.Triangle{A: random(1).Add(c), B: random(1).Add(c), C: random(1).Add(c)}
+		boxes[i] = math3d.AABB{Min: c.Point(), Max: c.Point()}.Expand(rng.Float64() * 0.3)
+	}
+	tris, bvhBoxes := math3d.BVHFromTriangles(triangles), math3d.BVHFromAABBs(boxes)
+	want := math3d.EmptyAABB()
+	for _, tr := range triangles {
+		want = want.Include(tr.A).Include(tr.B).Include(tr.C)
+	}
+	if got := tris.Bounds(); got != want {
+		t.Errorf("Bounds: want %v, got %v\n", want, got)
+	}
+
+	rays := make([]math3d.Ray, 500)
+	for i := range rays {
+		rays[i] = math3d.Ray{Origin: random(15), Direction: random(1).Vec3()}
+		if i%2 == 0 {
+			// aim half of them at the middle, where they hit something
+			rays[i].Direction = math3d.Point{}.Sub(rays[i].Origin).Add(random(3).Vec3())
+		}
+	}
+	batch := tris.RaycastBatch(rays)
+	hits := 0
+	for i, r := range rays {
+		want, wantOK := math3d.BVHHit{Index: -1}, false
+		for j, tr := range triangles {
+			if h, ok := r.IntersectTriangle(tr); ok && (!wantOK || h.T < want.T) {
+				want, wantOK = math3d.BVHHit{Index: j, T: h.T, Barycentric: h.Barycentric}, true
+			}
+		}
+		got, ok := tris.Raycast(r)
+		if ok != wantOK || got != want {
+			t.Errorf("Raycast(%v): triangles: want %v %v, got %v %v\n", r, want, wantOK, got, ok)
+		}
+		if batch[i] != want {
+			t.Errorf("RaycastBatch: %d: want %v, got %v\n", i, want, batch[i])
+		}
+		if ok {
+			hits++
+		}
+
+		want, wantOK = math3d.BVHHit{Index: -1}, false
+		for j, b := range boxes {
+			if enter, ok := rayEnterAABB(r, b); ok && (!wantOK || enter < want.T) {
+				want, wantOK = math3d.BVHHit{Index: j, T: enter}, true
+			}
+		}
+		if got, ok := bvhBoxes.Raycast(r); ok != wantOK || got.Index != want.Index {
+			t.Errorf("Raycast(%v): boxes: want %v %v, got %v %v\n", r, want, wantOK, got, ok)
+		}
+	}
+	if hits < 50 {
+		t.Errorf("Raycast: want most rays aimed at the middle to hit, got %d hits\n", hits)
+	}
+
+	if _, ok := math3d.BVHFromTriangles(nil).Raycast(rays[0]); ok {
+		t.Errorf("Raycast: empty: want !ok, got ok\n")
+	}
+}
+
+// rayEnterAABB returns the parameter where the ray enters the box,
+// found by clipping the ray against each slab in turn.
+func rayEnterAABB(r math3d.Ray, b math3d.AABB) (float64, bool) {
+	tmin, tmax := 0.0, 1e300
+	o := []float64{r.Origin.X, r.Origin.Y, r.Origin.Z}
+	d := []float64{r.Direction.X, r.Direction.Y, r.Direction.Z}
+	lo := []float64{b.Min.X, b.Min.Y, b.Min.Z}
+	hi := []float64{b.Max.X, b.Max.Y, b.Max.Z}
+	for i := range o {
+		t0, t1 := (lo[i]-o[i])/d[i], (hi[i]-o[i])/d[i]
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t0 > tmin {
+			tmin = t0
+		}
+		if t1 < tmax {
+			tmax = t1
+		}
+	}
+	return tmin, tmin <= tmax
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Camera holds the position, orientation, and projection parameters
+// needed to map between world space and normalized device coordinates.
+type Camera struct {
+	// Position is the location of the camera in world space.
+	Position Point
+	// Forward is the viewing direction and Up is the approximate upward
+	// direction. They need not be normalized but must not be parallel.
+	Forward, Up Vec3
+
+	// Orthographic selects an orthographic projection whose view volume
+	// is Height units tall. Otherwise the projection is a perspective
+	// with the vertical field of view FovY in radians.
+	Orthographic bool
+	FovY         float64
+	Height       float64
+	// Aspect is the aspect ratio of the view (width over height).
+	Aspect float64
+	// Near and Far are the distances to the clipping planes.
+	// Far may be math.Inf(1) for perspective projections.
+	Near, Far float64
+	// Clip is the clip-space convention used by the projection.
+	Clip ClipSpace
+}
+
+// NewPerspectiveCamera returns a camera at position looking towards target.
+func NewPerspectiveCamera(position, target Point, up Vec3, fovy, aspect, near, far float64) *Camera {
+	return &Camera{
+		Position: position,
+		Forward:  target.Sub(position),
+		Up:       up,
+		FovY:     fovy,
+		Aspect:   aspect,
+		Near:     near,
+		Far:      far,
+	}
+}
+
+// NewOrthographicCamera returns a camera at position looking towards target
+// with a view volume height units tall.
+func NewOrthographicCamera(position, target Point, up Vec3, height, aspect, near, far float64) *Camera {
+	return &Camera{
+		Position:     position,
+		Forward:      target.Sub(position),
+		Up:           up,
+		Orthographic: true,
+		Height:       height,
+		Aspect:       aspect,
+		Near:         near,
+		Far:          far,
+	}
+}
+
+// LookAt turns the camera to face target.
+func (c *Camera) LookAt(target Point) {
+	c.Forward = target.Sub(c.Position)
+}
+
+// PickRay returns the world-space ray through the given normalized
+// device coordinates. See PickRay.
+func (c *Camera) PickRay(ndc Vec2) (Ray, bool) {
+	return PickRay(ndc, c.ViewMatrix(), c.ProjectionMatrix(), c.Clip)
+}
+
+// Project maps a point in world space to normalized device coordinates.
+// Visible points have x and y in [−1, 1] and a depth within the range
+// selected by the camera's clip-space convention.
+func (c *Camera) Project(world Point) Point {
+	return c.ViewProjectionMatrix().TransformPoint(world)
+}
+
+// ProjectionMatrix returns the transform from view space to clip space.
+func (c *Camera) ProjectionMatrix() Mat4 {
+	if c.Orthographic {
+		h := c.Height / 2
+		w := h * c.Aspect
+		return OrthoClip(-w, w, -h, h, c.Near, c.Far, c.Clip)
+	}
+	return PerspectiveClip(c.FovY, c.Aspect, c.Near, c.Far, c.Clip)
+}
+
+// Unproject maps normalized device coordinates and a depth back to a
+// point in world space. It is the inverse of Project. It returns false
+// if the view-projection transform is singular, as it is when Near
+// equals Far, Aspect is 0, or Forward is the zero vector, or if the
+// point maps to infinity, as the far plane of an infinite projection
+// does.
+func (c *Camera) Unproject(ndc Vec2, depth float64) (Point, bool) {
+	inv, ok := c.ViewProjectionMatrix().Inverse()
+	if !ok {
+		return Point{}, false
+	}
+	p := inv.MulVec4(Vec4{X: ndc.X, Y: ndc.Y, Z: depth, W: 1})
+	if p.W == 0 {
+		return Point{}, false
+	}
+	world := Point{X: p.X / p.W, Y: p.Y / p.W, Z: p.Z / p.W}
+	if !world.IsFinite() {
+		return Point{}, false
+	}
+	return world, true
+}
+
+// ViewMatrix returns the transform from world space to view space.
+func (c *Camera) ViewMatrix() Mat4 {
+	return LookAt(c.Position, c.Position.Add(c.Forward), c.Up)
+}
+
+// ViewProjectionMatrix returns the transform from world space to clip space.
+func (c *Camera) ViewProjectionMatrix() Mat4 {
+	return c.ProjectionMatrix().Mul(c.ViewMatrix())
+}
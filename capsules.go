@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Capsule is the set of points within Radius of the segment, a cylinder
+// with hemispherical caps.
+type Capsule struct {
+	Segment Segment
+	Radius  float64
+}
+
+// ContainsPoint reports whether p is inside the capsule or on its surface.
+func (c Capsule) ContainsPoint(p Point) bool {
+	return p.Sub(c.Segment.ClosestPointTo(p)).LengthSquared() <= c.Radius*c.Radius
+}
+
+// DistanceTo returns the distance from the surface of the capsule to p.
+// It is negative when p is inside the capsule.
+func (c Capsule) DistanceTo(p Point) float64 {
+	return c.Segment.DistanceTo(p) - c.Radius
+}
+
+// IntersectsCapsule reports whether the two capsules overlap or touch.
+func (c Capsule) IntersectsCapsule(c2 Capsule) bool {
+	p1, p2, _, _ := ClosestPointsSegmentSegment(c.Segment, c2.Segment)
+	r := c.Radius + c2.Radius
+	return p1.Sub(p2).LengthSquared() <= r*r
+}
+
+// IntersectsSphere reports whether the capsule and the sphere overlap or touch.
+func (c Capsule) IntersectsSphere(s Sphere) bool {
+	r := c.Radius + s.Radius
+	return s.Center.Sub(c.Segment.ClosestPointTo(s.Center)).LengthSquared() <= r*r
+}
+
+// IntersectsTriangle reports whether the capsule and the triangle
+// overlap or touch.
+func (c Capsule) IntersectsTriangle(tr Triangle) bool {
+	return segmentTriangleDistanceSquared(c.Segment, tr) <= c.Radius*c.Radius
+}
+
+// Support returns the point of the capsule furthest in the direction d.
+// It implements the ConvexShape interface.
+func (c Capsule) Support(d Vec3) Point {
+	return Sphere{Center: c.Segment.Support(d), Radius: c.Radius}.Support(d)
+}
+
+// segmentTriangleDistanceSquared returns the squared distance between
+// the nearest points of the segment and the triangle. If the segment
+// does not pierce the triangle, the nearest points are either an end
+// point of the segment and its nearest point on the triangle, or the
+// nearest points of the segment and an edge of the triangle.
+func segmentTriangleDistanceSquared(s Segment, tr Triangle) float64 {
+	if pl, ok := tr.Plane(); ok {
+		da, db := pl.SignedDistance(s.A), pl.SignedDistance(s.B)
+		if (da <= 0 && db >= 0) || (da >= 0 && db <= 0) {
+			p := s.A
+			if da != db {
+				p = s.PointAt(da / (da - db))
+			}
+			if tr.ContainsPoint(p) {
+				return 0
+			}
+		}
+	}
+	best := math.Min(s.A.Sub(tr.ClosestPointTo(s.A)).LengthSquared(), s.B.Sub(tr.ClosestPointTo(s.B)).LengthSquared())
+	for _, edge := range []Segment{{A: tr.A, B: tr.B}, {A: tr.B, B: tr.C}, {A: tr.C, B: tr.A}} {
+		p1, p2, _, _ := ClosestPointsSegmentSegment(s, edge)
+		best = math.Min(best, p1.Sub(p2).LengthSquared())
+	}
+	return best
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestCapsule(t *testing.T) {
+	c := math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 0, Y: 4, Z: 0}}, Radius: 1}
+	for _, tt := range []struct {
+		p      math3d.Point
+		dist   float64
+		inside bool
+	}{
+		{math3d.Point{X: 0, Y: 2, Z: 0}, -1, true},
+		{math3d.Point{X: 3, Y: 2, Z: 0}, 2, false},
+		{math3d.Point{X: 0, Y: -2, Z: 0}, 1, false},
+		{math3d.Point{X: 0, Y: 4.5, Z: 0.5}, math.Sqrt(0.5) - 1, true},
+	} {
+		if got := c.DistanceTo(tt.p); math.Abs(got-tt.dist) > 1e-12 {
+			t.Errorf("DistanceTo(%v): want %v, got %v\n", tt.p, tt.dist, got)
+		}
+		if got := c.ContainsPoint(tt.p); got != tt.inside {
+			t.Errorf("ContainsPoint(%v): want %v, got %v\n", tt.p, tt.inside, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		s    math3d.Sphere
+		want bool
+	}{
+		{math3d.Sphere{Center: math3d.Point{X: 2.5, Y: 1, Z: 0}, Radius: 1.5}, true},
+		{math3d.Sphere{Center: math3d.Point{X: 2.5, Y: 1, Z: 0}, Radius: 1.4}, false},
+		{math3d.Sphere{Center: math3d.Point{X: 0, Y: 6, Z: 0}, Radius: 1.5}, true},
+	} {
+		if got := c.IntersectsSphere(tt.s); got != tt.want {
+			t.Errorf("IntersectsSphere(%v): want %v, got %v\n", tt.s, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		c2   math3d.Capsule
+		want bool
+	}{
+		{"crossing", math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{X: -3, Y: 2, Z: 1.9}, B: math3d.Point{X: 3, Y: 2, Z: 1.9}}, Radius: 1}, true},
+		{"crossing apart", math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{X: -3, Y: 2, Z: 2.1}, B: math3d.Point{X: 3, Y: 2, Z: 2.1}}, Radius: 1}, false},
+		{"parallel", math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{X: 1.5, Y: 1}, B: math3d.Point{X: 1.5, Y: 9}}, Radius: 0.5}, true},
+		{"end to end", math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{Y: 6.5}, B: math3d.Point{Y: 9}}, Radius: 1}, false},
+	} {
+		if got := c.IntersectsCapsule(tt.c2); got != tt.want {
+			t.Errorf("IntersectsCapsule: %s: want %v, got %v\n", tt.name, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		tr   math3d.Triangle
+		want bool
+	}{
+		{"pierced", math3d.Triangle{A: math3d.Point{X: -5, Y: 2, Z: -5}, B: math3d.Point{X: 5, Y: 2, Z: -5}, C: math3d.Point{X: 0, Y: 2, Z: 5}}, true},
+		{"near face", math3d.Triangle{A: math3d.Point{X: 0.9, Y: -5, Z: -5}, B: math3d.Point{X: 0.9, Y: 9, Z: -5}, C: math3d.Point{X: 0.9, Y: 0, Z: 5}}, true},
+		{"far face", math3d.Triangle{A: math3d.Point{X: 1.1, Y: -5, Z: -5}, B: math3d.Point{X: 1.1, Y: 9, Z: -5}, C: math3d.Point{X: 1.1, Y: 0, Z: 5}}, false},
+		{"near edge", math3d.Triangle{A: math3d.Point{X: -5, Y: 2, Z: 0.9}, B: math3d.Point{X: 5, Y: 2, Z: 0.9}, C: math3d.Point{X: 0, Y: 2, Z: 9}}, true},
+		{"far edge", math3d.Triangle{A: math3d.Point{X: -5, Y: 2, Z: 1.1}, B: math3d.Point{X: 5, Y: 2, Z: 1.1}, C: math3d.Point{X: 0, Y: 2, Z: 9}}, false},
+		{"beyond cap", math3d.Triangle{A: math3d.Point{X: -5, Y: 5.1, Z: -5}, B: math3d.Point{X: 5, Y: 5.1, Z: -5}, C: math3d.Point{X: 0, Y: 5.1, Z: 5}}, false},
+	} {
+		if got := c.IntersectsTriangle(tt.tr); got != tt.want {
+			t.Errorf("IntersectsTriangle: %s: want %v, got %v\n", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// ApproxEqual reports whether a and b differ by no more than epsilon.
+func ApproxEqual(a, b, epsilon float64) bool {
+	return a == b || math.Abs(a-b) <= epsilon
+}
+
+// ApproxEqualTol reports whether a and b are equal within an absolute
+// tolerance or a tolerance relative to the larger of their magnitudes.
+// The absolute tolerance handles values near zero, where a relative
+// tolerance is too strict to be useful.
+//
+//	|a − b| ≤ max(absTol, relTol * max(|a|, |b|))
+func ApproxEqualTol(a, b, absTol, relTol float64) bool {
+	if a == b {
+		return true
+	}
+	diff := math.Abs(a - b)
+	if diff <= absTol {
+		return true
+	}
+	return diff <= relTol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// EqualWithinULP reports whether a and b are no more than ulps
+// representable float64 values apart. Positive and negative zero
+// are equal and NaN is never equal to anything.
+func EqualWithinULP(a, b float64, ulps uint64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return false
+	}
+	return ULPDistance(a, b) <= ulps
+}
+
+// ULPDistance returns the number of representable float64 values
+// between a and b. The result is meaningless if either is NaN.
+func ULPDistance(a, b float64) uint64 {
+	ia, ib := orderedBits(a), orderedBits(b)
+	if ia < ib {
+		ia, ib = ib, ia
+	}
+	return uint64(ia) - uint64(ib)
+}
+
+// orderedBits maps the bits of f onto an integer that sorts in
+// the same order as f, with adjacent floats mapping to adjacent
+// integers and both zeros mapping to 0.
+func orderedBits(f float64) int64 {
+	b := int64(math.Float64bits(f))
+	if b < 0 {
+		return math.MinInt64 - b
+	}
+	return b
+}
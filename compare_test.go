@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestApproxEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a, b, absTol, relTol float64
+		expect               bool
+	}{
+		{1, 1, 0, 0, true},
+		{1, 1 + 1e-10, 1e-9, 0, true},
+		{1, 1 + 1e-8, 1e-9, 0, false},
+		{1e10, 1e10 + 1, 1e-9, 1e-9, true},
+		{1e10, 1e10 + 100, 1e-9, 1e-9, false},
+		{0, 1e-12, 1e-9, 1e-9, true},
+		{math.Inf(1), math.Inf(1), 0, 0, true},
+		{math.NaN(), math.NaN(), 1, 1, false},
+	} {
+		if ok := math3d.ApproxEqualTol(tt.a, tt.b, tt.absTol, tt.relTol); ok != tt.expect {
+			t.Errorf("ApproxEqualTol(%g, %g): want %v, got %v\n", tt.a, tt.b, tt.expect, ok)
+		}
+	}
+
+	x, y := 0.1, 0.2
+	v := math3d.NewVec3(x, y, 0.3)
+	w := math3d.NewVec3(x, y, x+y)
+	if !v.ApproxEqual(w, 1e-15) {
+		t.Errorf("Vec3.ApproxEqual: want true, got false\n")
+	}
+	if math3d.NewVector(1, 2).ApproxEqual(math3d.NewVector(1, 2, 3), 1) {
+		t.Errorf("Vector.ApproxEqual: length mismatch: want false, got true\n")
+	}
+	p := math3d.Point{X: 1, Y: 2, Z: 3}
+	if !p.ApproxEqual(math3d.Point{X: 1, Y: 2, Z: 3.0001}, 0.001) {
+		t.Errorf("Point.ApproxEqual: want true, got false\n")
+	}
+}
+
+func TestEqualWithinULP(t *testing.T) {
+	one := 1.0
+	next := math.Nextafter(one, 2)
+	for _, tt := range []struct {
+		a, b   float64
+		expect uint64
+	}{
+		{one, one, 0},
+		{one, next, 1},
+		{next, one, 1},
+		{0, math.Copysign(0, -1), 0},
+		{math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64, 2},
+		{one, math.Nextafter(math.Nextafter(next, 2), 2), 3},
+	} {
+		if d := math3d.ULPDistance(tt.a, tt.b); d != tt.expect {
+			t.Errorf("ULPDistance(%g, %g): want %d, got %d\n", tt.a, tt.b, tt.expect, d)
+		}
+	}
+	if math3d.EqualWithinULP(math.NaN(), math.NaN(), math.MaxUint64) {
+		t.Errorf("EqualWithinULP: NaN: want false, got true\n")
+	}
+	v := math3d.NewVec3(1, 2, 3)
+	if !v.EqualWithinULP(math3d.NewVec3(next, 2, 3), 1) {
+		t.Errorf("Vec3.EqualWithinULP: want true, got false\n")
+	}
+	if v.EqualWithinULP(math3d.NewVec3(next, 2, 3), 0) {
+		t.Errorf("Vec3.EqualWithinULP: want false, got true\n")
+	}
+}
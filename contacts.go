@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Contact describes how two overlapping shapes penetrate each other.
+// Normal is a unit vector pointing from the first shape towards the
+// second, and Depth is how far the second shape must move along Normal
+// for the shapes to just touch.
+type Contact struct {
+	Normal Vec3
+	Depth  float64
+}
+
+// The Intersects functions report whether two shapes overlap or touch.
+// When they do, they also return the contact that would separate them
+// along the shortest direction. When the direction is ambiguous, as for
+// two spheres with the same center, an arbitrary axis is chosen.
+
+// IntersectsAABBAABB reports whether the boxes overlap. The contact
+// normal is the axis along which they overlap the least.
+func IntersectsAABBAABB(a, b AABB) (Contact, bool) {
+	lo, hi := a.Min.Max(b.Min), a.Max.Min(b.Max)
+	overlap := [3]float64{hi.X - lo.X, hi.Y - lo.Y, hi.Z - lo.Z}
+	offset := b.Center().Sub(a.Center())
+	side := [3]float64{offset.X, offset.Y, offset.Z}
+	axis := 0
+	for i, o := range overlap {
+		if o < 0 {
+			return Contact{}, false
+		}
+		if o < overlap[axis] {
+			axis = i
+		}
+	}
+	return Contact{Normal: signedAxis(axis, side[axis]), Depth: overlap[axis]}, true
+}
+
+// IntersectsCapsuleCapsule reports whether the capsules overlap.
+func IntersectsCapsuleCapsule(a, b Capsule) (Contact, bool) {
+	c1, c2, _, _ := ClosestPointsSegmentSegment(a.Segment, b.Segment)
+	d := c2.Sub(c1)
+	r := a.Radius + b.Radius
+	dist2 := d.LengthSquared()
+	if dist2 > r*r {
+		return Contact{}, false
+	}
+	if dist2 == 0 {
+		// the axes cross; separate perpendicular to both if possible
+		u := a.Segment.B.Sub(a.Segment.A)
+		n, ok := u.Cross(b.Segment.B.Sub(b.Segment.A)).Normalized()
+		if !ok {
+			if n, ok = u.anyPerpendicular().Normalized(); !ok {
+				n = Vec3{X: 1}
+			}
+		}
+		return Contact{Normal: n, Depth: r}, true
+	}
+	dist := math.Sqrt(dist2)
+	return Contact{Normal: d.Div(dist), Depth: r - dist}, true
+}
+
+// IntersectsOBBOBB reports whether the boxes overlap. It tests the same
+// fifteen separating axes as OBB.IntersectsOBB, and the contact normal
+// is the axis along which the boxes overlap the least.
+func IntersectsOBBOBB(a, b OBB) (Contact, bool) {
+	axes := make([]Vec3, 0, 15)
+	for i := 0; i < 3; i++ {
+		axes = append(axes, a.Orientation.Col(i), b.Orientation.Col(i))
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			// edges that are nearly parallel add no new axis
+			n := a.Orientation.Col(i).Cross(b.Orientation.Col(j))
+			if l2 := n.LengthSquared(); l2 > 1e-12 {
+				axes = append(axes, n.Div(math.Sqrt(l2)))
+			}
+		}
+	}
+	t := b.Center.Sub(a.Center)
+	best := Contact{Depth: math.Inf(1)}
+	for _, n := range axes {
+		dist := t.Dot(n)
+		overlap := a.projectedRadius(n) + b.projectedRadius(n) - math.Abs(dist)
+		if overlap < 0 {
+			return Contact{}, false
+		}
+		if overlap < best.Depth {
+			if dist < 0 {
+				n = n.Mul(-1)
+			}
+			best = Contact{Normal: n, Depth: overlap}
+		}
+	}
+	return best, true
+}
+
+// IntersectsSphereAABB reports whether the sphere and the box overlap.
+func IntersectsSphereAABB(s Sphere, b AABB) (Contact, bool) {
+	q := b.ClosestPointTo(s.Center)
+	d := q.Sub(s.Center)
+	dist2 := d.LengthSquared()
+	if dist2 > s.Radius*s.Radius {
+		return Contact{}, false
+	}
+	if dist2 > 0 {
+		dist := math.Sqrt(dist2)
+		return Contact{Normal: d.Div(dist), Depth: s.Radius - dist}, true
+	}
+	// the center is inside the box; push the box off the nearest face
+	c := [3]float64{s.Center.X, s.Center.Y, s.Center.Z}
+	lo, hi := [3]float64{b.Min.X, b.Min.Y, b.Min.Z}, [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+	axis, face, nearest := 0, 1.0, math.Inf(1)
+	for i := range c {
+		if d := hi[i] - c[i]; d < nearest {
+			axis, face, nearest = i, -1, d
+		}
+		if d := c[i] - lo[i]; d < nearest {
+			axis, face, nearest = i, 1, d
+		}
+	}
+	return Contact{Normal: signedAxis(axis, face), Depth: s.Radius + nearest}, true
+}
+
+// IntersectsSphereOBB reports whether the sphere and the box overlap.
+func IntersectsSphereOBB(s Sphere, o OBB) (Contact, bool) {
+	// work in the frame of the box, where it is an AABB at the origin
+	local := Sphere{Center: o.Orientation.Transpose().MulVec3(s.Center.Sub(o.Center)).Point(), Radius: s.Radius}
+	e := o.HalfExtents
+	c, ok := IntersectsSphereAABB(local, AABB{Min: e.Mul(-1).Point(), Max: e.Point()})
+	if !ok {
+		return Contact{}, false
+	}
+	c.Normal = o.Orientation.MulVec3(c.Normal)
+	return c, true
+}
+
+// IntersectsSphereSphere reports whether the spheres overlap.
+func IntersectsSphereSphere(a, b Sphere) (Contact, bool) {
+	d := b.Center.Sub(a.Center)
+	r := a.Radius + b.Radius
+	dist2 := d.LengthSquared()
+	if dist2 > r*r {
+		return Contact{}, false
+	}
+	if dist2 == 0 {
+		return Contact{Normal: Vec3{X: 1}, Depth: r}, true
+	}
+	dist := math.Sqrt(dist2)
+	return Contact{Normal: d.Div(dist), Depth: r - dist}, true
+}
+
+// signedAxis returns the unit vector along the coordinate axis with the
+// given index, pointing in the direction of the sign of s.
+func signedAxis(axis int, s float64) Vec3 {
+	var n [3]float64
+	n[axis] = math.Copysign(1, s)
+	return Vec3{X: n[0], Y: n[1], Z: n[2]}
+}
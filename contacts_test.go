@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestIntersects(t *testing.T) {
+	unit := func(c math3d.Point, r float64) math3d.Sphere { return math3d.Sphere{Center: c, Radius: r} }
+	box := func(x0, y0, z0, x1, y1, z1 float64) math3d.AABB {
+		return math3d.AABB{Min: math3d.Point{X: x0, Y: y0, Z: z0}, Max: math3d.Point{X: x1, Y: y1, Z: z1}}
+	}
+	for _, tt := range []struct {
+		name string
+		got  func() (math3d.Contact, bool)
+		ok   bool
+		want math3d.Contact
+	}{
+		{"sphere sphere", func() (math3d.Contact, bool) {
+			return math3d.IntersectsSphereSphere(unit(math3d.Point{}, 1), unit(math3d.Point{Y: 1.5}, 1))
+		}, true, math3d.Contact{Normal: math3d.NewVec3(0, 1, 0), Depth: 0.5}},
+		{"sphere sphere apart", func() (math3d.Contact, bool) {
+			return math3d.IntersectsSphereSphere(unit(math3d.Point{}, 1), unit(math3d.Point{Y: 2.5}, 1))
+		}, false, math3d.Contact{}},
+		{"sphere aabb outside", func() (math3d.Contact, bool) {
+			return math3d.IntersectsSphereAABB(unit(math3d.Point{X: -0.5, Y: 0.5, Z: 0.5}, 1), box(0, 0, 0, 1, 1, 1))
+		}, true, math3d.Contact{Normal: math3d.NewVec3(1, 0, 0), Depth: 0.5}},
+		{"sphere aabb inside", func() (math3d.Contact, bool) {
+			return math3d.IntersectsSphereAABB(unit(math3d.Point{X: 0.5, Y: 0.5, Z: 0.9}, 0.25), box(0, 0, 0, 1, 1, 1))
+		}, true, math3d.Contact{Normal: math3d.NewVec3(0, 0, -1), Depth: 0.35}},
+		{"aabb aabb", func() (math3d.Contact, bool) {
+			return math3d.IntersectsAABBAABB(box(0, 0, 0, 2, 2, 2), box(-1.8, 1, 1, 0.2, 3, 3))
+		}, true, math3d.Contact{Normal: math3d.NewVec3(-1, 0, 0), Depth: 0.2}},
+		{"aabb aabb apart", func() (math3d.Contact, bool) {
+			return math3d.IntersectsAABBAABB(box(0, 0, 0, 1, 1, 1), box(0, 0, 1.5, 1, 1, 2))
+		}, false, math3d.Contact{}},
+		{"sphere obb", func() (math3d.Contact, bool) {
+			o := math3d.OBB{HalfExtents: math3d.NewVec3(1, 1, 1), Orientation: math3d.Mat3FromRotationZ(math.Pi / 4)}
+			return math3d.IntersectsSphereOBB(unit(math3d.Point{X: 2}, 1), o)
+		}, true, math3d.Contact{Normal: math3d.NewVec3(-1, 0, 0), Depth: math.Sqrt2 - 1}},
+		{"obb obb", func() (math3d.Contact, bool) {
+			a := math3d.OBB{HalfExtents: math3d.NewVec3(1, 1, 1), Orientation: math3d.Identity3()}
+			b := math3d.OBB{Center: math3d.Point{Y: 1.9}, HalfExtents: math3d.NewVec3(1, 1, 1), Orientation: math3d.Mat3FromRotationY(0.3)}
+			return math3d.IntersectsOBBOBB(a, b)
+		}, true, math3d.Contact{Normal: math3d.NewVec3(0, 1, 0), Depth: 0.1}},
+		{"capsule capsule", func() (math3d.Contact, bool) {
+			a := math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{X: -1}, B: math3d.Point{X: 1}}, Radius: 0.5}
+			b := math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{Y: -1, Z: 0.75}, B: math3d.Point{Y: 1, Z: 0.75}}, Radius: 0.5}
+			return math3d.IntersectsCapsuleCapsule(a, b)
+		}, true, math3d.Contact{Normal: math3d.NewVec3(0, 0, 1), Depth: 0.25}},
+		{"capsule capsule crossing", func() (math3d.Contact, bool) {
+			a := math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{X: -1}, B: math3d.Point{X: 1}}, Radius: 0.5}
+			b := math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{Y: -1}, B: math3d.Point{Y: 1}}, Radius: 0.5}
+			return math3d.IntersectsCapsuleCapsule(a, b)
+		}, true, math3d.Contact{Normal: math3d.NewVec3(0, 0, 1), Depth: 1}},
+	} {
+		got, ok := tt.got()
+		if ok != tt.ok {
+			t.Errorf("Intersects: %s: want %v, got %v\n", tt.name, tt.ok, ok)
+		} else if ok && (!got.Normal.ApproxEqual(tt.want.Normal, 1e-12) || math.Abs(got.Depth-tt.want.Depth) > 1e-12) {
+			t.Errorf("Intersects: %s: want %v, got %v\n", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Cylinder is the solid right circular cylinder of the given Radius
+// around the axis from A to B, closed by flat caps at both ends.
+type Cylinder struct {
+	A, B   Point
+	Radius float64
+}
+
+// Support returns the point of the cylinder furthest in the direction d.
+// It implements the ConvexShape interface.
+func (c Cylinder) Support(d Vec3) Point {
+	p := Segment{A: c.A, B: c.B}.Support(d)
+	axis := c.B.Sub(c.A).NormalizeOrZero()
+	radial := d.Sub(axis.Mul(d.Dot(axis))).NormalizeOrZero()
+	return p.Add(radial.Mul(c.Radius))
+}
+
+// Torus is the surface swept by a circle of radius MinorRadius whose
+// center moves around a circle of radius MajorRadius about Center, in
+// the plane perpendicular to Axis.
+type Torus struct {
+	Center      Point
+	Axis        Vec3
+	MajorRadius float64
+	MinorRadius float64
+}
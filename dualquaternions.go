@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// DualQuaternion implements a dual quaternion Real + εDual, where ε² = 0.
+// Unit dual quaternions represent rigid transforms: the real part is the
+// rotation and the dual part encodes the translation applied after it.
+type DualQuaternion struct {
+	Real, Dual Quaternion
+}
+
+// IdentityDualQuaternion returns the dual quaternion for no motion.
+func IdentityDualQuaternion() DualQuaternion {
+	return DualQuaternion{Real: IdentityQuaternion()}
+}
+
+// DualQuaternionFromRotationTranslation returns the unit dual quaternion
+// that rotates by r and then translates by t. The rotation is normalized.
+//
+//	dual = ½ t r
+func DualQuaternionFromRotationTranslation(r Quaternion, t Vec3) DualQuaternion {
+	r = r.Normalize()
+	return DualQuaternion{
+		Real: r,
+		Dual: Quaternion{X: t.X, Y: t.Y, Z: t.Z}.Mul(r).MulScalar(0.5),
+	}
+}
+
+// DualQuaternionFromAffine returns the unit dual quaternion for the rigid
+// transform a. Any scale or shear in a is not representable and is lost.
+func DualQuaternionFromAffine(a Affine) DualQuaternion {
+	return DualQuaternionFromRotationTranslation(a.Linear().ToQuaternion(), a.Translation())
+}
+
+// BlendDualQuaternions returns the dual quaternion linear blend (DLB) of
+// the unit dual quaternions with the given weights. Each term is flipped
+// into the hemisphere of the first so that the blend takes the shorter
+// path, and the weighted sum is normalized. Unlike blending matrices,
+// the result is always a rigid transform, which avoids the collapsing
+// joints of linear blend skinning. The slices must have the same length.
+func BlendDualQuaternions(dqs []DualQuaternion, weights []float64) DualQuaternion {
+	if len(dqs) != len(weights) {
+		panic("math3d: blend length mismatch")
+	}
+	var sum DualQuaternion
+	for i, dq := range dqs {
+		w := weights[i]
+		if dq.Real.Dot(dqs[0].Real) < 0 {
+			w = -w
+		}
+		sum = sum.Add(dq.MulScalar(w))
+	}
+	return sum.Normalize()
+}
+
+// Add returns the component-wise sum d+e.
+func (d DualQuaternion) Add(e DualQuaternion) DualQuaternion {
+	return DualQuaternion{Real: d.Real.Add(e.Real), Dual: d.Dual.Add(e.Dual)}
+}
+
+// Affine returns the rigid transform for the unit dual quaternion d.
+func (d DualQuaternion) Affine() Affine {
+	return AffineFromLinear(d.Real.ToMat3(), d.Translation())
+}
+
+// ApproxEqual reports whether each component of d is within epsilon of
+// the corresponding component of e. Note that d and −d represent the
+// same rigid transform but are not approximately equal.
+func (d DualQuaternion) ApproxEqual(e DualQuaternion, epsilon float64) bool {
+	return d.Real.ApproxEqual(e.Real, epsilon) && d.Dual.ApproxEqual(e.Dual, epsilon)
+}
+
+// Conjugate returns the quaternion conjugate of both parts of d.
+// For a unit dual quaternion this is the inverse transform.
+func (d DualQuaternion) Conjugate() DualQuaternion {
+	return DualQuaternion{Real: d.Real.Conjugate(), Dual: d.Dual.Conjugate()}
+}
+
+// Mul returns the product d×e. As transforms, the product applies e
+// first and then d.
+func (d DualQuaternion) Mul(e DualQuaternion) DualQuaternion {
+	return DualQuaternion{
+		Real: d.Real.Mul(e.Real),
+		Dual: d.Real.Mul(e.Dual).Add(d.Dual.Mul(e.Real)),
+	}
+}
+
+// MulScalar returns d with every component multiplied by scalar.
+func (d DualQuaternion) MulScalar(scalar float64) DualQuaternion {
+	return DualQuaternion{Real: d.Real.MulScalar(scalar), Dual: d.Dual.MulScalar(scalar)}
+}
+
+// Normalize returns the unit dual quaternion nearest d. It scales both
+// parts so that the real part has unit length and then removes the part
+// of the dual that is not orthogonal to the real. It returns the
+// identity if the real part is zero.
+func (d DualQuaternion) Normalize() DualQuaternion {
+	n := d.Real.Length()
+	if n == 0 {
+		return IdentityDualQuaternion()
+	}
+	r, e := d.Real.MulScalar(1/n), d.Dual.MulScalar(1/n)
+	return DualQuaternion{Real: r, Dual: e.Sub(r.MulScalar(r.Dot(e)))}
+}
+
+// Rotation returns the rotation part of the unit dual quaternion d.
+func (d DualQuaternion) Rotation() Quaternion {
+	return d.Real
+}
+
+// TransformDirection returns v rotated by the unit dual quaternion d.
+// Directions are not affected by the translation.
+func (d DualQuaternion) TransformDirection(v Vec3) Vec3 {
+	return d.Real.RotateVec3(v)
+}
+
+// TransformPoint returns p rotated and then translated by the unit dual
+// quaternion d.
+func (d DualQuaternion) TransformPoint(p Point) Point {
+	return d.Real.RotateVec3(p.Vec3()).Add(d.Translation()).Point()
+}
+
+// Translation returns the translation part of the unit dual quaternion d.
+//
+//	t = 2 dual real*
+func (d DualQuaternion) Translation() Vec3 {
+	t := d.Dual.Mul(d.Real.Conjugate())
+	return Vec3{X: 2 * t.X, Y: 2 * t.Y, Z: 2 * t.Z}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestDualQuaternion(t *testing.T) {
+	r := math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 2, 3), 0.9)
+	tr := math3d.NewVec3(4, -5, 6)
+	d := math3d.DualQuaternionFromRotationTranslation(r, tr)
+	if got := d.Translation(); !got.ApproxEqual(tr, 1e-12) {
+		t.Errorf("Translation: want %v, got %v\n", tr, got)
+	}
+	if got := d.Rotation(); !got.ApproxEqual(r, 1e-12) {
+		t.Errorf("Rotation: want %v, got %v\n", r, got)
+	}
+
+	a := math3d.AffineFromLinear(r.ToMat3(), tr)
+	if got := d.Affine(); !got.ApproxEqual(a, 1e-12) {
+		t.Errorf("Affine: want %v, got %v\n", a, got)
+	}
+	if got := math3d.DualQuaternionFromAffine(a); !got.ApproxEqual(d, 1e-12) && !got.ApproxEqual(d.MulScalar(-1), 1e-12) {
+		t.Errorf("DualQuaternionFromAffine: want %v, got %v\n", d, got)
+	}
+
+	p := math3d.Point{X: 1, Y: -1, Z: 2}
+	if want, got := a.TransformPoint(p), d.TransformPoint(p); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("TransformPoint: want %v, got %v\n", want, got)
+	}
+
+	e := math3d.DualQuaternionFromRotationTranslation(math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 1, 0), -0.4), math3d.NewVec3(1, 0, 0))
+	if want, got := d.Affine().Mul(e.Affine()), d.Mul(e).Affine(); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("Mul: want %v, got %v\n", want, got)
+	}
+	if got := d.Mul(d.Conjugate()); !got.ApproxEqual(math3d.IdentityDualQuaternion(), 1e-12) {
+		t.Errorf("Conjugate: want identity, got %v\n", got)
+	}
+
+	// normalizing a scaled and perturbed dual quaternion restores a rigid transform
+	n := d.MulScalar(3).Normalize()
+	if !n.ApproxEqual(d, 1e-12) {
+		t.Errorf("Normalize: want %v, got %v\n", d, n)
+	}
+}
+
+func TestBlendDualQuaternions(t *testing.T) {
+	a := math3d.DualQuaternionFromRotationTranslation(math3d.IdentityQuaternion(), math3d.NewVec3(0, 0, 0))
+	b := math3d.DualQuaternionFromRotationTranslation(math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 0, 1), math.Pi/2), math3d.NewVec3(2, 0, 0))
+
+	if got := math3d.BlendDualQuaternions([]math3d.DualQuaternion{a, b}, []float64{1, 0}); !got.ApproxEqual(a, 1e-12) {
+		t.Errorf("BlendDualQuaternions: weight on a: want %v, got %v\n", a, got)
+	}
+	// a sign-flipped copy of b blends to the same transform
+	got := math3d.BlendDualQuaternions([]math3d.DualQuaternion{a, b.MulScalar(-1)}, []float64{0.5, 0.5})
+	if n := got.Real.Length(); math.Abs(n-1) > 1e-12 {
+		t.Errorf("BlendDualQuaternions: want unit real part, got length %f\n", n)
+	}
+	want := math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 0, 1), math.Pi/4)
+	if !got.Rotation().ApproxEqual(want, 1e-12) {
+		t.Errorf("BlendDualQuaternions: rotation: want %v, got %v\n", want, got.Rotation())
+	}
+	if d := got.Real.Dot(got.Dual); math.Abs(d) > 1e-12 {
+		t.Errorf("BlendDualQuaternions: want real·dual = 0, got %f\n", d)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// EasingFunc maps a parameter t in [0, 1] to a shaped parameter.
+// Easing functions return 0 when t is 0 and 1 when t is 1.
+//
+// To ease a vector, pass the shaped parameter to Lerp,
+//
+//	v.Lerp(w, EaseInOutCubic(t))
+//
+// or use Apply to shape each component.
+type EasingFunc func(t float64) float64
+
+// Linear is the identity easing function.
+func Linear(t float64) float64 {
+	return t
+}
+
+// Smoothstep returns 0 when x ≤ edge0, 1 when x ≥ edge1, and performs a
+// smooth Hermite interpolation between 0 and 1 otherwise.
+//
+//	t = clamp((x−edge0)/(edge1−edge0), 0, 1)
+//	s = t²(3 − 2t)
+//
+// When edge0 equals edge1 it is a step from 0 to 1 at the edge.
+func Smoothstep(edge0, edge1, x float64) float64 {
+	t := smoothstepParam(edge0, edge1, x)
+	return t * t * (3 - 2*t)
+}
+
+// Smootherstep is Ken Perlin's variant of Smoothstep with zero first and
+// second derivatives at the edges.
+//
+//	s = t³(t(6t − 15) + 10)
+func Smootherstep(edge0, edge1, x float64) float64 {
+	t := smoothstepParam(edge0, edge1, x)
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// EaseInQuad accelerates from rest along t².
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad decelerates to rest, the reverse of EaseInQuad.
+func EaseOutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOutQuad accelerates and then decelerates along quadratic curves.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - 2*(1-t)*(1-t)
+}
+
+// EaseInCubic accelerates from rest along t³.
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic decelerates to rest, the reverse of EaseInCubic.
+func EaseOutCubic(t float64) float64 {
+	u := 1 - t
+	return 1 - u*u*u
+}
+
+// EaseInOutCubic accelerates and then decelerates along cubic curves.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	u := 1 - t
+	return 1 - 4*u*u*u
+}
+
+// EaseInSine accelerates from rest along a quarter cosine wave.
+func EaseInSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// EaseOutSine decelerates to rest along a quarter sine wave.
+func EaseOutSine(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+// EaseInOutSine accelerates and then decelerates along a half cosine wave.
+func EaseInOutSine(t float64) float64 {
+	return (1 - math.Cos(t*math.Pi)) / 2
+}
+
+// EaseInExpo accelerates from rest exponentially, doubling every tenth of t.
+func EaseInExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*(t-1))
+}
+
+// EaseOutExpo decelerates to rest exponentially, the reverse of EaseInExpo.
+func EaseOutExpo(t float64) float64 {
+	if t >= 1 {
+		return 1
+	}
+	return 1 - math.Pow(2, -10*t)
+}
+
+// EaseInOutExpo accelerates and then decelerates exponentially.
+func EaseInOutExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	} else if t >= 1 {
+		return 1
+	} else if t < 0.5 {
+		return math.Pow(2, 20*t-10) / 2
+	}
+	return 1 - math.Pow(2, -20*t+10)/2
+}
+
+// smoothstepParam returns the clamped parameter of x between the edges.
+// Equal edges give a step, 0 below the edge and 1 at or above it.
+func smoothstepParam(edge0, edge1, x float64) float64 {
+	if edge0 == edge1 {
+		if x < edge0 {
+			return 0
+		}
+		return 1
+	}
+	return Clamp(InverseLerp(edge0, edge1, x), 0, 1)
+}
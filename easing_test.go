@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestEasing(t *testing.T) {
+	for name, fn := range map[string]math3d.EasingFunc{
+		"Linear":         math3d.Linear,
+		"EaseInQuad":     math3d.EaseInQuad,
+		"EaseOutQuad":    math3d.EaseOutQuad,
+		"EaseInOutQuad":  math3d.EaseInOutQuad,
+		"EaseInCubic":    math3d.EaseInCubic,
+		"EaseOutCubic":   math3d.EaseOutCubic,
+		"EaseInOutCubic": math3d.EaseInOutCubic,
+		"EaseInSine":     math3d.EaseInSine,
+		"EaseOutSine":    math3d.EaseOutSine,
+		"EaseInOutSine":  math3d.EaseInOutSine,
+		"EaseInExpo":     math3d.EaseInExpo,
+		"EaseOutExpo":    math3d.EaseOutExpo,
+		"EaseInOutExpo":  math3d.EaseInOutExpo,
+	} {
+		if v := fn(0); math.Abs(v) > 1e-12 {
+			t.Errorf("%s(0): want %f, got %f\n", name, 0.0, v)
+		}
+		if v := fn(1); math.Abs(v-1) > 1e-12 {
+			t.Errorf("%s(1): want %f, got %f\n", name, 1.0, v)
+		}
+	}
+
+	for _, tt := range []struct {
+		x, expect float64
+	}{
+		{-1, 0},
+		{0, 0},
+		{0.5, 0.5},
+		{1, 1},
+		{2, 1},
+	} {
+		if v := math3d.Smoothstep(0, 1, tt.x); v != tt.expect {
+			t.Errorf("Smoothstep: want %f, got %f\n", tt.expect, v)
+		}
+		if v := math3d.Smootherstep(0, 1, tt.x); v != tt.expect {
+			t.Errorf("Smootherstep: want %f, got %f\n", tt.expect, v)
+		}
+	}
+
+	// equal edges make a step at the edge
+	for _, tt := range []struct {
+		x, expect float64
+	}{
+		{1, 0},
+		{2, 1},
+		{3, 1},
+	} {
+		if v := math3d.Smoothstep(2, 2, tt.x); v != tt.expect {
+			t.Errorf("Smoothstep: equal edges: want %f, got %f\n", tt.expect, v)
+		}
+		if v := math3d.Smootherstep(2, 2, tt.x); v != tt.expect {
+			t.Errorf("Smootherstep: equal edges: want %f, got %f\n", tt.expect, v)
+		}
+	}
+
+	v := math3d.NewVec3(0, 0.5, 1).Apply(math3d.EaseInQuad)
+	if v != math3d.NewVec3(0, 0.25, 1) {
+		t.Errorf("Apply: want %v, got %v\n", math3d.NewVec3(0, 0.25, 1), v)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// RotationOrder is the order in which Euler angle rotations are applied.
+// All orders are intrinsic: each rotation is about an axis of the frame
+// produced by the rotations before it. The intrinsic order XYZ is the
+// same as the extrinsic order ZYX about the fixed world axes.
+type RotationOrder int
+
+const (
+	OrderXYZ RotationOrder = iota
+	OrderXZY
+	OrderYXZ
+	OrderYZX
+	OrderZXY
+	OrderZYX
+)
+
+// String implements the fmt.Stringer interface.
+func (o RotationOrder) String() string {
+	switch o {
+	case OrderXYZ:
+		return "XYZ"
+	case OrderXZY:
+		return "XZY"
+	case OrderYXZ:
+		return "YXZ"
+	case OrderYZX:
+		return "YZX"
+	case OrderZXY:
+		return "ZXY"
+	case OrderZYX:
+		return "ZYX"
+	}
+	return "RotationOrder(?)"
+}
+
+// EulerAngles is a rotation given as counter-clockwise angles in radians
+// about the x, y, and z axes, applied in the given order.
+type EulerAngles struct {
+	X, Y, Z float64
+	Order   RotationOrder
+}
+
+// EulerAnglesFromMat3 returns the Euler angles in the given order for
+// the rotation matrix m. The middle angle is in [−π/2, π/2] and the
+// others are in [−π, π]. At gimbal lock, when the middle angle is ±π/2,
+// the first and last axes coincide and the last angle is set to zero.
+func EulerAnglesFromMat3(m Mat3, order RotationOrder) EulerAngles {
+	const lock = 0.9999999
+	e := EulerAngles{Order: order}
+	switch order {
+	case OrderXYZ:
+		e.Y = math.Asin(Clamp(m[0][2], -1, 1))
+		if math.Abs(m[0][2]) < lock {
+			e.X, e.Z = math.Atan2(-m[1][2], m[2][2]), math.Atan2(-m[0][1], m[0][0])
+		} else {
+			e.X = math.Atan2(m[2][1], m[1][1])
+		}
+	case OrderXZY:
+		e.Z = math.Asin(-Clamp(m[0][1], -1, 1))
+		if math.Abs(m[0][1]) < lock {
+			e.X, e.Y = math.Atan2(m[2][1], m[1][1]), math.Atan2(m[0][2], m[0][0])
+		} else {
+			e.X = math.Atan2(-m[1][2], m[2][2])
+		}
+	case OrderYXZ:
+		e.X = math.Asin(-Clamp(m[1][2], -1, 1))
+		if math.Abs(m[1][2]) < lock {
+			e.Y, e.Z = math.Atan2(m[0][2], m[2][2]), math.Atan2(m[1][0], m[1][1])
+		} else {
+			e.Y = math.Atan2(-m[2][0], m[0][0])
+		}
+	case OrderYZX:
+		e.Z = math.Asin(Clamp(m[1][0], -1, 1))
+		if math.Abs(m[1][0]) < lock {
+			e.X, e.Y = math.Atan2(-m[1][2], m[1][1]), math.Atan2(-m[2][0], m[0][0])
+		} else {
+			e.Y = math.Atan2(m[0][2], m[2][2])
+		}
+	case OrderZXY:
+		e.X = math.Asin(Clamp(m[2][1], -1, 1))
+		if math.Abs(m[2][1]) < lock {
+			e.Y, e.Z = math.Atan2(-m[2][0], m[2][2]), math.Atan2(-m[0][1], m[1][1])
+		} else {
+			e.Z = math.Atan2(m[1][0], m[0][0])
+		}
+	case OrderZYX:
+		e.Y = math.Asin(-Clamp(m[2][0], -1, 1))
+		if math.Abs(m[2][0]) < lock {
+			e.X, e.Z = math.Atan2(m[2][1], m[2][2]), math.Atan2(m[1][0], m[0][0])
+		} else {
+			e.Z = math.Atan2(-m[0][1], m[1][1])
+		}
+	}
+	return e
+}
+
+// EulerAnglesFromQuaternion returns the Euler angles in the given order
+// for the unit quaternion q. See EulerAnglesFromMat3.
+func EulerAnglesFromQuaternion(q Quaternion, order RotationOrder) EulerAngles {
+	return EulerAnglesFromMat3(q.ToMat3(), order)
+}
+
+// ToMat3 returns the rotation matrix for the Euler angles. For the
+// intrinsic order XYZ it is Rx × Ry × Rz.
+func (e EulerAngles) ToMat3() Mat3 {
+	x, y, z := Mat3FromRotationX(e.X), Mat3FromRotationY(e.Y), Mat3FromRotationZ(e.Z)
+	switch e.Order {
+	case OrderXZY:
+		return x.Mul(z).Mul(y)
+	case OrderYXZ:
+		return y.Mul(x).Mul(z)
+	case OrderYZX:
+		return y.Mul(z).Mul(x)
+	case OrderZXY:
+		return z.Mul(x).Mul(y)
+	case OrderZYX:
+		return z.Mul(y).Mul(x)
+	}
+	return x.Mul(y).Mul(z)
+}
+
+// ToQuaternion returns the unit quaternion for the Euler angles.
+func (e EulerAngles) ToQuaternion() Quaternion {
+	x := QuaternionFromAxisAngle(Vec3{X: 1}, e.X)
+	y := QuaternionFromAxisAngle(Vec3{Y: 1}, e.Y)
+	z := QuaternionFromAxisAngle(Vec3{Z: 1}, e.Z)
+	switch e.Order {
+	case OrderXZY:
+		return x.Mul(z).Mul(y)
+	case OrderYXZ:
+		return y.Mul(x).Mul(z)
+	case OrderYZX:
+		return y.Mul(z).Mul(x)
+	case OrderZXY:
+		return z.Mul(x).Mul(y)
+	case OrderZYX:
+		return z.Mul(y).Mul(x)
+	}
+	return x.Mul(y).Mul(z)
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Frustum is the convex volume bounded by six planes whose
+// normals point inward. The planes are ordered left, right,
+// bottom, top, near, and far.
+type Frustum struct {
+	Planes [6]Plane
+}
+
+// Containment is the result of classifying a volume against a frustum.
+type Containment int
+
+const (
+	// Outside means the volume is entirely outside the frustum.
+	Outside Containment = iota
+	// Intersecting means the volume may cross the boundary of the frustum.
+	Intersecting
+	// Inside means the volume is entirely inside the frustum.
+	Inside
+)
+
+// String implements the fmt.Stringer interface.
+func (c Containment) String() string {
+	switch c {
+	case Outside:
+		return "Outside"
+	case Intersecting:
+		return "Intersecting"
+	case Inside:
+		return "Inside"
+	}
+	return "Containment(?)"
+}
+
+// FrustumFromMatrix extracts the planes of the view volume of a
+// projection or view-projection matrix using the method of Gribb and
+// Hartmann. The clip space is needed to locate the depth planes. With a
+// view-projection matrix the planes are in world space; with a projection
+// matrix alone they are in view space. A far plane at infinity becomes a
+// plane that contains every point.
+func FrustumFromMatrix(m Mat4, clip ClipSpace) Frustum {
+	row := func(i int) Vec4 {
+		return Vec4{X: m[i][0], Y: m[i][1], Z: m[i][2], W: m[i][3]}
+	}
+	r0, r1, r2, r3 := row(0), row(1), row(2), row(3)
+	lo, hi := r3.Add(r2), r3.Sub(r2) // −w ≤ z ≤ w
+	if clip.Depth == DepthZeroToOne {
+		lo = r2 // 0 ≤ z ≤ w
+	}
+	near, far := lo, hi
+	if clip.Reversed {
+		near, far = hi, lo
+	}
+	var f Frustum
+	for i, v := range []Vec4{r3.Add(r0), r3.Sub(r0), r3.Add(r1), r3.Sub(r1), near, far} {
+		f.Planes[i] = planeFromVec4(v)
+	}
+	return f
+}
+
+// ClassifyAABB reports whether the box is inside, outside, or crossing
+// the frustum. For each plane it tests the corner of the box furthest
+// along the plane's normal (the p-vertex) to reject the box and the
+// corner furthest against it (the n-vertex) to accept it. Children of a
+// node that is Inside need no further tests. Like IntersectsAABB, the
+// test is conservative: boxes near the frustum's edges may be reported
+// as Intersecting when they are Outside.
+func (f Frustum) ClassifyAABB(b AABB) Containment {
+	result := Inside
+	for _, pl := range f.Planes {
+		if pl.SignedDistance(b.pVertex(pl.Normal)) < 0 {
+			return Outside
+		}
+		if pl.SignedDistance(b.pVertex(pl.Normal.Mul(-1))) < 0 {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+// ClassifySphere reports whether the sphere is inside, outside, or
+// crossing the frustum. The test is conservative in the same way as
+// ClassifyAABB.
+func (f Frustum) ClassifySphere(s Sphere) Containment {
+	result := Inside
+	for _, pl := range f.Planes {
+		d := pl.SignedDistance(s.Center)
+		if d < -s.Radius {
+			return Outside
+		}
+		if d < s.Radius {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+// ContainsPoint reports whether p is inside or on the boundary of the frustum.
+func (f Frustum) ContainsPoint(p Point) bool {
+	for _, pl := range f.Planes {
+		if pl.SignedDistance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Corners returns the eight corners of the frustum. The four corners of
+// the near plane come first, followed by the four of the far plane, each
+// in the order bottom-left, bottom-right, top-right, top-left.
+// It returns false if a corner is not finite, as when the far plane is
+// at infinity.
+func (f Frustum) Corners() ([8]Point, bool) {
+	const left, right, bottom, top, near, far = 0, 1, 2, 3, 4, 5
+	var corners [8]Point
+	for i, depth := range []int{near, far} {
+		for j, side := range [4][2]int{{left, bottom}, {right, bottom}, {right, top}, {left, top}} {
+			p, ok := IntersectThreePlanes(f.Planes[side[0]], f.Planes[side[1]], f.Planes[depth])
+			if !ok {
+				return corners, false
+			}
+			corners[i*4+j] = p
+		}
+	}
+	return corners, true
+}
+
+// IntersectsAABB reports whether the box may intersect the frustum.
+// For each plane it tests only the corner of the box furthest along the
+// plane's normal (the p-vertex). The test is conservative: boxes near
+// the frustum's edges may be reported as intersecting when they are not.
+func (f Frustum) IntersectsAABB(b AABB) bool {
+	for _, pl := range f.Planes {
+		if pl.SignedDistance(b.pVertex(pl.Normal)) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere reports whether the sphere may intersect the frustum.
+// The test is conservative in the same way as IntersectsAABB.
+func (f Frustum) IntersectsSphere(s Sphere) bool {
+	for _, pl := range f.Planes {
+		if pl.SignedDistance(s.Center) < -s.Radius {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitCascades divides the frustum into slices for cascaded shadow maps.
+// The distances are measured from the near plane along the viewing
+// direction and must be increasing; n distances produce n−1 frustums
+// whose side planes are shared with f. For example, distances of
+// 0, 10, 30, and 99 split a frustum with near and far planes 1 and 100
+// units from the eye into three cascades.
+func (f Frustum) SplitCascades(distances []float64) []Frustum {
+	if len(distances) < 2 {
+		return nil
+	}
+	near := f.Planes[4]
+	cascades := make([]Frustum, 0, len(distances)-1)
+	for i := 1; i < len(distances); i++ {
+		c := f
+		c.Planes[4] = Plane{Normal: near.Normal, D: near.D - distances[i-1]}
+		c.Planes[5] = Plane{Normal: near.Normal.Mul(-1), D: distances[i] - near.D}
+		cascades = append(cascades, c)
+	}
+	return cascades
+}
+
+// planeFromVec4 returns the plane ax + by + cz + d = 0 with a unit
+// normal. A plane with a zero normal is returned unchanged.
+func planeFromVec4(v Vec4) Plane {
+	return Plane{Normal: Vec3{X: v.X, Y: v.Y, Z: v.Z}, D: v.W}.Normalize()
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestFrustum(t *testing.T) {
+	view := math3d.LookAt(math3d.Point{Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0))
+	for _, tt := range []struct {
+		name string
+		proj math3d.Mat4
+		clip math3d.ClipSpace
+	}{
+		{"gl", math3d.Perspective(math.Pi/2, 1, 1, 100), math3d.ClipSpace{}},
+		{"d3d", math3d.PerspectiveClip(math.Pi/2, 1, 1, 100, math3d.ClipSpace{Depth: math3d.DepthZeroToOne}), math3d.ClipSpace{Depth: math3d.DepthZeroToOne}},
+		{"reversed", math3d.PerspectiveClip(math.Pi/2, 1, 1, 100, math3d.ClipSpace{Depth: math3d.DepthZeroToOne, Reversed: true}), math3d.ClipSpace{Depth: math3d.DepthZeroToOne, Reversed: true}},
+	} {
+		f := math3d.FrustumFromMatrix(tt.proj.Mul(view), tt.clip)
+		for _, pt := range []struct {
+			p      math3d.Point
+			inside bool
+		}{
+			{math3d.Point{}, true},
+			{math3d.Point{X: 8, Y: 0, Z: 0}, true},
+			{math3d.Point{X: 11, Y: 0, Z: 0}, false},
+			{math3d.Point{Z: 9.5}, false},
+			{math3d.Point{Z: -89}, true},
+			{math3d.Point{Z: -91}, false},
+		} {
+			if ok := f.ContainsPoint(pt.p); ok != pt.inside {
+				t.Errorf("%s: ContainsPoint(%v): want %v, got %v\n", tt.name, pt.p, pt.inside, ok)
+			}
+		}
+		if n := f.Planes[4].Normal; !n.ApproxEqual(math3d.NewVec3(0, 0, -1), 1e-12) {
+			t.Errorf("%s: near plane normal: want %v, got %v\n", tt.name, math3d.NewVec3(0, 0, -1), n)
+		}
+		if !f.IntersectsSphere(math3d.Sphere{Center: math3d.Point{X: 11}, Radius: 2}) {
+			t.Errorf("%s: IntersectsSphere: want true, got false\n", tt.name)
+		}
+		if f.IntersectsSphere(math3d.Sphere{Center: math3d.Point{X: 20}, Radius: 2}) {
+			t.Errorf("%s: IntersectsSphere: want false, got true\n", tt.name)
+		}
+		if !f.IntersectsAABB(math3d.AABB{Min: math3d.Point{X: 9, Y: -1, Z: -1}, Max: math3d.Point{X: 12, Y: 1, Z: 1}}) {
+			t.Errorf("%s: IntersectsAABB: want true, got false\n", tt.name)
+		}
+		if f.IntersectsAABB(math3d.AABB{Min: math3d.Point{X: 15, Y: -1, Z: -1}, Max: math3d.Point{X: 17, Y: 1, Z: 1}}) {
+			t.Errorf("%s: IntersectsAABB: want false, got true\n", tt.name)
+		}
+		for _, c := range []struct {
+			b    math3d.AABB
+			want math3d.Containment
+		}{
+			{math3d.AABB{Min: math3d.Point{X: -1, Y: -1, Z: -1}, Max: math3d.Point{X: 1, Y: 1, Z: 1}}, math3d.Inside},
+			{math3d.AABB{Min: math3d.Point{X: 9, Y: -1, Z: -1}, Max: math3d.Point{X: 12, Y: 1, Z: 1}}, math3d.Intersecting},
+			{math3d.AABB{Min: math3d.Point{X: 15, Y: -1, Z: -1}, Max: math3d.Point{X: 17, Y: 1, Z: 1}}, math3d.Outside},
+		} {
+			if got := f.ClassifyAABB(c.b); got != c.want {
+				t.Errorf("%s: ClassifyAABB(%v): want %v, got %v\n", tt.name, c.b, c.want, got)
+			}
+		}
+		for _, c := range []struct {
+			s    math3d.Sphere
+			want math3d.Containment
+		}{
+			{math3d.Sphere{Center: math3d.Point{}, Radius: 2}, math3d.Inside},
+			{math3d.Sphere{Center: math3d.Point{X: 11}, Radius: 2}, math3d.Intersecting},
+			{math3d.Sphere{Center: math3d.Point{Z: -90}, Radius: 2}, math3d.Intersecting},
+			{math3d.Sphere{Center: math3d.Point{X: 20}, Radius: 2}, math3d.Outside},
+		} {
+			if got := f.ClassifySphere(c.s); got != c.want {
+				t.Errorf("%s: ClassifySphere(%v): want %v, got %v\n", tt.name, c.s, c.want, got)
+			}
+		}
+	}
+
+	// an infinite far plane culls nothing behind the near plane
+	f := math3d.FrustumFromMatrix(math3d.PerspectiveReversedInfinite(math.Pi/2, 1, 1), math3d.ClipSpace{Depth: math3d.DepthZeroToOne, Reversed: true})
+	if !f.ContainsPoint(math3d.Point{Z: -1e12}) {
+		t.Errorf("infinite: ContainsPoint: want true, got false\n")
+	}
+}
+
+func TestFrustumCorners(t *testing.T) {
+	f := math3d.FrustumFromMatrix(math3d.Perspective(math.Pi/2, 2, 1, 100), math3d.ClipSpace{})
+	corners, ok := f.Corners()
+	if !ok {
+		t.Fatalf("Corners: want ok, got false\n")
+	}
+	for i, expect := range []math3d.Point{
+		{X: -2, Y: -1, Z: -1}, {X: 2, Y: -1, Z: -1}, {X: 2, Y: 1, Z: -1}, {X: -2, Y: 1, Z: -1},
+		{X: -200, Y: -100, Z: -100}, {X: 200, Y: -100, Z: -100}, {X: 200, Y: 100, Z: -100}, {X: -200, Y: 100, Z: -100},
+	} {
+		if !corners[i].ApproxEqual(expect, 1e-9) {
+			t.Errorf("Corners: %d: want %v, got %v\n", i, expect, corners[i])
+		}
+	}
+
+	cascades := f.SplitCascades([]float64{0, 9, 99})
+	if len(cascades) != 2 {
+		t.Fatalf("SplitCascades: want 2 cascades, got %d\n", len(cascades))
+	}
+	c0, _ := cascades[0].Corners()
+	c1, _ := cascades[1].Corners()
+	if !c0[4].ApproxEqual(math3d.Point{X: -20, Y: -10, Z: -10}, 1e-9) {
+		t.Errorf("SplitCascades: 0: want %v, got %v\n", math3d.Point{X: -20, Y: -10, Z: -10}, c0[4])
+	}
+	if !c1[0].ApproxEqual(c0[4], 1e-9) {
+		t.Errorf("SplitCascades: cascades should share a plane: want %v, got %v\n", c0[4], c1[0])
+	}
+	if !c1[6].ApproxEqual(corners[6], 1e-9) {
+		t.Errorf("SplitCascades: 1: want %v, got %v\n", corners[6], c1[6])
+	}
+}
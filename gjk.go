@@ -0,0 +1,427 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// ConvexShape is a convex set described by its support function, which
+// is all that the GJK and EPA queries need to know about a shape.
+// Sphere, AABB, OBB, Capsule, Cylinder, Segment, Triangle, and
+// Tetrahedron implement it.
+type ConvexShape interface {
+	// Support returns a point of the shape furthest in the direction d.
+	// The direction need not be normalized and may be zero.
+	Support(d Vec3) Point
+}
+
+const (
+	gjkMaxIterations = 128
+	epaMaxIterations = 1024
+	// gjkTolerance is the relative accuracy of distances found by GJK.
+	gjkTolerance = 1e-10
+	// epaTolerance is the relative accuracy of depths found by EPA.
+	epaTolerance = 1e-8
+)
+
+// GJKDistance returns the distance between two convex shapes and the
+// closest point on each, using the algorithm of Gilbert, Johnson, and
+// Keerthi. The distance is zero when the shapes overlap or touch, and
+// the closest points are then a point common to both.
+func GJKDistance(a, b ConvexShape) (dist float64, pa, pb Point) {
+	g := gjk(a, b, false)
+	pa, pb = g.witnesses()
+	return g.v.Length(), pa, pb
+}
+
+// GJKIntersects reports whether two convex shapes overlap or touch.
+// It stops as soon as it finds a plane that separates them, so it is
+// faster than GJKDistance for shapes that are far apart.
+func GJKIntersects(a, b ConvexShape) bool {
+	return gjk(a, b, true).intersect
+}
+
+// EPAContact reports whether two convex shapes overlap or touch and
+// returns the contact that separates them along the shortest direction.
+// It runs GJK to find a simplex that encloses the origin of the
+// Minkowski difference and then the expanding polytope algorithm to find
+// the point on its boundary nearest the origin. Curved shapes are
+// approximated by a polytope with a bounded number of vertices, so their
+// depths are approximate. When the Minkowski difference is flat, as for
+// two coplanar triangles, Depth is zero.
+func EPAContact(a, b ConvexShape) (Contact, bool) {
+	g := gjk(a, b, false)
+	if !g.intersect {
+		return Contact{}, false
+	}
+	if verts, ok := g.enclose(a, b); ok {
+		if c, ok := epa(a, b, verts); ok {
+			return c, true
+		}
+	}
+	return Contact{Normal: g.flatNormal(), Depth: 0}, true
+}
+
+// furthestPoint returns the point furthest in the direction d.
+func furthestPoint(d Vec3, points ...Point) Point {
+	best, bestDot := points[0], points[0].Vec3().Dot(d)
+	for _, p := range points[1:] {
+		if dot := p.Vec3().Dot(d); dot > bestDot {
+			best, bestDot = p, dot
+		}
+	}
+	return best
+}
+
+// supportPoint is a vertex of the Minkowski difference a − b along with
+// the points of a and b that produced it.
+type supportPoint struct {
+	w      Vec3
+	pa, pb Point
+}
+
+// minkowskiSupport returns the point of a − b furthest in the direction d.
+func minkowskiSupport(a, b ConvexShape, d Vec3) supportPoint {
+	pa, pb := a.Support(d), b.Support(d.Mul(-1))
+	return supportPoint{w: pa.Sub(pb), pa: pa, pb: pb}
+}
+
+// gjkState is the result of running GJK: the simplex, the weights of the
+// point v in it that is nearest the origin, and whether the shapes meet.
+type gjkState struct {
+	simplex   []supportPoint
+	lambda    []float64
+	v         Vec3
+	intersect bool
+}
+
+// gjk runs the distance algorithm on a − b. When earlyOut is set, it
+// stops as soon as it finds a separating plane.
+func gjk(a, b ConvexShape, earlyOut bool) gjkState {
+	first := minkowskiSupport(a, b, Vec3{X: 1})
+	g := gjkState{simplex: []supportPoint{first}, lambda: []float64{1}, v: first.w}
+	scale := first.w.LengthSquared()
+	for i := 0; i < gjkMaxIterations; i++ {
+		vv := g.v.LengthSquared()
+		if vv <= gjkTolerance*gjkTolerance*scale {
+			g.intersect = true
+			return g
+		}
+		w := minkowskiSupport(a, b, g.v.Mul(-1))
+		vw := g.v.Dot(w.w)
+		if earlyOut && vw > 0 {
+			return g
+		}
+		if vv-vw <= gjkTolerance*vv {
+			break
+		}
+		for _, s := range g.simplex {
+			if s.w == w.w {
+				// no new vertex, so v cannot get any closer
+				return g.touching(scale)
+			}
+		}
+		scale = math.Max(scale, w.w.LengthSquared())
+		simplex, lambda, v := closestOnSimplex(append(g.simplex, w))
+		if len(simplex) == 4 {
+			g.simplex, g.lambda, g.v, g.intersect = simplex, lambda, v, true
+			return g
+		}
+		if v.LengthSquared() >= vv {
+			break
+		}
+		g.simplex, g.lambda, g.v = simplex, lambda, v
+	}
+	return g.touching(scale)
+}
+
+// touching sets intersect if v is too close to the origin to tell the
+// shapes apart.
+func (g gjkState) touching(scale float64) gjkState {
+	g.intersect = g.v.LengthSquared() <= gjkTolerance*gjkTolerance*scale
+	return g
+}
+
+// witnesses returns the points of a and b whose difference is v.
+func (g gjkState) witnesses() (pa, pb Point) {
+	var sa, sb Vec3
+	for i, s := range g.simplex {
+		sa = sa.Add(s.pa.Vec3().Mul(g.lambda[i]))
+		sb = sb.Add(s.pb.Vec3().Mul(g.lambda[i]))
+	}
+	return sa.Point(), sb.Point()
+}
+
+// enclose grows the simplex from GJK into a tetrahedron that encloses
+// the origin. It returns false if a − b is flat.
+func (g gjkState) enclose(a, b ConvexShape) ([]supportPoint, bool) {
+	verts := append([]supportPoint(nil), g.simplex...)
+	eps := epaTolerance * math.Sqrt(g.scale())
+	if len(verts) == 1 {
+		for _, d := range []Vec3{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}, {Z: 1}, {Z: -1}} {
+			if w := minkowskiSupport(a, b, d); w.w.Sub(verts[0].w).Length() > eps {
+				verts = append(verts, w)
+				break
+			}
+		}
+	}
+	if len(verts) == 2 {
+		u := verts[1].w.Sub(verts[0].w)
+		line := Line{Origin: verts[0].w.Point(), Direction: u}
+		d := u.anyPerpendicular()
+		rot := Mat3FromAxisAngle(u.NormalizeOrZero(), math.Pi/3)
+		for i := 0; i < 6; i++ {
+			if w := minkowskiSupport(a, b, d); line.DistanceTo(w.w.Point()) > eps {
+				verts = append(verts, w)
+				break
+			}
+			d = rot.MulVec3(d)
+		}
+	}
+	if len(verts) == 3 {
+		n, ok := verts[1].w.Sub(verts[0].w).Cross(verts[2].w.Sub(verts[0].w)).Normalized()
+		if !ok {
+			return nil, false
+		}
+		for _, d := range []Vec3{n, n.Mul(-1)} {
+			if w := minkowskiSupport(a, b, d); math.Abs(w.w.Sub(verts[0].w).Dot(n)) > eps {
+				verts = append(verts, w)
+				break
+			}
+		}
+	}
+	return verts, len(verts) == 4
+}
+
+// flatNormal returns a direction perpendicular to the flat simplex.
+func (g gjkState) flatNormal() Vec3 {
+	s := g.simplex
+	if len(s) >= 3 {
+		if n, ok := s[1].w.Sub(s[0].w).Cross(s[2].w.Sub(s[0].w)).Normalized(); ok {
+			return n
+		}
+	}
+	if len(s) >= 2 {
+		if n, ok := s[1].w.Sub(s[0].w).anyPerpendicular().Normalized(); ok {
+			return n
+		}
+	}
+	return Vec3{X: 1}
+}
+
+// scale returns the largest squared length of a simplex vertex.
+func (g gjkState) scale() float64 {
+	var scale float64
+	for _, s := range g.simplex {
+		scale = math.Max(scale, s.w.LengthSquared())
+	}
+	return scale
+}
+
+// closestOnSimplex returns the smallest face of the simplex that holds
+// the point nearest the origin, the weights of that point, and the point.
+// A tetrahedron that encloses the origin is returned whole.
+func closestOnSimplex(s []supportPoint) ([]supportPoint, []float64, Vec3) {
+	switch len(s) {
+	case 2:
+		return closestOnSegment(s[0], s[1])
+	case 3:
+		return closestOnTriangle(s[0], s[1], s[2])
+	case 4:
+		return closestOnTetrahedron(s)
+	}
+	return s, []float64{1}, s[0].w
+}
+
+// closestOnSegment is closestOnSimplex for two vertices.
+func closestOnSegment(a, b supportPoint) ([]supportPoint, []float64, Vec3) {
+	ab := b.w.Sub(a.w)
+	t := -a.w.Dot(ab)
+	if t <= 0 {
+		return []supportPoint{a}, []float64{1}, a.w
+	}
+	denom := ab.LengthSquared()
+	if t >= denom {
+		return []supportPoint{b}, []float64{1}, b.w
+	}
+	t /= denom
+	return []supportPoint{a, b}, []float64{1 - t, t}, a.w.Add(ab.Mul(t))
+}
+
+// closestOnTriangle is closestOnSimplex for three vertices. It follows
+// the same Voronoi region tests as Triangle.ClosestPointTo.
+func closestOnTriangle(a, b, c supportPoint) ([]supportPoint, []float64, Vec3) {
+	ab, ac := b.w.Sub(a.w), c.w.Sub(a.w)
+	ap := a.w.Mul(-1)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return []supportPoint{a}, []float64{1}, a.w
+	}
+	bp := b.w.Mul(-1)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return []supportPoint{b}, []float64{1}, b.w
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return []supportPoint{a, b}, []float64{1 - v, v}, a.w.Add(ab.Mul(v))
+	}
+	cp := c.w.Mul(-1)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return []supportPoint{c}, []float64{1}, c.w
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return []supportPoint{a, c}, []float64{1 - w, w}, a.w.Add(ac.Mul(w))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return []supportPoint{b, c}, []float64{1 - w, w}, b.w.Add(c.w.Sub(b.w).Mul(w))
+	}
+	denom := 1 / (va + vb + vc)
+	v, w := vb*denom, vc*denom
+	return []supportPoint{a, b, c}, []float64{1 - v - w, v, w}, a.w.Add(ab.Mul(v)).Add(ac.Mul(w))
+}
+
+// closestOnTetrahedron is closestOnSimplex for four vertices.
+func closestOnTetrahedron(s []supportPoint) ([]supportPoint, []float64, Vec3) {
+	faces := [4][4]int{{0, 1, 2, 3}, {0, 1, 3, 2}, {0, 2, 3, 1}, {1, 2, 3, 0}}
+	var best []supportPoint
+	var bestLambda []float64
+	var bestV Vec3
+	bestDist := math.Inf(1)
+	// a sliver has no reliable inside; look at every face instead
+	var edge2 float64
+	for i := 1; i < 4; i++ {
+		edge2 = math.Max(edge2, s[i].w.Sub(s[0].w).LengthSquared())
+	}
+	vol := s[1].w.Sub(s[0].w).ScalarTriple(s[2].w.Sub(s[0].w), s[3].w.Sub(s[0].w))
+	flat := math.Abs(vol) <= 1e-9*edge2*math.Sqrt(edge2)
+	for _, f := range faces {
+		a, b, c, d := s[f[0]].w, s[f[1]].w, s[f[2]].w, s[f[3]].w
+		n := b.Sub(a).Cross(c.Sub(a))
+		// the origin is on the far side of the face from the fourth vertex
+		dd, od := n.Dot(d.Sub(a)), -n.Dot(a)
+		if !flat && dd*od >= 0 {
+			continue
+		}
+		simplex, lambda, v := closestOnTriangle(s[f[0]], s[f[1]], s[f[2]])
+		if dist := v.LengthSquared(); dist < bestDist {
+			best, bestLambda, bestV, bestDist = simplex, lambda, v, dist
+		}
+	}
+	if best == nil {
+		// the origin is inside; weight the vertices to reach it
+		t := Tetrahedron{A: s[0].w.Point(), B: s[1].w.Point(), C: s[2].w.Point(), D: s[3].w.Point()}
+		if bary, ok := t.Barycentric(Point{}); ok {
+			return s, bary[:], Vec3{}
+		}
+		return s, []float64{0.25, 0.25, 0.25, 0.25}, Vec3{}
+	}
+	return best, bestLambda, bestV
+}
+
+// epaFace is a triangle of the expanding polytope with its outward unit
+// normal and its distance from the origin.
+type epaFace struct {
+	a, b, c int
+	normal  Vec3
+	dist    float64
+}
+
+// epa expands the tetrahedron, which must enclose the origin, towards
+// the boundary of a − b nearest the origin. It returns false if every
+// face of the tetrahedron is degenerate.
+func epa(a, b ConvexShape, tetra []supportPoint) (Contact, bool) {
+	verts := append([]supportPoint(nil), tetra...)
+	newFace := func(i, j, k int) (epaFace, bool) {
+		n, ok := verts[j].w.Sub(verts[i].w).Cross(verts[k].w.Sub(verts[i].w)).Normalized()
+		return epaFace{a: i, b: j, c: k, normal: n, dist: n.Dot(verts[i].w)}, ok
+	}
+	var faces []epaFace
+	for _, f := range [4][4]int{{0, 1, 2, 3}, {0, 3, 1, 2}, {0, 2, 3, 1}, {1, 3, 2, 0}} {
+		face, ok := newFace(f[0], f[1], f[2])
+		if !ok {
+			continue
+		}
+		if face.normal.Dot(verts[f[3]].w.Sub(verts[f[0]].w)) > 0 {
+			// wind the face so its normal points away from the fourth vertex
+			face, _ = newFace(f[0], f[2], f[1])
+		}
+		faces = append(faces, face)
+	}
+	if len(faces) == 0 {
+		return Contact{}, false
+	}
+	best := faces[0]
+	for i := 0; i < epaMaxIterations; i++ {
+		best = faces[0]
+		for _, f := range faces[1:] {
+			if f.dist < best.dist {
+				best = f
+			}
+		}
+		w := minkowskiSupport(a, b, best.normal)
+		if w.w.Dot(best.normal)-best.dist <= epaTolerance*math.Max(1, best.dist) {
+			break
+		}
+		// remove the faces that w can see and stitch the hole to w
+		type edge struct{ i, j int }
+		var horizon []edge
+		kept := faces[:0]
+		for _, f := range faces {
+			if f.normal.Dot(w.w.Sub(verts[f.a].w)) <= 0 {
+				kept = append(kept, f)
+				continue
+			}
+			for _, e := range []edge{{f.a, f.b}, {f.b, f.c}, {f.c, f.a}} {
+				shared := false
+				for k, h := range horizon {
+					if h.i == e.j && h.j == e.i {
+						horizon = append(horizon[:k], horizon[k+1:]...)
+						shared = true
+						break
+					}
+				}
+				if !shared {
+					horizon = append(horizon, e)
+				}
+			}
+		}
+		faces = kept
+		verts = append(verts, w)
+		for _, e := range horizon {
+			if face, ok := newFace(e.i, e.j, len(verts)-1); ok {
+				faces = append(faces, face)
+			}
+		}
+		if len(faces) == 0 {
+			break
+		}
+	}
+	return Contact{Normal: best.normal, Depth: math.Max(best.dist, 0)}, true
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestGJKDistance(t *testing.T) {
+	box := math3d.AABB{Min: math3d.Point{X: -1, Y: -1, Z: -1}, Max: math3d.Point{X: 1, Y: 1, Z: 1}}
+	for _, tt := range []struct {
+		name   string
+		a, b   math3d.ConvexShape
+		dist   float64
+		pa, pb math3d.Point
+	}{
+		{"sphere box", math3d.Sphere{Center: math3d.Point{X: 4}, Radius: 1}, box, 2, math3d.Point{X: 3}, math3d.Point{X: 1}},
+		{"box segment", box, math3d.Segment{A: math3d.Point{X: 3, Y: 3, Z: -5}, B: math3d.Point{X: 3, Y: 3, Z: 5}}, 2 * math.Sqrt2, math3d.Point{X: 1, Y: 1}, math3d.Point{X: 3, Y: 3}},
+		{"triangle capsule", math3d.Triangle{A: math3d.Point{X: -1, Y: -1}, B: math3d.Point{X: 1, Y: -1}, C: math3d.Point{Y: 1}},
+			math3d.Capsule{Segment: math3d.Segment{A: math3d.Point{Z: 2}, B: math3d.Point{Z: 4}}, Radius: 0.5}, 1.5, math3d.Point{}, math3d.Point{Z: 1.5}},
+		{"overlapping", math3d.Sphere{Center: math3d.Point{X: 1}, Radius: 1}, box, 0, math3d.Point{}, math3d.Point{}},
+	} {
+		// closest points on curved surfaces converge more slowly than the distance
+		dist, pa, pb := math3d.GJKDistance(tt.a, tt.b)
+		if math.Abs(dist-tt.dist) > 1e-9 {
+			t.Errorf("GJKDistance: %s: want %v, got %v\n", tt.name, tt.dist, dist)
+		}
+		if tt.dist > 0 && (!pa.ApproxEqual(tt.pa, 1e-5) || !pb.ApproxEqual(tt.pb, 1e-5)) {
+			t.Errorf("GJKDistance: %s: want %v %v, got %v %v\n", tt.name, tt.pa, tt.pb, pa, pb)
+		}
+		if tt.dist == 0 && (!pa.ApproxEqual(pb, 1e-9) || !box.ContainsPoint(pb)) {
+			t.Errorf("GJKDistance: %s: want a common point, got %v %v\n", tt.name, pa, pb)
+		}
+		if got := math3d.GJKIntersects(tt.a, tt.b); got != (tt.dist == 0) {
+			t.Errorf("GJKIntersects: %s: want %v, got %v\n", tt.name, tt.dist == 0, got)
+		}
+	}
+}
+
+func TestEPAContact(t *testing.T) {
+	a := math3d.OBB{HalfExtents: math3d.NewVec3(1, 1, 1), Orientation: math3d.Identity3()}
+	b := math3d.OBB{Center: math3d.Point{Y: 1.9}, HalfExtents: math3d.NewVec3(1, 1, 1), Orientation: math3d.Mat3FromRotationY(0.3)}
+	want, _ := math3d.IntersectsOBBOBB(a, b)
+	if got, ok := math3d.EPAContact(a, b); !ok || !got.Normal.ApproxEqual(want.Normal, 1e-9) || math.Abs(got.Depth-want.Depth) > 1e-9 {
+		t.Errorf("EPAContact: boxes: want %v, got %v %v\n", want, got, ok)
+	}
+
+	s1 := math3d.Sphere{Radius: 1}
+	s2 := math3d.Sphere{Center: math3d.Point{X: 0.6, Y: 0.8}, Radius: 0.5}
+	want, _ = math3d.IntersectsSphereSphere(s1, s2)
+	if got, ok := math3d.EPAContact(s1, s2); !ok || !got.Normal.ApproxEqual(want.Normal, 1e-4) || math.Abs(got.Depth-want.Depth) > 1e-6 {
+		t.Errorf("EPAContact: spheres: want %v, got %v %v\n", want, got, ok)
+	}
+
+	if _, ok := math3d.EPAContact(s1, math3d.Sphere{Center: math3d.Point{Z: 3}, Radius: 1}); ok {
+		t.Errorf("EPAContact: apart: want false, got true\n")
+	}
+
+	// degenerate shapes have no volume to expand, so the contact is flat
+	for _, h := range []float64{0, 1e-200} {
+		p := math3d.OBB{HalfExtents: math3d.NewVec3(h, h, h), Orientation: math3d.Identity3()}
+		if got, ok := math3d.EPAContact(p, p); !ok || got.Depth > 1e-12 || math.Abs(got.Normal.Length()-1) > 1e-12 {
+			t.Errorf("EPAContact: degenerate %g: want flat contact, got %v %v\n", h, got, ok)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Homography is a projective transform of the plane, stored as a
+// 3×3 matrix acting on homogeneous coordinates (x, y, 1).
+type Homography Mat3
+
+// EstimateHomography returns the homography that maps each src point
+// onto the corresponding dst point, using the normalized direct linear
+// transform (DLT). With more than four correspondences the result is
+// the least-squares fit of the algebraic error. It returns false if
+// there are fewer than four correspondences or the points are degenerate,
+// such as when three of four points are collinear.
+func EstimateHomography(src, dst []Vec2) (Homography, bool) {
+	n := len(src)
+	if n < 4 || len(dst) != n {
+		return Homography{}, false
+	}
+	// Hartley normalization conditions the system for accuracy
+	ts, ok := normalizingTransform2D(src)
+	if !ok {
+		return Homography{}, false
+	}
+	td, ok := normalizingTransform2D(dst)
+	if !ok {
+		return Homography{}, false
+	}
+
+	// each correspondence contributes two rows to A, and h is the
+	// unit vector minimizing |Ah|, the eigenvector of AᵀA with the
+	// smallest eigenvalue
+	ata := NewMatrix(9, 9)
+	for i := range src {
+		s, d := ts.TransformPoint2D(src[i]), td.TransformPoint2D(dst[i])
+		for _, row := range [2][9]float64{
+			{-s.X, -s.Y, -1, 0, 0, 0, d.X * s.X, d.X * s.Y, d.X},
+			{0, 0, 0, -s.X, -s.Y, -1, d.Y * s.X, d.Y * s.Y, d.Y},
+		} {
+			for j := 0; j < 9; j++ {
+				for k := j; k < 9; k++ {
+					ata[j][k] += row[j] * row[k]
+				}
+			}
+		}
+	}
+	values, vectors := ata.SymmetricEigen()
+	if values[1] <= 1e-12*values[8] {
+		// the null space is not unique
+		return Homography{}, false
+	}
+	h := vectors.Col(0)
+	hn := Mat3{{h[0], h[1], h[2]}, {h[3], h[4], h[5]}, {h[6], h[7], h[8]}}
+
+	// undo the normalization
+	tdi, _ := td.Inverse()
+	m := tdi.Mul(hn).Mul(ts)
+	if m[2][2] != 0 {
+		m = m.MulScalar(1 / m[2][2])
+	}
+	if m.Determinant() == 0 {
+		return Homography{}, false
+	}
+	return Homography(m), true
+}
+
+// Apply maps the point v through the homography. Points mapped to the
+// line at infinity have infinite or NaN components.
+func (h Homography) Apply(v Vec2) Vec2 {
+	p := Mat3(h).MulVec3(Vec3{X: v.X, Y: v.Y, Z: 1})
+	return Vec2{X: p.X / p.Z, Y: p.Y / p.Z}
+}
+
+// Inverse returns the homography that undoes h.
+// It returns false if h is singular.
+func (h Homography) Inverse() (Homography, bool) {
+	m, ok := Mat3(h).Inverse()
+	if !ok {
+		return Homography{}, false
+	}
+	if m[2][2] != 0 {
+		m = m.MulScalar(1 / m[2][2])
+	}
+	return Homography(m), true
+}
+
+// normalizingTransform2D returns the similarity transform that moves the
+// centroid of the points to the origin and scales them so their average
+// distance from it is √2.
+func normalizingTransform2D(points []Vec2) (Mat3, bool) {
+	var c Vec2
+	for _, p := range points {
+		c = c.Add(p)
+	}
+	c = c.Div(float64(len(points)))
+	var d float64
+	for _, p := range points {
+		d += p.Sub(c).Length()
+	}
+	d /= float64(len(points))
+	if d == 0 {
+		return Mat3{}, false
+	}
+	s := math.Sqrt2 / d
+	return Mat3{{s, 0, -s * c.X}, {0, s, -s * c.Y}, {0, 0, 1}}, true
+}
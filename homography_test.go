@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"testing"
+)
+
+func TestHomography(t *testing.T) {
+	expect := math3d.Homography{{2, 0.3, 5}, {-0.2, 1.5, -3}, {0.001, 0.002, 1}}
+	var src, dst []math3d.Vec2
+	for _, p := range []math3d.Vec2{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 80}, {X: 0, Y: 80}, {X: 40, Y: 30}} {
+		src = append(src, p)
+		dst = append(dst, expect.Apply(p))
+	}
+
+	for _, n := range []int{4, 5} {
+		h, ok := math3d.EstimateHomography(src[:n], dst[:n])
+		if !ok {
+			t.Fatalf("EstimateHomography: %d points: want ok, got false\n", n)
+		}
+		if !math3d.Mat3(h).ApproxEqual(math3d.Mat3(expect), 1e-9) {
+			t.Errorf("EstimateHomography: %d points: want %v, got %v\n", n, expect, h)
+		}
+		inv, _ := h.Inverse()
+		if p := inv.Apply(h.Apply(src[4])); !p.ApproxEqual(src[4], 1e-9) {
+			t.Errorf("Inverse: want %v, got %v\n", src[4], p)
+		}
+	}
+
+	collinear := []math3d.Vec2{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}
+	if _, ok := math3d.EstimateHomography(collinear, collinear); ok {
+		t.Errorf("EstimateHomography: collinear: want false, got true\n")
+	}
+}
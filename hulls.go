@@ -0,0 +1,388 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"sort"
+)
+
+// ConvexHull is a convex polyhedron given by its corners and triangular
+// faces. Each face holds the indices of three corners in Vertices, in
+// counter-clockwise order seen from outside the hull, so that the normals
+// of the faces point outward. Larger flat faces are split into several
+// triangles.
+type ConvexHull struct {
+	Vertices []Point
+	Faces    [][3]int
+}
+
+// ConvexHull2D returns the corners of the convex hull of the points in
+// counter-clockwise order, starting from the point with the least x and
+// then the least y, using Andrew's monotone chain algorithm in
+// O(n log n) time. Points that lie on an edge of the hull between two
+// corners are included, in order, when keepCollinear is true, and left
+// out otherwise. Duplicate points appear once. The hull of collinear
+// points is the two ends of the line they lie on, or all of the points
+// in order along it when keepCollinear is true.
+func ConvexHull2D(points []Vec2, keepCollinear bool) []Vec2 {
+	sorted := make([]Vec2, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	sorted = unique
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	first, last := sorted[0], sorted[len(sorted)-1]
+	flat := true
+	for _, p := range sorted[1 : len(sorted)-1] {
+		if orient2D(first, last, p) != 0 {
+			flat = false
+			break
+		}
+	}
+	if flat {
+		if keepCollinear {
+			return sorted
+		}
+		return []Vec2{first, last}
+	}
+
+	// a chain turns right, and loses its last point, when the turn is
+	// clockwise, or straight too unless collinear points are kept
+	right := func(a, b, c Vec2) bool {
+		o := orient2D(a, b, c)
+		return o < 0 || o == 0 && !keepCollinear
+	}
+	hull := make([]Vec2, 0, 2*len(sorted))
+	for _, p := range sorted {
+		for len(hull) >= 2 && right(hull[len(hull)-2], hull[len(hull)-1], p) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull)
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) > lower && right(hull[len(hull)-2], hull[len(hull)-1], p) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// the upper chain ends where the lower chain began
+	return hull[:len(hull)-1]
+}
+
+// ConvexHull3D returns the convex hull of the points, using the
+// quickhull algorithm of Barber, Dobkin, and Huhdanpaa (1996). Points
+// within a small tolerance of the hull, relative to the size of their
+// coordinates, count as on it, so points on the faces or edges of the
+// hull, or repeated points, are not made corners; when several points
+// are equally far out, the least in x, then y, then z is taken, which is
+// always a true corner. If all the points lie in a plane, the hull is
+// their convex polygon, with a face on each side and no volume. It
+// returns false if there are fewer than three points that are not on a
+// line.
+func ConvexHull3D(points []Point) (ConvexHull, bool) {
+	if len(points) == 0 {
+		return ConvexHull{}, false
+	}
+	var scale float64
+	for _, p := range points {
+		scale = math.Max(scale, math.Max(math.Abs(p.X), math.Max(math.Abs(p.Y), math.Abs(p.Z))))
+	}
+	h := &hullBuilder{points: points, eps: hullTolerance * scale, edges: map[[2]int]int{}}
+	all := make([]int, len(points))
+	for i := range all {
+		all[i] = i
+	}
+
+	// the initial tetrahedron, each corner furthest from the ones before
+	i0, _ := h.furthest(all, func(p Point) float64 { return 0 })
+	i1, d := h.furthest(all, func(p Point) float64 { return p.Distance(points[i0]) })
+	if d <= h.eps {
+		return ConvexHull{}, false
+	}
+	dir := points[i1].Sub(points[i0]).Div(d)
+	i2, d := h.furthest(all, func(p Point) float64 { return p.Sub(points[i0]).Cross(dir).Length() })
+	if d <= h.eps {
+		return ConvexHull{}, false
+	}
+	n, _ := points[i1].Sub(points[i0]).Cross(points[i2].Sub(points[i0])).Normalized()
+	i3, d := h.furthest(all, func(p Point) float64 { return math.Abs(n.Dot(p.Sub(points[i0]))) })
+	if d <= h.eps {
+		return h.flat(points[i0], n)
+	}
+	if n.Dot(points[i3].Sub(points[i0])) > 0 {
+		i1, i2 = i2, i1
+	}
+	h.addFace(i0, i1, i2)
+	h.addFace(i0, i3, i1)
+	h.addFace(i1, i3, i2)
+	h.addFace(i2, i3, i0)
+	rest := make([]int, 0, len(points))
+	for _, i := range all {
+		if i != i0 && i != i1 && i != i2 && i != i3 {
+			rest = append(rest, i)
+		}
+	}
+	h.assign(rest, 0)
+
+	// new faces are appended, so one pass reaches them all
+	for f := 0; f < len(h.faces); f++ {
+		if !h.faces[f].dead && len(h.faces[f].outside) != 0 {
+			h.expand(f)
+		}
+	}
+	return h.hull(), true
+}
+
+// Support returns the corner of the hull furthest in the direction d.
+// It implements the ConvexShape interface.
+func (c ConvexHull) Support(d Vec3) Point {
+	return furthestPoint(d, c.Vertices...)
+}
+
+// Volume returns the volume enclosed by the faces of the hull.
+func (c ConvexHull) Volume() float64 {
+	var v float64
+	for _, f := range c.Faces {
+		a, b, cc := c.Vertices[f[0]].Vec3(), c.Vertices[f[1]].Vec3(), c.Vertices[f[2]].Vec3()
+		v += a.ScalarTriple(b, cc)
+	}
+	return math.Abs(v) / 6
+}
+
+// hullTolerance is the distance, relative to the largest coordinate of
+// the points, within which a point counts as on a face of a hull.
+const hullTolerance = 1e-12
+
+// hullFace is a face of a hull under construction, with its outward unit
+// normal and offset from the origin, and the points outside it that are
+// yet to be added.
+type hullFace struct {
+	v       [3]int
+	normal  Vec3
+	offset  float64
+	outside []int
+	dead    bool
+}
+
+// hullBuilder holds the state of ConvexHull3D. Each directed edge of a
+// live face maps to that face, so the neighbor across the edge from a to
+// b is the face holding the edge from b to a.
+type hullBuilder struct {
+	points []Point
+	eps    float64
+	faces  []hullFace
+	edges  map[[2]int]int
+}
+
+// addFace adds the face with corners a, b, and c.
+func (h *hullBuilder) addFace(a, b, c int) {
+	pa := h.points[a]
+	n, _ := h.points[b].Sub(pa).Cross(h.points[c].Sub(pa)).Normalized()
+	h.faces = append(h.faces, hullFace{v: [3]int{a, b, c}, normal: n, offset: n.Dot(pa.Vec3())})
+	f := len(h.faces) - 1
+	h.edges[[2]int{a, b}], h.edges[[2]int{b, c}], h.edges[[2]int{c, a}] = f, f, f
+}
+
+// assign moves each point to the outside set of the face from the given
+// one on that it is furthest above, dropping the points that are inside
+// those faces.
+func (h *hullBuilder) assign(points []int, from int) {
+	for _, i := range points {
+		best, height := -1, h.eps
+		for f := from; f < len(h.faces); f++ {
+			if d := h.height(f, i); !h.faces[f].dead && d > height {
+				best, height = f, d
+			}
+		}
+		if best >= 0 {
+			h.faces[best].outside = append(h.faces[best].outside, i)
+		}
+	}
+}
+
+// expand adds the point furthest outside face f to the hull, replacing
+// the faces it can see with a cone of faces from the edge of that region
+// to the point. Faces whose planes the point lies in are replaced too, so
+// that corners left in the middle of a flat face or a straight edge by
+// earlier steps are removed.
+func (h *hullBuilder) expand(f int) {
+	p, _ := h.furthest(h.faces[f].outside, func(q Point) float64 {
+		return h.faces[f].normal.Dot(q.Vec3()) - h.faces[f].offset
+	})
+	visible := []int{f}
+	h.faces[f].dead = true
+	var horizon [][2]int
+	for k := 0; k < len(visible); k++ {
+		v := h.faces[visible[k]].v
+		for e := range v {
+			a, b := v[e], v[(e+1)%3]
+			g := h.edges[[2]int{b, a}]
+			if h.faces[g].dead {
+				continue
+			}
+			if h.height(g, p) >= -h.eps {
+				h.faces[g].dead = true
+				visible = append(visible, g)
+			} else {
+				horizon = append(horizon, [2]int{a, b})
+			}
+		}
+	}
+
+	var orphans []int
+	for _, g := range visible {
+		v := h.faces[g].v
+		delete(h.edges, [2]int{v[0], v[1]})
+		delete(h.edges, [2]int{v[1], v[2]})
+		delete(h.edges, [2]int{v[2], v[0]})
+		for _, i := range h.faces[g].outside {
+			if i != p {
+				orphans = append(orphans, i)
+			}
+		}
+		h.faces[g].outside = nil
+	}
+	from := len(h.faces)
+	for _, e := range horizon {
+		h.addFace(e[0], e[1], p)
+	}
+	h.assign(orphans, from)
+}
+
+// flat returns the hull of points that all lie in the plane through o
+// with the unit normal n: their convex polygon, fanned into triangles on
+// both sides.
+func (h *hullBuilder) flat(o Point, n Vec3) (ConvexHull, bool) {
+	t, b := BuildOrthonormalBasis(n)
+	proj := make([]Vec2, len(h.points))
+	index := map[Vec2]int{}
+	for i, p := range h.points {
+		d := p.Sub(o)
+		proj[i] = Vec2{X: d.Dot(t), Y: d.Dot(b)}
+		if _, ok := index[proj[i]]; !ok {
+			index[proj[i]] = i
+		}
+	}
+	// drop the corners that rounding in the projection left just off the
+	// line through their neighbors
+	corners := ConvexHull2D(proj, false)
+	for i := 0; len(corners) > 3 && i < len(corners); {
+		prev, next := corners[(i+len(corners)-1)%len(corners)], corners[(i+1)%len(corners)]
+		if orient2D(prev, corners[i], next) <= h.eps*next.Sub(prev).Length() {
+			corners = append(corners[:i], corners[i+1:]...)
+			i = 0
+			continue
+		}
+		i++
+	}
+	if len(corners) < 3 {
+		return ConvexHull{}, false
+	}
+	var c ConvexHull
+	for _, q := range corners {
+		c.Vertices = append(c.Vertices, h.points[index[q]])
+	}
+	for i := 1; i+1 < len(corners); i++ {
+		c.Faces = append(c.Faces, [3]int{0, i, i + 1}, [3]int{0, i + 1, i})
+	}
+	return c, true
+}
+
+// furthest returns the candidate with the greatest score and its score.
+// Among the candidates within the tolerance of the greatest, it takes the
+// least in x, then y, then z, which is a corner of their convex hull.
+func (h *hullBuilder) furthest(candidates []int, score func(Point) float64) (int, float64) {
+	best := math.Inf(-1)
+	for _, i := range candidates {
+		best = math.Max(best, score(h.points[i]))
+	}
+	pick := -1
+	for _, i := range candidates {
+		if score(h.points[i]) >= best-h.eps && (pick < 0 || pointLess(h.points[i], h.points[pick])) {
+			pick = i
+		}
+	}
+	return pick, best
+}
+
+// height returns the signed distance of point i above the plane of face f.
+func (h *hullBuilder) height(f, i int) float64 {
+	return h.faces[f].normal.Dot(h.points[i].Vec3()) - h.faces[f].offset
+}
+
+// hull returns the live faces and the points they use.
+func (h *hullBuilder) hull() ConvexHull {
+	var c ConvexHull
+	index := map[int]int{}
+	for _, f := range h.faces {
+		if f.dead {
+			continue
+		}
+		var face [3]int
+		for k, i := range f.v {
+			j, ok := index[i]
+			if !ok {
+				j = len(c.Vertices)
+				index[i] = j
+				c.Vertices = append(c.Vertices, h.points[i])
+			}
+			face[k] = j
+		}
+		c.Faces = append(c.Faces, face)
+	}
+	return c
+}
+
+// orient2D returns twice the signed area of the triangle abc, which is
+// positive when a, b, and c are in counter-clockwise order.
+func orient2D(a, b, c Vec2) float64 {
+	return b.Sub(a).Cross(c.Sub(a))
+}
+
+// pointLess reports whether a comes before b in order of x, then y, then z.
+func pointLess(a, b Point) bool {
+	if a.X != b.X {
+		return a.X < b.X
+	}
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+	return a.Z < b.Z
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestConvexHull2D(t *testing.T) {
+	v := math3d.NewVec2
+	square := []math3d.Vec2{
+		v(1, 1), v(2, 0), v(0, 0), v(0, 2), v(1, 0), v(2, 2), v(0, 1), v(2, 1), v(1, 2), v(0.5, 1.5), v(2, 2),
+	}
+	for _, tt := range []struct {
+		name          string
+		points        []math3d.Vec2
+		keepCollinear bool
+		want          []math3d.Vec2
+	}{
+		{"empty", nil, false, []math3d.Vec2{}},
+		{"one point", []math3d.Vec2{v(3, 4), v(3, 4)}, false, []math3d.Vec2{v(3, 4)}},
+		{"square", square, false, []math3d.Vec2{v(0, 0), v(2, 0), v(2, 2), v(0, 2)}},
+		{"square with collinear", square, true, []math3d.Vec2{v(0, 0), v(1, 0), v(2, 0), v(2, 1), v(2, 2), v(1, 2), v(0, 2), v(0, 1)}},
+		{"line", []math3d.Vec2{v(2, 2), v(0, 0), v(1, 1), v(3, 3)}, false, []math3d.Vec2{v(0, 0), v(3, 3)}},
+		{"line with collinear", []math3d.Vec2{v(2, 2), v(0, 0), v(1, 1), v(3, 3)}, true, []math3d.Vec2{v(0, 0), v(1, 1), v(2, 2), v(3, 3)}},
+		{"triangle", []math3d.Vec2{v(0, 3), v(1, 1), v(-2, 0), v(2, 0), v(0, 0.5)}, false, []math3d.Vec2{v(-2, 0), v(2, 0), v(0, 3)}},
+	} {
+		got := math3d.ConvexHull2D(tt.points, tt.keepCollinear)
+		if len(got) != len(tt.want) {
+			t.Errorf("ConvexHull2D: %s: want %v, got %v\n", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ConvexHull2D: %s: want %v, got %v\n", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+	if square[0] != v(1, 1) {
+		t.Errorf("ConvexHull2D: want points unchanged, got %v\n", square)
+	}
+}
+
+func TestConvexHull3D(t *testing.T) {
+	// checkHull reports whether every point is inside or on every face and
+	// the faces form a closed surface
+	checkHull := func(name string, h math3d.ConvexHull, points []math3d.Point) {
+		edges := map[[2]int]int{}
+		for _, f := range h.Faces {
+			a, b, c := h.Vertices[f[0]], h.Vertices[f[1]], h.Vertices[f[2]]
+			n := b.Sub(a).Cross(c.Sub(a))
+			for _, p := range points {
+				if d := n.Dot(p.Sub(a)); d > 1e-9 {
+					t.Errorf("ConvexHull3D: %s: want %v inside face %v, got %v above\n", name, p, f, d)
+				}
+			}
+			for i := range f {
+				edges[[2]int{f[i], f[(i+1)%3]}]++
+			}
+		}
+		for e, n := range edges {
+			if n != 1 || edges[[2]int{e[1], e[0]}] != 1 {
+				t.Errorf("ConvexHull3D: %s: want each edge once each way, got %v %d times\n", name, e, n)
+			}
+		}
+	}
+
+	// a cube sampled on a grid, so that most points are on its faces
+	var cube []math3d.Point
+	for x := 0; x <= 4; x++ {
+		for y := 0; y <= 4; y++ {
+			for z := 0; z <= 4; z++ {
+				cube = append(cube, math3d.Point{X: float64(x)/2 - 1, Y: float64(y)/2 + 3, Z: float64(z) / 2})
+			}
+		}
+	}
+	h, ok := math3d.ConvexHull3D(cube)
+	if !ok || len(h.Vertices) != 8 || len(h.Faces) != 12 {
+		t.Errorf("ConvexHull3D: cube: want 8 corners and 12 faces, got %d %d %v\n", len(h.Vertices), len(h.Faces), ok)
+	}
+	if got := h.Volume(); math.Abs(got-8) > 1e-12 {
+		t.Errorf("Volume: cube: want 8, got %v\n", got)
+	}
+	if got, want := h.Support(math3d.NewVec3(1, -1, 1)), (math3d.Point{X: 1, Y: 3, Z: 2}); got != want {
+		t.Errorf("Support: cube: want %v, got %v\n", want, got)
+	}
+	checkHull("cube", h, cube)
+
+	rng := rand.New(rand.NewSource(846))
+	var ball []math3d.Point
+	for len(ball) < 500 {
+		v := math3d.NewVec3(rng.Float64()*2-1, rng.Float64()*2-1, rng.Float64()*2-1)
+		if v.Length() <= 1 {
+			ball = append(ball, v.Mul(10).Point())
+		}
+	}
+	h, ok = math3d.ConvexHull3D(ball)
+	if v, e, f := len(h.Vertices), 3*len(h.Faces)/2, len(h.Faces); !ok || v-e+f != 2 {
+		t.Errorf("ConvexHull3D: ball: want V - E + F = 2, got %d - %d + %d %v\n", v, e, f, ok)
+	}
+	checkHull("ball", h, ball)
+
+	// points in a tilted plane give a flat hull
+	var flat []math3d.Point
+	for i := 0; i < 100; i++ {
+		u, v := float64(i%10), float64(i/10)
+		flat = append(flat, math3d.Point{X: u, Y: v, Z: u + 2*v})
+	}
+	h, ok = math3d.ConvexHull3D(flat)
+	if !ok || len(h.Vertices) != 4 || len(h.Faces) != 4 || h.Volume() != 0 {
+		t.Errorf("ConvexHull3D: flat: want 4 corners and 4 faces, got %v %v\n", h, ok)
+	}
+
+	for _, points := range [][]math3d.Point{nil, {{X: 1}, {X: 1}}, {{}, {X: 1, Y: 1, Z: 1}, {X: 3, Y: 3, Z: 3}}} {
+		if _, ok := math3d.ConvexHull3D(points); ok {
+			t.Errorf("ConvexHull3D(%v): want !ok, got ok\n", points)
+		}
+	}
+}
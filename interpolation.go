@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Clamp returns x limited to the range [min, max].
+func Clamp(x, min, max float64) float64 {
+	if x < min {
+		return min
+	} else if x > max {
+		return max
+	}
+	return x
+}
+
+// InverseLerp returns the parameter t such that Lerp(a, b, t) is v.
+// Returns 0 if a and b are equal.
+func InverseLerp(a, b, v float64) float64 {
+	if a == b {
+		return 0
+	}
+	return (v - a) / (b - a)
+}
+
+// Lerp returns the linear interpolation between a and b.
+// The result is exactly a when t is 0 and exactly b when t is 1.
+//
+//	lerp(a, b, t) = (1−t)a + tb
+func Lerp(a, b, t float64) float64 {
+	return (1-t)*a + t*b
+}
+
+// Remap maps v from the range [inMin, inMax] to the range [outMin, outMax].
+// Values outside the input range are extrapolated, not clamped.
+func Remap(v, inMin, inMax, outMin, outMax float64) float64 {
+	return Lerp(outMin, outMax, InverseLerp(inMin, inMax, v))
+}
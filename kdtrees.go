@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// KDTree is a k-d tree over a fixed set of points for nearest-neighbor
+// and radius queries. Queries return indexes into the slice the tree was
+// built from. The tree is balanced and is not changed by queries, so it
+// may be searched from several goroutines at once.
+type KDTree struct {
+	points []Point
+	// order holds the indexes of the points arranged as an implicit tree:
+	// the node for the range [lo, hi) is at the middle of it, with the
+	// nodes for the halves on either side.
+	order []int
+	// axis holds the splitting axis of the node at each position in order.
+	axis []int8
+}
+
+// KDTreeFromPoints builds a k-d tree over the points, splitting each
+// range at its median along the axis on which it is most spread out.
+// The tree keeps the slice, so it must not be changed afterwards.
+func KDTreeFromPoints(points []Point) *KDTree {
+	t := &KDTree{points: points, order: make([]int, len(points)), axis: make([]int8, len(points))}
+	for i := range t.order {
+		t.order[i] = i
+	}
+	t.build(0, len(points))
+	return t
+}
+
+// KNearest returns the indexes of the k points nearest p, nearest first.
+// It returns fewer than k indexes if the tree holds fewer points.
+func (t *KDTree) KNearest(p Point, k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	h := &kdHeap{}
+	t.search(p, 0, len(t.order), math.Inf(1), func(i int, d2 float64) float64 {
+		if h.Len() < k {
+			heap.Push(h, kdItem{index: i, dist2: d2})
+		} else if d2 < (*h)[0].dist2 {
+			(*h)[0] = kdItem{index: i, dist2: d2}
+			heap.Fix(h, 0)
+		}
+		if h.Len() < k {
+			return math.Inf(1)
+		}
+		return (*h)[0].dist2
+	})
+	result := make([]int, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(kdItem).index
+	}
+	return result
+}
+
+// Len returns the number of points in the tree.
+func (t *KDTree) Len() int {
+	return len(t.points)
+}
+
+// NearestNeighbor returns the index of the point nearest p and its
+// distance from p. It returns false if the tree is empty.
+func (t *KDTree) NearestNeighbor(p Point) (int, float64, bool) {
+	best, bestD2 := -1, math.Inf(1)
+	t.search(p, 0, len(t.order), math.Inf(1), func(i int, d2 float64) float64 {
+		if d2 < bestD2 {
+			best, bestD2 = i, d2
+		}
+		return bestD2
+	})
+	if best < 0 {
+		return 0, 0, false
+	}
+	return best, math.Sqrt(bestD2), true
+}
+
+// RadiusSearch returns the indexes of the points within distance r of p,
+// including those at exactly r, in no particular order.
+func (t *KDTree) RadiusSearch(p Point, r float64) []int {
+	var result []int
+	r2 := r * r
+	t.search(p, 0, len(t.order), math.Inf(1), func(i int, d2 float64) float64 {
+		if d2 <= r2 {
+			result = append(result, i)
+		}
+		return r2
+	})
+	return result
+}
+
+// build arranges order[lo:hi] into a subtree.
+func (t *KDTree) build(lo, hi int) {
+	if hi-lo <= 0 {
+		return
+	}
+	mid := (lo + hi) / 2
+	if hi-lo > 1 {
+		b := EmptyAABB()
+		for _, i := range t.order[lo:hi] {
+			b = b.Include(t.points[i])
+		}
+		size := b.Size()
+		axis := 0
+		if size.Y > size.X && size.Y >= size.Z {
+			axis = 1
+		} else if size.Z > size.X && size.Z > size.Y {
+			axis = 2
+		}
+		t.axis[mid] = int8(axis)
+		sub := t.order[lo:hi]
+		sort.Slice(sub, func(a, b int) bool {
+			return pointAxis(t.points[sub[a]], axis) < pointAxis(t.points[sub[b]], axis)
+		})
+	}
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+// search visits the points of the subtree for order[lo:hi] that may be
+// within the squared distance limit of p, nearest subtrees first. The
+// visit function is given each point's index and squared distance and
+// returns the new limit, which search returns when it is done.
+func (t *KDTree) search(p Point, lo, hi int, limit float64, visit func(i int, d2 float64) float64) float64 {
+	if hi <= lo {
+		return limit
+	}
+	mid := (lo + hi) / 2
+	i := t.order[mid]
+	limit = visit(i, p.Sub(t.points[i]).LengthSquared())
+	axis := int(t.axis[mid])
+	d := pointAxis(p, axis) - pointAxis(t.points[i], axis)
+	if d <= 0 {
+		limit = t.search(p, lo, mid, limit, visit)
+		if d*d <= limit {
+			limit = t.search(p, mid+1, hi, limit, visit)
+		}
+	} else {
+		limit = t.search(p, mid+1, hi, limit, visit)
+		if d*d <= limit {
+			limit = t.search(p, lo, mid, limit, visit)
+		}
+	}
+	return limit
+}
+
+// kdItem is a point found by KNearest.
+type kdItem struct {
+	index int
+	dist2 float64
+}
+
+// kdHeap is a max-heap of points by distance, so the furthest of the k
+// nearest found so far is on top.
+type kdHeap []kdItem
+
+func (h kdHeap) Len() int            { return len(h) }
+func (h kdHeap) Less(i, j int) bool  { return h[i].dist2 > h[j].dist2 }
+func (h kdHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *kdHeap) Push(x interface{}) { *h = append(*h, x.(kdItem)) }
+func (h *kdHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// pointAxis returns the coordinate of p along the axis with the given
+// index.
+func pointAxis(p Point, axis int) float64 {
+	switch axis {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	}
+	return p.Z
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(836))
+	random := func() math3d.Point {
+		return math3d.Point{X: rng.Float64()*10 - 5, Y: rng.Float64()*10 - 5, Z: rng.Float64() - 0.5}
+	}
+	points := make([]math3d.Point, 500)
+	for i := range points {
+		points[i] = random()
+	}
+	// duplicates must not confuse the splits
+	points = append(points, points[:20]...)
+	tree := math3d.KDTreeFromPoints(points)
+	if tree.Len() != len(points) {
+		t.Errorf("Len: want %d, got %d\n", len(points), tree.Len())
+	}
+
+	for q := 0; q < 50; q++ {
+		p := random()
+		byDistance := make([]int, len(points))
+		for i := range byDistance {
+			byDistance[i] = i
+		}
+		sort.SliceStable(byDistance, func(a, b int) bool {
+			return p.Distance(points[byDistance[a]]) < p.Distance(points[byDistance[b]])
+		})
+
+		if i, d, ok := tree.NearestNeighbor(p); !ok || d != p.Distance(points[byDistance[0]]) || points[i] != points[byDistance[0]] {
+			t.Errorf("NearestNeighbor(%v): want %v, got %v %v %v\n", p, points[byDistance[0]], points[i], d, ok)
+		}
+
+		got := tree.KNearest(p, 7)
+		if len(got) != 7 {
+			t.Fatalf("KNearest(%v): want 7 points, got %d\n", p, len(got))
+		}
+		for k, i := range got {
+			if want := p.Distance(points[byDistance[k]]); p.Distance(points[i]) != want {
+				t.Errorf("KNearest(%v): %d: want distance %v, got %v\n", p, k, want, p.Distance(points[i]))
+			}
+		}
+
+		want := 0
+		for _, i := range byDistance {
+			if p.Distance(points[i]) <= 1.5 {
+				want++
+			}
+		}
+		found := tree.RadiusSearch(p, 1.5)
+		for _, i := range found {
+			if p.Distance(points[i]) > 1.5 {
+				t.Errorf("RadiusSearch(%v): %v is too far\n", p, points[i])
+			}
+		}
+		if len(found) != want {
+			t.Errorf("RadiusSearch(%v): want %d points, got %d\n", p, want, len(found))
+		}
+	}
+
+	if got := tree.KNearest(math3d.Point{}, 1000); len(got) != len(points) {
+		t.Errorf("KNearest: want all %d points, got %d\n", len(points), len(got))
+	}
+	if _, _, ok := math3d.KDTreeFromPoints(nil).NearestNeighbor(math3d.Point{}); ok {
+		t.Errorf("NearestNeighbor: empty: want !ok, got ok\n")
+	}
+}
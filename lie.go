@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// The exponential and logarithm maps convert between rotations and
+// rotation vectors (elements of the Lie algebra so(3)). A rotation
+// vector points along the axis of rotation and its length is the
+// counter-clockwise angle in radians. Rotation vectors can be added,
+// scaled, and averaged, which makes them the natural space for
+// interpolating rotations and integrating angular velocity.
+
+// ExpSO3 returns the rotation matrix for the rotation vector omega
+// using Rodrigues' formula, with Taylor expansions for small angles.
+//
+//	R = I + (sinθ/θ)[ω]× + ((1 − cosθ)/θ²)[ω]×²
+func ExpSO3(omega Vec3) Mat3 {
+	theta2 := omega.LengthSquared()
+	var a, b float64
+	if theta2 < 1e-12 {
+		a, b = 1-theta2/6, 0.5-theta2/24
+	} else {
+		theta := math.Sqrt(theta2)
+		sin, cos := math.Sincos(theta)
+		a, b = sin/theta, (1-cos)/theta2
+	}
+	k := omega.Skew()
+	return Identity3().Add(k.MulScalar(a)).Add(k.Mul(k).MulScalar(b))
+}
+
+// LogSO3 returns the rotation vector for the rotation matrix r, with an
+// angle in [0, π]. It goes through the quaternion for r, which stays
+// accurate near 0 and π where the usual trace-based formula does not.
+func LogSO3(r Mat3) Vec3 {
+	return r.ToQuaternion().RotationVector()
+}
+
+// QuaternionFromRotationVector returns the unit quaternion for the
+// rotation vector omega. It is the quaternion exponential map.
+//
+//	q = (cos(θ/2), sin(θ/2) ω/θ)
+func QuaternionFromRotationVector(omega Vec3) Quaternion {
+	theta := omega.Length()
+	s := 0.5 - theta*theta/48
+	if theta >= 1e-6 {
+		s = math.Sin(theta/2) / theta
+	}
+	return Quaternion{W: math.Cos(theta / 2), X: omega.X * s, Y: omega.Y * s, Z: omega.Z * s}
+}
+
+// RotationVector returns the rotation vector for the unit quaternion q,
+// with an angle in [0, π]. It is the quaternion logarithm map.
+func (q Quaternion) RotationVector() Vec3 {
+	if q.W < 0 {
+		// q and −q are the same rotation; take the shorter way around
+		q = Quaternion{W: -q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+	}
+	v := Vec3{X: q.X, Y: q.Y, Z: q.Z}
+	n := v.Length()
+	if n == 0 {
+		return Vec3{}
+	}
+	return v.Mul(2 * math.Atan2(n, q.W) / n)
+}
+
+// Twist is an element of the Lie algebra se(3), the 6-vector describing
+// a rigid motion as a rotation vector Omega and a linear velocity V.
+// Applied for unit time, a twist moves along a screw: a rotation about
+// an axis combined with a translation along it.
+type Twist struct {
+	Omega, V Vec3
+}
+
+// Mul returns the twist scaled by scalar, which scales the motion
+// along the same screw.
+func (tw Twist) Mul(scalar float64) Twist {
+	return Twist{Omega: tw.Omega.Mul(scalar), V: tw.V.Mul(scalar)}
+}
+
+// ExpSE3 returns the rigid transform reached by following the twist
+// for unit time.
+//
+//	R = exp([ω]×)
+//	t = (I + ((1 − cosθ)/θ²)[ω]× + ((θ − sinθ)/θ³)[ω]×²) v
+func ExpSE3(tw Twist) Affine {
+	theta2 := tw.Omega.LengthSquared()
+	var b, c float64
+	if theta2 < 1e-12 {
+		b, c = 0.5-theta2/24, 1.0/6-theta2/120
+	} else {
+		theta := math.Sqrt(theta2)
+		sin, cos := math.Sincos(theta)
+		b, c = (1-cos)/theta2, (theta-sin)/(theta2*theta)
+	}
+	k := tw.Omega.Skew()
+	v := Identity3().Add(k.MulScalar(b)).Add(k.Mul(k).MulScalar(c))
+	return AffineFromLinear(ExpSO3(tw.Omega), v.MulVec3(tw.V))
+}
+
+// LogSE3 returns the twist that reaches the rigid transform a in unit
+// time. The linear part of a must be a rotation. The rotation angle of
+// the result is in [0, π].
+func LogSE3(a Affine) Twist {
+	omega := LogSO3(a.Linear())
+	theta2 := omega.LengthSquared()
+	var d float64
+	if theta2 < 1e-12 {
+		d = 1.0/12 + theta2/720
+	} else {
+		theta := math.Sqrt(theta2)
+		sin, cos := math.Sincos(theta)
+		d = (1 - theta*sin/(2*(1-cos))) / theta2
+	}
+	k := omega.Skew()
+	vinv := Identity3().Sub(k.MulScalar(0.5)).Add(k.Mul(k).MulScalar(d))
+	return Twist{Omega: omega, V: vinv.MulVec3(a.Translation())}
+}
+
+// InterpolateSE3 returns the rigid transform at parameter t on the screw
+// motion from a to b, which rotates and translates at constant rates.
+// The result is a when t is 0 and b when t is 1.
+//
+//	a × exp(t log(a⁻¹ × b))
+func InterpolateSE3(a, b Affine, t float64) Affine {
+	ainv, ok := a.Inverse()
+	if !ok {
+		return a
+	}
+	return a.Mul(ExpSE3(LogSE3(ainv.Mul(b)).Mul(t)))
+}
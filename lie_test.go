@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestSO3(t *testing.T) {
+	for _, omega := range []math3d.Vec3{
+		math3d.NewVec3(0, 0, 0),
+		math3d.NewVec3(1e-9, 0, 0),
+		math3d.NewVec3(0.3, -0.2, 0.1),
+		math3d.NewVec3(0, 0, math.Pi/2),
+		math3d.NewVec3(1, 2, 2).SetLength(math.Pi - 1e-9),
+	} {
+		r := math3d.ExpSO3(omega)
+		if expect := math3d.Mat3FromAxisAngle(omega, omega.Length()); !r.ApproxEqual(expect, 1e-12) {
+			t.Errorf("ExpSO3(%v): want %v, got %v\n", omega, expect, r)
+		}
+		if w := math3d.LogSO3(r); !w.ApproxEqual(omega, 1e-9) {
+			t.Errorf("LogSO3(ExpSO3(%v)): got %v\n", omega, w)
+		}
+		q := math3d.QuaternionFromRotationVector(omega)
+		if w := q.RotationVector(); !w.ApproxEqual(omega, 1e-12) {
+			t.Errorf("RotationVector(%v): got %v\n", omega, w)
+		}
+	}
+
+	if s := math3d.NewVec3(1, 2, 3).Skew().MulVec3(math3d.NewVec3(4, 5, 6)); s != math3d.NewVec3(1, 2, 3).Cross(math3d.NewVec3(4, 5, 6)) {
+		t.Errorf("Skew: want %v, got %v\n", math3d.NewVec3(1, 2, 3).Cross(math3d.NewVec3(4, 5, 6)), s)
+	}
+}
+
+func TestSE3(t *testing.T) {
+	for _, tw := range []math3d.Twist{
+		{},
+		{V: math3d.NewVec3(1, 2, 3)},
+		{Omega: math3d.NewVec3(1e-9, 0, 0), V: math3d.NewVec3(1, 0, 0)},
+		{Omega: math3d.NewVec3(0.3, -0.2, 0.1), V: math3d.NewVec3(1, -2, 0.5)},
+		{Omega: math3d.NewVec3(0, 0, 3), V: math3d.NewVec3(0, 1, 1)},
+	} {
+		a := math3d.ExpSE3(tw)
+		got := math3d.LogSE3(a)
+		if !got.Omega.ApproxEqual(tw.Omega, 1e-9) || !got.V.ApproxEqual(tw.V, 1e-9) {
+			t.Errorf("LogSE3(ExpSE3(%v)): got %v\n", tw, got)
+		}
+	}
+
+	// a pure rotation about the z-axis through (1, 0, 0)
+	tw := math3d.Twist{Omega: math3d.NewVec3(0, 0, math.Pi), V: math3d.NewVec3(0, -math.Pi, 0)}
+	a := math3d.ExpSE3(tw)
+	if p := a.TransformPoint(math3d.Point{X: 1}); !p.ApproxEqual(math3d.Point{X: 1}, 1e-12) {
+		t.Errorf("ExpSE3: axis point: want %v, got %v\n", math3d.Point{X: 1}, p)
+	}
+	if p := a.TransformPoint(math3d.Point{}); !p.ApproxEqual(math3d.Point{X: 2}, 1e-12) {
+		t.Errorf("ExpSE3: want %v, got %v\n", math3d.Point{X: 2}, p)
+	}
+
+	// halfway along the screw, the origin has swung a quarter turn about (1, 0, 0)
+	mid := math3d.InterpolateSE3(math3d.IdentityAffine(), a, 0.5)
+	if p := mid.TransformPoint(math3d.Point{}); !p.ApproxEqual(math3d.Point{X: 1, Y: -1}, 1e-9) {
+		t.Errorf("InterpolateSE3: want %v, got %v\n", math3d.Point{X: 1, Y: -1}, p)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math/bits"
+
+// Line is the infinite line through Origin along Direction. The
+// direction need not be normalized; the parameter t of a point on the
+// line is measured in multiples of Direction.
+type Line struct {
+	Origin    Point
+	Direction Vec3
+}
+
+// LineThrough returns the line through a and b, with the parameter 0
+// at a and 1 at b.
+func LineThrough(a, b Point) Line {
+	return Line{Origin: a, Direction: b.Sub(a)}
+}
+
+// ClosestPointTo returns the point on the line nearest to p.
+// A line with a zero direction is treated as the single point Origin.
+func (l Line) ClosestPointTo(p Point) Point {
+	return l.PointAt(l.closestT(p))
+}
+
+// DistanceTo returns the distance from p to the nearest point on the line.
+func (l Line) DistanceTo(p Point) float64 {
+	return p.Distance(l.ClosestPointTo(p))
+}
+
+// PointAt returns the point at parameter t along the line.
+func (l Line) PointAt(t float64) Point {
+	return l.Origin.Add(l.Direction.Mul(t))
+}
+
+// closestT returns the parameter of the point on the line nearest to p.
+func (l Line) closestT(p Point) float64 {
+	d := l.Direction.LengthSquared()
+	if d == 0 {
+		return 0
+	}
+	return p.Sub(l.Origin).Dot(l.Direction) / d
+}
+
+// Segment is the part of the line between the end points A and B.
+type Segment struct {
+	A, B Point
+}
+
+// ClosestPointOnSegment returns the point on the segment nearest to p.
+func ClosestPointOnSegment(p Point, s Segment) Point {
+	return s.ClosestPointTo(p)
+}
+
+// ClosestPointsSegmentSegment returns the points c1 on s1 and c2 on s2
+// that are nearest each other, along with their parameters s and t, so
+// that c1 is s1.PointAt(s) and c2 is s2.PointAt(t). It follows Ericson,
+// Real-Time Collision Detection, §5.1.9. Parallel segments have many
+// such pairs; one of them is returned. Segments of zero length are
+// treated as points.
+func ClosestPointsSegmentSegment(s1, s2 Segment) (c1, c2 Point, s, t float64) {
+	const epsilon = 1e-12
+	d1, d2, r := s1.B.Sub(s1.A), s2.B.Sub(s2.A), s1.A.Sub(s2.A)
+	a, e, f := d1.Dot(d1), d2.Dot(d2), d2.Dot(r)
+	switch {
+	case a <= epsilon && e <= epsilon:
+		// both segments are points
+	case a <= epsilon:
+		t = Clamp(f/e, 0, 1)
+	case e <= epsilon:
+		s = Clamp(-d1.Dot(r)/a, 0, 1)
+	default:
+		b, c := d1.Dot(d2), d1.Dot(r)
+		if denom := a*e - b*b; denom != 0 {
+			s = Clamp((b*f-c*e)/denom, 0, 1)
+		}
+		t = (b*s + f) / e
+		if t < 0 {
+			t, s = 0, Clamp(-c/a, 0, 1)
+		} else if t > 1 {
+			t, s = 1, Clamp((b-c)/a, 0, 1)
+		}
+	}
+	return s1.A.Add(d1.Mul(s)), s2.A.Add(d2.Mul(t)), s, t
+}
+
+// ClosestPointTo returns the point on the segment nearest to p.
+func (s Segment) ClosestPointTo(p Point) Point {
+	return s.PointAt(Clamp(s.Line().closestT(p), 0, 1))
+}
+
+// DistanceTo returns the distance from p to the nearest point on the segment.
+func (s Segment) DistanceTo(p Point) float64 {
+	return p.Distance(s.ClosestPointTo(p))
+}
+
+// Length returns the distance between the end points.
+func (s Segment) Length() float64 {
+	return s.A.Distance(s.B)
+}
+
+// Line returns the line through the segment, with the parameter 0 at A
+// and 1 at B.
+func (s Segment) Line() Line {
+	return LineThrough(s.A, s.B)
+}
+
+// PointAt returns the point at parameter t, which is A when t is 0 and B
+// when t is 1. Values of t outside [0, 1] extend past the end points.
+func (s Segment) PointAt(t float64) Point {
+	return s.A.Lerp(s.B, t)
+}
+
+// Supercover returns the cells of a grid with cells of the given size
+// that the segment passes through, in order from A to B. Unlike
+// Ray.TraverseGrid, where the segment passes exactly through an edge or
+// corner of the grid it also includes the cells that it only touches
+// there, so that nothing the segment touches along its length is missed.
+// Cells that meet the segment only at an end point on their boundary are
+// left out. A segment with no length returns the cell holding A. The
+// cell size must be positive.
+func (s Segment) Supercover(cellSize float64) []Cell {
+	var cells []Cell
+	if s.A == s.B {
+		return append(cells, CellOf(s.A, cellSize))
+	}
+	Ray{Origin: s.A, Direction: s.B.Sub(s.A)}.TraverseGrid(cellSize, func(c Cell, enter, leave float64) bool {
+		if len(cells) != 0 {
+			// fill in the cells around an edge or corner stepped across
+			// diagonally, changing fewer axes first
+			prev := cells[len(cells)-1]
+			from, to := [3]int{prev.X, prev.Y, prev.Z}, [3]int{c.X, c.Y, c.Z}
+			var changed uint
+			for i := range from {
+				if from[i] != to[i] {
+					changed |= 1 << i
+				}
+			}
+			for n := 1; n < bits.OnesCount(changed); n++ {
+				for mask := uint(1); mask < 8; mask++ {
+					if mask&^changed != 0 || bits.OnesCount(mask) != n {
+						continue
+					}
+					b := from
+					for i := range b {
+						if mask&(1<<i) != 0 {
+							b[i] = to[i]
+						}
+					}
+					cells = append(cells, Cell{X: b[0], Y: b[1], Z: b[2]})
+				}
+			}
+		}
+		cells = append(cells, c)
+		return leave < 1
+	})
+	return cells
+}
+
+// Support returns the end of the segment furthest in the direction d.
+// It implements the ConvexShape interface.
+func (s Segment) Support(d Vec3) Point {
+	if s.B.Sub(s.A).Dot(d) > 0 {
+		return s.B
+	}
+	return s.A
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestLine(t *testing.T) {
+	a, b := math3d.Point{X: 1, Y: 1, Z: 0}, math3d.Point{X: 3, Y: 1, Z: 0}
+	l := math3d.LineThrough(a, b)
+	if got := l.PointAt(1); got != b {
+		t.Errorf("PointAt: want %v, got %v\n", b, got)
+	}
+	for _, tt := range []struct {
+		p, want math3d.Point
+		dist    float64
+	}{
+		{math3d.Point{X: 2, Y: 4, Z: 0}, math3d.Point{X: 2, Y: 1, Z: 0}, 3},
+		{math3d.Point{X: -5, Y: 1, Z: 4}, math3d.Point{X: -5, Y: 1, Z: 0}, 4},
+		{math3d.Point{X: 9, Y: 1, Z: 0}, math3d.Point{X: 9, Y: 1, Z: 0}, 0},
+	} {
+		if got := l.ClosestPointTo(tt.p); !got.ApproxEqual(tt.want, 1e-12) {
+			t.Errorf("ClosestPointTo(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+		if got := l.DistanceTo(tt.p); math.Abs(got-tt.dist) > 1e-12 {
+			t.Errorf("DistanceTo(%v): want %v, got %v\n", tt.p, tt.dist, got)
+		}
+	}
+	if got := (math3d.Line{Origin: a}).ClosestPointTo(b); got != a {
+		t.Errorf("ClosestPointTo: zero direction: want %v, got %v\n", a, got)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	s := math3d.Segment{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 0, Y: 3, Z: 4}}
+	if got := s.Length(); got != 5 {
+		t.Errorf("Length: want 5, got %v\n", got)
+	}
+	if got, want := s.PointAt(0.5), (math3d.Point{X: 0, Y: 1.5, Z: 2}); got != want {
+		t.Errorf("PointAt: want %v, got %v\n", want, got)
+	}
+	for _, tt := range []struct {
+		p, want math3d.Point
+		dist    float64
+	}{
+		{math3d.Point{X: 2, Y: 1.5, Z: 2}, math3d.Point{X: 0, Y: 1.5, Z: 2}, 2},
+		{math3d.Point{X: 0, Y: -3, Z: -4}, s.A, 5},
+		{math3d.Point{X: 0, Y: 6, Z: 8}, s.B, 5},
+	} {
+		if got := s.ClosestPointTo(tt.p); !got.ApproxEqual(tt.want, 1e-12) {
+			t.Errorf("ClosestPointTo(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+		if got := s.DistanceTo(tt.p); math.Abs(got-tt.dist) > 1e-12 {
+			t.Errorf("DistanceTo(%v): want %v, got %v\n", tt.p, tt.dist, got)
+		}
+	}
+	// the line through the segment is not clamped
+	if got, want := s.Line().ClosestPointTo(math3d.Point{X: 0, Y: 6, Z: 8}), (math3d.Point{X: 0, Y: 6, Z: 8}); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("Line: ClosestPointTo: want %v, got %v\n", want, got)
+	}
+}
+
+func TestSegmentSupercover(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		s    math3d.Segment
+		size float64
+		want []math3d.Cell
+	}{
+		{"point", math3d.Segment{A: math3d.Point{X: -0.5, Y: 2.5}, B: math3d.Point{X: -0.5, Y: 2.5}}, 1,
+			[]math3d.Cell{{X: -1, Y: 2}}},
+		{"along x", math3d.Segment{A: math3d.Point{X: 0.5, Y: 0.5, Z: 0.5}, B: math3d.Point{X: 3, Y: 0.5, Z: 0.5}}, 1,
+			[]math3d.Cell{{}, {X: 1}, {X: 2}}},
+		{"through an edge", math3d.Segment{A: math3d.Point{X: 0.5, Y: 0.5, Z: 0.5}, B: math3d.Point{X: 1.5, Y: 1.5, Z: 0.5}}, 1,
+			[]math3d.Cell{{}, {X: 1}, {Y: 1}, {X: 1, Y: 1}}},
+		{"through a corner", math3d.Segment{A: math3d.Point{X: 1, Y: 1, Z: 1}, B: math3d.Point{X: 3, Y: 3, Z: 3}}, 2,
+			[]math3d.Cell{{}, {X: 1}, {Y: 1}, {Z: 1}, {X: 1, Y: 1}, {X: 1, Z: 1}, {Y: 1, Z: 1}, {X: 1, Y: 1, Z: 1}}},
+		{"backward", math3d.Segment{A: math3d.Point{X: 0.25, Y: 0.5, Z: 0.5}, B: math3d.Point{X: -1.25, Y: 0.75, Z: 0.5}}, 0.5,
+			[]math3d.Cell{{Y: 1, Z: 1}, {X: -1, Y: 1, Z: 1}, {X: -2, Y: 1, Z: 1}, {X: -3, Y: 1, Z: 1}}},
+	} {
+		got := tt.s.Supercover(tt.size)
+		if len(got) != len(tt.want) {
+			t.Errorf("Supercover: %s: want %v, got %v\n", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Supercover: %s: want %v, got %v\n", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestClosestPointsSegmentSegment(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		s1, s2 math3d.Segment
+		dist   float64
+		s, t   float64
+	}{
+		{"crossing",
+			math3d.Segment{A: math3d.Point{X: -1, Y: 0, Z: 0}, B: math3d.Point{X: 1, Y: 0, Z: 0}},
+			math3d.Segment{A: math3d.Point{X: 0, Y: -1, Z: 2}, B: math3d.Point{X: 0, Y: 3, Z: 2}},
+			2, 0.5, 0.25},
+		{"end to interior",
+			math3d.Segment{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 1, Y: 0, Z: 0}},
+			math3d.Segment{A: math3d.Point{X: 3, Y: -1, Z: 0}, B: math3d.Point{X: 3, Y: 1, Z: 0}},
+			2, 1, 0.5},
+		{"end to end",
+			math3d.Segment{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 1, Y: 0, Z: 0}},
+			math3d.Segment{A: math3d.Point{X: 4, Y: 4, Z: 0}, B: math3d.Point{X: 4, Y: 9, Z: 0}},
+			5, 1, 0},
+		{"parallel",
+			math3d.Segment{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 4, Y: 0, Z: 0}},
+			math3d.Segment{A: math3d.Point{X: 2, Y: 1, Z: 0}, B: math3d.Point{X: 6, Y: 1, Z: 0}},
+			1, -1, -1},
+		{"point and segment",
+			math3d.Segment{A: math3d.Point{X: 1, Y: 2, Z: 0}, B: math3d.Point{X: 1, Y: 2, Z: 0}},
+			math3d.Segment{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 4, Y: 0, Z: 0}},
+			2, 0, 0.25},
+		{"two points",
+			math3d.Segment{A: math3d.Point{X: 1, Y: 2, Z: 2}, B: math3d.Point{X: 1, Y: 2, Z: 2}},
+			math3d.Segment{A: math3d.Point{X: 1, Y: 0, Z: 2}, B: math3d.Point{X: 1, Y: 0, Z: 2}},
+			2, 0, 0},
+	} {
+		c1, c2, s, u := math3d.ClosestPointsSegmentSegment(tt.s1, tt.s2)
+		if d := c1.Distance(c2); math.Abs(d-tt.dist) > 1e-12 {
+			t.Errorf("ClosestPointsSegmentSegment: %s: distance: want %v, got %v\n", tt.name, tt.dist, d)
+		}
+		if c1 != tt.s1.PointAt(s) || c2 != tt.s2.PointAt(u) {
+			t.Errorf("ClosestPointsSegmentSegment: %s: want points at the parameters %v %v, got %v %v\n", tt.name, s, u, c1, c2)
+		}
+		if s < 0 || s > 1 || u < 0 || u > 1 {
+			t.Errorf("ClosestPointsSegmentSegment: %s: want parameters in [0, 1], got %v %v\n", tt.name, s, u)
+		}
+		// parallel segments have many answers; only check the others
+		if tt.s >= 0 && (math.Abs(s-tt.s) > 1e-12 || math.Abs(u-tt.t) > 1e-12) {
+			t.Errorf("ClosestPointsSegmentSegment: %s: parameters: want %v %v, got %v %v\n", tt.name, tt.s, tt.t, s, u)
+		}
+	}
+}
+
+func TestClosestPointOnSegment(t *testing.T) {
+	s := math3d.Segment{A: math3d.Point{X: 1, Y: 1, Z: 1}, B: math3d.Point{X: 1, Y: 1, Z: 5}}
+	for _, tt := range []struct {
+		p, want math3d.Point
+	}{
+		{math3d.Point{X: 4, Y: 1, Z: 3}, math3d.Point{X: 1, Y: 1, Z: 3}},
+		{math3d.Point{X: 1, Y: 1, Z: -4}, s.A},
+		{math3d.Point{X: 0, Y: 0, Z: 9}, s.B},
+	} {
+		if got := math3d.ClosestPointOnSegment(tt.p, s); !got.ApproxEqual(tt.want, 1e-12) {
+			t.Errorf("ClosestPointOnSegment(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Mat3 implements a 3×3 matrix stored in row-major order,
+// so m[i][j] is the element in row i and column j.
+// Vectors are treated as columns and are multiplied on the right.
+type Mat3 [3][3]float64
+
+// Identity3 returns the 3×3 identity matrix.
+func Identity3() Mat3 {
+	return Mat3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// Mat3FromCols returns the matrix with the given column vectors.
+func Mat3FromCols(c0, c1, c2 Vec3) Mat3 {
+	return Mat3{
+		{c0.X, c1.X, c2.X},
+		{c0.Y, c1.Y, c2.Y},
+		{c0.Z, c1.Z, c2.Z},
+	}
+}
+
+// Mat3FromRows returns the matrix with the given row vectors.
+func Mat3FromRows(r0, r1, r2 Vec3) Mat3 {
+	return Mat3{
+		{r0.X, r0.Y, r0.Z},
+		{r1.X, r1.Y, r1.Z},
+		{r2.X, r2.Y, r2.Z},
+	}
+}
+
+// Mat3FromAxisAngle returns the matrix that rotates counter-clockwise
+// by angle radians about the given axis, which need not be normalized.
+// Returns the identity matrix if the axis is zero.
+//
+//	R = cosθ I + sinθ [k]× + (1 − cosθ) k⊗k
+func Mat3FromAxisAngle(axis Vec3, angle float64) Mat3 {
+	k, ok := axis.Normalized()
+	if !ok {
+		return Identity3()
+	}
+	sin, cos := math.Sincos(angle)
+	t := 1 - cos
+	return Mat3{
+		{cos + t*k.X*k.X, t*k.X*k.Y - sin*k.Z, t*k.X*k.Z + sin*k.Y},
+		{t*k.X*k.Y + sin*k.Z, cos + t*k.Y*k.Y, t*k.Y*k.Z - sin*k.X},
+		{t*k.X*k.Z - sin*k.Y, t*k.Y*k.Z + sin*k.X, cos + t*k.Z*k.Z},
+	}
+}
+
+// Mat3FromLookRotation returns the rotation that orients an object to
+// face along forward, with its up axis as close to up as possible. As in
+// LookAt, the object faces along its negative z-axis with its y-axis up,
+// so the result is the inverse of the rotation part of a view matrix.
+// If up is zero or parallel to forward, an arbitrary perpendicular is
+// used instead. Returns the identity if forward is zero.
+func Mat3FromLookRotation(forward, up Vec3) Mat3 {
+	f, ok := forward.Normalized()
+	if !ok {
+		return Identity3()
+	}
+	s, ok := f.Cross(up).Normalized()
+	if !ok {
+		s = f.Cross(f.anyPerpendicular()).Normalize()
+	}
+	u := s.Cross(f)
+	return Mat3FromCols(s, u, f.Mul(-1))
+}
+
+// Mat3FromRotation2D returns the homogeneous 2D transform that rotates
+// counter-clockwise by angle radians.
+func Mat3FromRotation2D(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{cos, -sin, 0}, {sin, cos, 0}, {0, 0, 1}}
+}
+
+// Mat3FromRotationX returns the matrix that rotates counter-clockwise
+// by angle radians about the x-axis.
+func Mat3FromRotationX(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{1, 0, 0}, {0, cos, -sin}, {0, sin, cos}}
+}
+
+// Mat3FromRotationY returns the matrix that rotates counter-clockwise
+// by angle radians about the y-axis.
+func Mat3FromRotationY(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{cos, 0, sin}, {0, 1, 0}, {-sin, 0, cos}}
+}
+
+// Mat3FromRotationZ returns the matrix that rotates counter-clockwise
+// by angle radians about the z-axis.
+func Mat3FromRotationZ(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{cos, -sin, 0}, {sin, cos, 0}, {0, 0, 1}}
+}
+
+// Mat3FromScale returns the matrix that scales by the components of s.
+func Mat3FromScale(s Vec3) Mat3 {
+	return Mat3{{s.X, 0, 0}, {0, s.Y, 0}, {0, 0, s.Z}}
+}
+
+// Mat3FromScale2D returns the homogeneous 2D transform that scales
+// by sx and sy.
+func Mat3FromScale2D(sx, sy float64) Mat3 {
+	return Mat3{{sx, 0, 0}, {0, sy, 0}, {0, 0, 1}}
+}
+
+// Mat3FromTranslation2D returns the homogeneous 2D transform that
+// translates by t.
+func Mat3FromTranslation2D(t Vec2) Mat3 {
+	return Mat3{{1, 0, t.X}, {0, 1, t.Y}, {0, 0, 1}}
+}
+
+// RandomRotationMat3 returns a rotation matrix drawn uniformly from all
+// rotations. See RandomQuaternion.
+func RandomRotationMat3(rng *rand.Rand) Mat3 {
+	return RandomQuaternion(rng).ToMat3()
+}
+
+func (m Mat3) Add(n Mat3) Mat3 {
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] += n[i][j]
+		}
+	}
+	return m
+}
+
+// ApproxEqual reports whether each element of m is within epsilon of
+// the corresponding element of n.
+func (m Mat3) ApproxEqual(n Mat3, epsilon float64) bool {
+	for i := range m {
+		for j := range m[i] {
+			if !ApproxEqual(m[i][j], n[i][j], epsilon) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Col returns column j of the matrix.
+func (m Mat3) Col(j int) Vec3 {
+	return Vec3{X: m[0][j], Y: m[1][j], Z: m[2][j]}
+}
+
+// Determinant returns the determinant of the matrix.
+func (m Mat3) Determinant() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// HasNaN reports whether any element of the matrix is NaN.
+func (m Mat3) HasNaN() bool {
+	for i := range m {
+		for j := range m[i] {
+			if math.IsNaN(m[i][j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Inverse returns the inverse of the matrix.
+// It returns false if the matrix is singular.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	// the inverse is the adjugate (transposed cofactors) divided by the determinant
+	r := 1 / det
+	return Mat3{
+		{
+			(m[1][1]*m[2][2] - m[1][2]*m[2][1]) * r,
+			(m[0][2]*m[2][1] - m[0][1]*m[2][2]) * r,
+			(m[0][1]*m[1][2] - m[0][2]*m[1][1]) * r,
+		},
+		{
+			(m[1][2]*m[2][0] - m[1][0]*m[2][2]) * r,
+			(m[0][0]*m[2][2] - m[0][2]*m[2][0]) * r,
+			(m[0][2]*m[1][0] - m[0][0]*m[1][2]) * r,
+		},
+		{
+			(m[1][0]*m[2][1] - m[1][1]*m[2][0]) * r,
+			(m[0][1]*m[2][0] - m[0][0]*m[2][1]) * r,
+			(m[0][0]*m[1][1] - m[0][1]*m[1][0]) * r,
+		},
+	}, true
+}
+
+// IsFinite reports whether every element of the matrix is neither NaN nor infinite.
+func (m Mat3) IsFinite() bool {
+	for i := range m {
+		for j := range m[i] {
+			if math.IsNaN(m[i][j]) || math.IsInf(m[i][j], 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Mul returns the matrix product m×n. Applying the result to a vector
+// applies n first and then m.
+func (m Mat3) Mul(n Mat3) Mat3 {
+	var p Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			p[i][j] = m[i][0]*n[0][j] + m[i][1]*n[1][j] + m[i][2]*n[2][j]
+		}
+	}
+	return p
+}
+
+// MulScalar returns the matrix with every element multiplied by scalar.
+func (m Mat3) MulScalar(scalar float64) Mat3 {
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] *= scalar
+		}
+	}
+	return m
+}
+
+// MulVec3 returns the matrix-vector product m×v.
+func (m Mat3) MulVec3(v Vec3) Vec3 {
+	return Vec3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// Row returns row i of the matrix.
+func (m Mat3) Row(i int) Vec3 {
+	return Vec3{X: m[i][0], Y: m[i][1], Z: m[i][2]}
+}
+
+func (m Mat3) Sub(n Mat3) Mat3 {
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] -= n[i][j]
+		}
+	}
+	return m
+}
+
+// ToQuaternion returns the unit quaternion for the rotation matrix m.
+// It uses Shepperd's method, choosing the largest of the four candidate
+// divisors so the extraction stays accurate for every rotation.
+// The result is only meaningful if m is a rotation.
+func (m Mat3) ToQuaternion() Quaternion {
+	trace := m[0][0] + m[1][1] + m[2][2]
+	if trace > 0 {
+		s := 2 * math.Sqrt(trace+1)
+		return Quaternion{
+			W: s / 4,
+			X: (m[2][1] - m[1][2]) / s,
+			Y: (m[0][2] - m[2][0]) / s,
+			Z: (m[1][0] - m[0][1]) / s,
+		}
+	} else if m[0][0] > m[1][1] && m[0][0] > m[2][2] {
+		s := 2 * math.Sqrt(1+m[0][0]-m[1][1]-m[2][2])
+		return Quaternion{
+			W: (m[2][1] - m[1][2]) / s,
+			X: s / 4,
+			Y: (m[0][1] + m[1][0]) / s,
+			Z: (m[0][2] + m[2][0]) / s,
+		}
+	} else if m[1][1] > m[2][2] {
+		s := 2 * math.Sqrt(1+m[1][1]-m[0][0]-m[2][2])
+		return Quaternion{
+			W: (m[0][2] - m[2][0]) / s,
+			X: (m[0][1] + m[1][0]) / s,
+			Y: s / 4,
+			Z: (m[1][2] + m[2][1]) / s,
+		}
+	}
+	s := 2 * math.Sqrt(1+m[2][2]-m[0][0]-m[1][1])
+	return Quaternion{
+		W: (m[1][0] - m[0][1]) / s,
+		X: (m[0][2] + m[2][0]) / s,
+		Y: (m[1][2] + m[2][1]) / s,
+		Z: s / 4,
+	}
+}
+
+// TransformDirection2D applies the homogeneous 2D transform to a
+// direction. Translation is ignored.
+func (m Mat3) TransformDirection2D(v Vec2) Vec2 {
+	return Vec2{
+		X: m[0][0]*v.X + m[0][1]*v.Y,
+		Y: m[1][0]*v.X + m[1][1]*v.Y,
+	}
+}
+
+// TransformPoint2D applies the homogeneous 2D transform to a point,
+// including translation and the perspective divide.
+func (m Mat3) TransformPoint2D(v Vec2) Vec2 {
+	p := m.MulVec3(Vec3{X: v.X, Y: v.Y, Z: 1})
+	if p.Z != 1 && p.Z != 0 {
+		return Vec2{X: p.X / p.Z, Y: p.Y / p.Z}
+	}
+	return Vec2{X: p.X, Y: p.Y}
+}
+
+// Transpose returns the transpose of the matrix.
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{
+		{m[0][0], m[1][0], m[2][0]},
+		{m[0][1], m[1][1], m[2][1]},
+		{m[0][2], m[1][2], m[2][2]},
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestMat3(t *testing.T) {
+	m := math3d.Mat3{{2, 0, 1}, {1, 3, 2}, {1, 1, 2}}
+	if d := m.Determinant(); d != 6 {
+		t.Errorf("Determinant: want %f, got %f\n", 6.0, d)
+	}
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatalf("Inverse: want ok, got singular\n")
+	}
+	if p := m.Mul(inv); !p.ApproxEqual(math3d.Identity3(), 1e-12) {
+		t.Errorf("Inverse: m×m⁻¹: want identity, got %v\n", p)
+	}
+	if _, ok := (math3d.Mat3{{1, 2, 3}, {2, 4, 6}, {0, 0, 1}}).Inverse(); ok {
+		t.Errorf("Inverse: singular: want false, got true\n")
+	}
+	if tr := m.Transpose(); tr.Row(0) != m.Col(0) {
+		t.Errorf("Transpose: want %v, got %v\n", m.Col(0), tr.Row(0))
+	}
+
+	// rotations are counter-clockwise
+	for _, tt := range []struct {
+		name      string
+		m         math3d.Mat3
+		v, expect math3d.Vec3
+	}{
+		{"RotationX", math3d.Mat3FromRotationX(math.Pi / 2), math3d.NewVec3(0, 1, 0), math3d.NewVec3(0, 0, 1)},
+		{"RotationY", math3d.Mat3FromRotationY(math.Pi / 2), math3d.NewVec3(0, 0, 1), math3d.NewVec3(1, 0, 0)},
+		{"RotationZ", math3d.Mat3FromRotationZ(math.Pi / 2), math3d.NewVec3(1, 0, 0), math3d.NewVec3(0, 1, 0)},
+		{"AxisAngle", math3d.Mat3FromAxisAngle(math3d.NewVec3(0, 0, 2), math.Pi/2), math3d.NewVec3(1, 0, 0), math3d.NewVec3(0, 1, 0)},
+		{"Scale", math3d.Mat3FromScale(math3d.NewVec3(1, 2, 3)), math3d.NewVec3(1, 1, 1), math3d.NewVec3(1, 2, 3)},
+	} {
+		if v := tt.m.MulVec3(tt.v); !v.ApproxEqual(tt.expect, 1e-12) {
+			t.Errorf("%s: want %v, got %v\n", tt.name, tt.expect, v)
+		}
+	}
+	axis := math3d.NewVec3(1, 2, 3)
+	if r := math3d.Mat3FromAxisAngle(axis, 0.7); !r.MulVec3(axis).ApproxEqual(axis, 1e-12) {
+		t.Errorf("AxisAngle: axis should be fixed, got %v\n", r.MulVec3(axis))
+	}
+
+	xf := math3d.Mat3FromTranslation2D(math3d.NewVec2(5, 0)).Mul(math3d.Mat3FromRotation2D(math.Pi / 2))
+	if p := xf.TransformPoint2D(math3d.NewVec2(1, 0)); !p.ApproxEqual(math3d.NewVec2(5, 1), 1e-12) {
+		t.Errorf("TransformPoint2D: want %v, got %v\n", math3d.NewVec2(5, 1), p)
+	}
+	if d := xf.TransformDirection2D(math3d.NewVec2(1, 0)); !d.ApproxEqual(math3d.NewVec2(0, 1), 1e-12) {
+		t.Errorf("TransformDirection2D: want %v, got %v\n", math3d.NewVec2(0, 1), d)
+	}
+}
@@ -0,0 +1,378 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Mat4 implements a 4×4 matrix stored in row-major order,
+// so m[i][j] is the element in row i and column j.
+// Vectors are treated as columns and are multiplied on the right,
+// so the translation of an affine transform is in the last column.
+//
+// Vec4 stores its components as W, X, Y, Z; the matrix always
+// treats them in the mathematical order x, y, z, w.
+type Mat4 [4][4]float64
+
+// Identity4 returns the 4×4 identity matrix.
+func Identity4() Mat4 {
+	return Mat4{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+}
+
+// Mat4FromAxisAngle returns the transform that rotates counter-clockwise
+// by angle radians about the given axis through the origin.
+func Mat4FromAxisAngle(axis Vec3, angle float64) Mat4 {
+	return Mat4FromMat3(Mat3FromAxisAngle(axis, angle))
+}
+
+// Mat4FromMat3 returns the transform with m as its upper-left 3×3
+// linear part and no translation.
+func Mat4FromMat3(m Mat3) Mat4 {
+	return Mat4{
+		{m[0][0], m[0][1], m[0][2], 0},
+		{m[1][0], m[1][1], m[1][2], 0},
+		{m[2][0], m[2][1], m[2][2], 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Mat4FromRotationAround returns the transform that rotates
+// counter-clockwise by angle radians about the given axis through point.
+// It is the translation of point to the origin, the rotation, and the
+// translation back, composed in one step.
+func Mat4FromRotationAround(point Point, axis Vec3, angle float64) Mat4 {
+	r := Mat3FromAxisAngle(axis, angle)
+	p := point.Vec3()
+	m := Mat4FromMat3(r)
+	t := p.Sub(r.MulVec3(p))
+	m[0][3], m[1][3], m[2][3] = t.X, t.Y, t.Z
+	return m
+}
+
+// Mat4FromRotationX returns the transform that rotates counter-clockwise
+// by angle radians about the x-axis.
+func Mat4FromRotationX(angle float64) Mat4 {
+	return Mat4FromMat3(Mat3FromRotationX(angle))
+}
+
+// Mat4FromRotationY returns the transform that rotates counter-clockwise
+// by angle radians about the y-axis.
+func Mat4FromRotationY(angle float64) Mat4 {
+	return Mat4FromMat3(Mat3FromRotationY(angle))
+}
+
+// Mat4FromRotationZ returns the transform that rotates counter-clockwise
+// by angle radians about the z-axis.
+func Mat4FromRotationZ(angle float64) Mat4 {
+	return Mat4FromMat3(Mat3FromRotationZ(angle))
+}
+
+// Mat4FromScale returns the transform that scales by the components of s.
+func Mat4FromScale(s Vec3) Mat4 {
+	return Mat4{{s.X, 0, 0, 0}, {0, s.Y, 0, 0}, {0, 0, s.Z, 0}, {0, 0, 0, 1}}
+}
+
+// Mat4FromUniformScale returns the transform that scales by s along every axis.
+func Mat4FromUniformScale(s float64) Mat4 {
+	return Mat4FromScale(Vec3{X: s, Y: s, Z: s})
+}
+
+// Mat4FromShearXY returns the transform that shears parallel to the
+// xy-plane, offsetting x and y in proportion to z.
+//
+//	x' = x + xz*z
+//	y' = y + yz*z
+func Mat4FromShearXY(xz, yz float64) Mat4 {
+	return Mat4{{1, 0, xz, 0}, {0, 1, yz, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+}
+
+// Mat4FromShearXZ returns the transform that shears parallel to the
+// xz-plane, offsetting x and z in proportion to y.
+//
+//	x' = x + xy*y
+//	z' = z + zy*y
+func Mat4FromShearXZ(xy, zy float64) Mat4 {
+	return Mat4{{1, xy, 0, 0}, {0, 1, 0, 0}, {0, zy, 1, 0}, {0, 0, 0, 1}}
+}
+
+// Mat4FromShearYZ returns the transform that shears parallel to the
+// yz-plane, offsetting y and z in proportion to x.
+//
+//	y' = y + yx*x
+//	z' = z + zx*x
+func Mat4FromShearYZ(yx, zx float64) Mat4 {
+	return Mat4{{1, 0, 0, 0}, {yx, 1, 0, 0}, {zx, 0, 1, 0}, {0, 0, 0, 1}}
+}
+
+// Mat4FromTranslation returns the transform that translates by t.
+func Mat4FromTranslation(t Vec3) Mat4 {
+	return Mat4{{1, 0, 0, t.X}, {0, 1, 0, t.Y}, {0, 0, 1, t.Z}, {0, 0, 0, 1}}
+}
+
+// ApproxEqual reports whether each element of m is within epsilon of
+// the corresponding element of n.
+func (m Mat4) ApproxEqual(n Mat4, epsilon float64) bool {
+	for i := range m {
+		for j := range m[i] {
+			if !ApproxEqual(m[i][j], n[i][j], epsilon) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Col returns column j of the matrix.
+func (m Mat4) Col(j int) Vec4 {
+	return Vec4{X: m[0][j], Y: m[1][j], Z: m[2][j], W: m[3][j]}
+}
+
+// Decompose splits an affine transform into translation, rotation, and
+// scale, such that m = T×R×S. A transform that mirrors geometry is
+// reported as a negative x scale. Any shear is removed from the rotation
+// by Gram-Schmidt orthogonalization and is not reported. The bottom row
+// of m is assumed to be (0, 0, 0, 1). It returns false if the transform
+// collapses space onto a plane, line, or point.
+func (m Mat4) Decompose() (translation Vec3, rotation Quaternion, scale Vec3, ok bool) {
+	lin := m.Mat3()
+	c0, c1, c2 := lin.Col(0), lin.Col(1), lin.Col(2)
+	mirrored := lin.Determinant() < 0
+	if mirrored {
+		c0 = c0.Mul(-1)
+	}
+	r0, ok := c0.Normalized()
+	if !ok {
+		return Vec3{}, Quaternion{}, Vec3{}, false
+	}
+	r1, ok := c1.Sub(r0.Mul(r0.Dot(c1))).Normalized()
+	if !ok {
+		return Vec3{}, Quaternion{}, Vec3{}, false
+	}
+	r2 := r0.Cross(r1)
+	scale = Vec3{X: c0.Dot(r0), Y: c1.Dot(r1), Z: c2.Dot(r2)}
+	if scale.Z <= 0 {
+		return Vec3{}, Quaternion{}, Vec3{}, false
+	}
+	if mirrored {
+		scale.X = -scale.X
+	}
+	return m.Translation(), Mat3FromCols(r0, r1, r2).ToQuaternion(), scale, true
+}
+
+// Determinant returns the determinant of the matrix.
+func (m Mat4) Determinant() float64 {
+	b00 := m[0][0]*m[1][1] - m[0][1]*m[1][0]
+	b01 := m[0][0]*m[1][2] - m[0][2]*m[1][0]
+	b02 := m[0][0]*m[1][3] - m[0][3]*m[1][0]
+	b03 := m[0][1]*m[1][2] - m[0][2]*m[1][1]
+	b04 := m[0][1]*m[1][3] - m[0][3]*m[1][1]
+	b05 := m[0][2]*m[1][3] - m[0][3]*m[1][2]
+	b06 := m[2][0]*m[3][1] - m[2][1]*m[3][0]
+	b07 := m[2][0]*m[3][2] - m[2][2]*m[3][0]
+	b08 := m[2][0]*m[3][3] - m[2][3]*m[3][0]
+	b09 := m[2][1]*m[3][2] - m[2][2]*m[3][1]
+	b10 := m[2][1]*m[3][3] - m[2][3]*m[3][1]
+	b11 := m[2][2]*m[3][3] - m[2][3]*m[3][2]
+	return b00*b11 - b01*b10 + b02*b09 + b03*b08 - b04*b07 + b05*b06
+}
+
+// HasNaN reports whether any element of the matrix is NaN.
+func (m Mat4) HasNaN() bool {
+	for i := range m {
+		for j := range m[i] {
+			if math.IsNaN(m[i][j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Inverse returns the inverse of the matrix.
+// It returns false if the matrix is singular.
+func (m Mat4) Inverse() (Mat4, bool) {
+	// expand along the 2×2 minors of the top and bottom row pairs
+	b00 := m[0][0]*m[1][1] - m[0][1]*m[1][0]
+	b01 := m[0][0]*m[1][2] - m[0][2]*m[1][0]
+	b02 := m[0][0]*m[1][3] - m[0][3]*m[1][0]
+	b03 := m[0][1]*m[1][2] - m[0][2]*m[1][1]
+	b04 := m[0][1]*m[1][3] - m[0][3]*m[1][1]
+	b05 := m[0][2]*m[1][3] - m[0][3]*m[1][2]
+	b06 := m[2][0]*m[3][1] - m[2][1]*m[3][0]
+	b07 := m[2][0]*m[3][2] - m[2][2]*m[3][0]
+	b08 := m[2][0]*m[3][3] - m[2][3]*m[3][0]
+	b09 := m[2][1]*m[3][2] - m[2][2]*m[3][1]
+	b10 := m[2][1]*m[3][3] - m[2][3]*m[3][1]
+	b11 := m[2][2]*m[3][3] - m[2][3]*m[3][2]
+	det := b00*b11 - b01*b10 + b02*b09 + b03*b08 - b04*b07 + b05*b06
+	if det == 0 {
+		return Mat4{}, false
+	}
+	r := 1 / det
+	return Mat4{
+		{
+			(m[1][1]*b11 - m[1][2]*b10 + m[1][3]*b09) * r,
+			(m[0][2]*b10 - m[0][1]*b11 - m[0][3]*b09) * r,
+			(m[3][1]*b05 - m[3][2]*b04 + m[3][3]*b03) * r,
+			(m[2][2]*b04 - m[2][1]*b05 - m[2][3]*b03) * r,
+		},
+		{
+			(m[1][2]*b08 - m[1][0]*b11 - m[1][3]*b07) * r,
+			(m[0][0]*b11 - m[0][2]*b08 + m[0][3]*b07) * r,
+			(m[3][2]*b02 - m[3][0]*b05 - m[3][3]*b01) * r,
+			(m[2][0]*b05 - m[2][2]*b02 + m[2][3]*b01) * r,
+		},
+		{
+			(m[1][0]*b10 - m[1][1]*b08 + m[1][3]*b06) * r,
+			(m[0][1]*b08 - m[0][0]*b10 - m[0][3]*b06) * r,
+			(m[3][0]*b04 - m[3][1]*b02 + m[3][3]*b00) * r,
+			(m[2][1]*b02 - m[2][0]*b04 - m[2][3]*b00) * r,
+		},
+		{
+			(m[1][1]*b07 - m[1][0]*b09 - m[1][2]*b06) * r,
+			(m[0][0]*b09 - m[0][1]*b07 + m[0][2]*b06) * r,
+			(m[3][1]*b01 - m[3][0]*b03 - m[3][2]*b00) * r,
+			(m[2][0]*b03 - m[2][1]*b01 + m[2][2]*b00) * r,
+		},
+	}, true
+}
+
+// IsFinite reports whether every element of the matrix is neither NaN nor infinite.
+func (m Mat4) IsFinite() bool {
+	for i := range m {
+		for j := range m[i] {
+			if math.IsNaN(m[i][j]) || math.IsInf(m[i][j], 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Mat3 returns the upper-left 3×3 linear part of the matrix.
+func (m Mat4) Mat3() Mat3 {
+	return Mat3{
+		{m[0][0], m[0][1], m[0][2]},
+		{m[1][0], m[1][1], m[1][2]},
+		{m[2][0], m[2][1], m[2][2]},
+	}
+}
+
+// Mul returns the matrix product m×n. Applying the result to a vector
+// applies n first and then m.
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var p Mat4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			p[i][j] = m[i][0]*n[0][j] + m[i][1]*n[1][j] + m[i][2]*n[2][j] + m[i][3]*n[3][j]
+		}
+	}
+	return p
+}
+
+// MulVec4 returns the matrix-vector product m×v.
+func (m Mat4) MulVec4(v Vec4) Vec4 {
+	return Vec4{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z + m[0][3]*v.W,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z + m[1][3]*v.W,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z + m[2][3]*v.W,
+		W: m[3][0]*v.X + m[3][1]*v.Y + m[3][2]*v.Z + m[3][3]*v.W,
+	}
+}
+
+// NormalMatrix returns the inverse-transpose of the upper-left 3×3 part
+// of the matrix, which maps surface normals so they stay perpendicular
+// to surfaces under non-uniform scaling and shear.
+// It returns false if the matrix is singular.
+func (m Mat4) NormalMatrix() (Mat3, bool) {
+	inv, ok := m.Mat3().Inverse()
+	if !ok {
+		return Mat3{}, false
+	}
+	return inv.Transpose(), true
+}
+
+// ToQuaternion returns the unit quaternion for the rotation in the
+// upper-left 3×3 part of the matrix, which must be a rotation.
+// Use Decompose for transforms that also scale.
+func (m Mat4) ToQuaternion() Quaternion {
+	return m.Mat3().ToQuaternion()
+}
+
+// TransformDirection applies the transform to a direction (w = 0).
+// Translation does not affect directions. Use TransformPoint for
+// positions and TransformNormal for surface normals.
+func (m Mat4) TransformDirection(v Vec3) Vec3 {
+	return Vec3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// TransformNormal applies the transform to a surface normal and returns
+// the normalized result. Unlike TransformDirection, it keeps normals
+// perpendicular to transformed surfaces under non-uniform scaling.
+//
+// It uses the cofactor matrix, which is the inverse-transpose scaled by
+// the determinant, so no inverse is needed; the sign of the determinant
+// keeps normals facing outward when the transform mirrors geometry.
+// It returns the zero vector if the normal collapses.
+func (m Mat4) TransformNormal(n Vec3) Vec3 {
+	lin := m.Mat3()
+	c0, c1, c2 := lin.Col(0), lin.Col(1), lin.Col(2)
+	v := c1.Cross(c2).Mul(n.X).Add(c2.Cross(c0).Mul(n.Y)).Add(c0.Cross(c1).Mul(n.Z))
+	if lin.Determinant() < 0 {
+		v = v.Mul(-1)
+	}
+	return v.NormalizeOrZero()
+}
+
+// TransformPoint applies the transform to a point (w = 1),
+// including translation and the perspective divide.
+// Points mapped to infinity, such as a point on the eye plane of a
+// perspective projection, have infinite or NaN components.
+// To transform a position stored in a Vec3, convert it with Vec3.Point.
+func (m Mat4) TransformPoint(p Point) Point {
+	v := m.MulVec4(Vec4{X: p.X, Y: p.Y, Z: p.Z, W: 1})
+	if v.W != 1 {
+		return Point{X: v.X / v.W, Y: v.Y / v.W, Z: v.Z / v.W}
+	}
+	return Point{X: v.X, Y: v.Y, Z: v.Z}
+}
+
+// Translation returns the translation part of an affine transform.
+func (m Mat4) Translation() Vec3 {
+	return Vec3{X: m[0][3], Y: m[1][3], Z: m[2][3]}
+}
+
+// Transpose returns the transpose of the matrix.
+func (m Mat4) Transpose() Mat4 {
+	var t Mat4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			t[i][j] = m[j][i]
+		}
+	}
+	return t
+}
@@ -0,0 +1,267 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestMat4(t *testing.T) {
+	m := math3d.Mat4{
+		{2, 1, 0, 3},
+		{0, 1, 4, 1},
+		{1, 0, 1, 2},
+		{0, 2, 1, 1},
+	}
+	det := m.Determinant()
+	if det == 0 {
+		t.Fatalf("Determinant: want non-zero, got %f\n", det)
+	}
+	if d := m.Transpose().Determinant(); math.Abs(d-det) > 1e-12 {
+		t.Errorf("Determinant: transpose: want %f, got %f\n", det, d)
+	}
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatalf("Inverse: want ok, got singular\n")
+	}
+	if p := m.Mul(inv); !p.ApproxEqual(math3d.Identity4(), 1e-12) {
+		t.Errorf("Inverse: m×m⁻¹: want identity, got %v\n", p)
+	}
+	if p := inv.Mul(m); !p.ApproxEqual(math3d.Identity4(), 1e-12) {
+		t.Errorf("Inverse: m⁻¹×m: want identity, got %v\n", p)
+	}
+	if d := inv.Determinant(); math.Abs(d-1/det) > 1e-12 {
+		t.Errorf("Inverse: determinant: want %f, got %f\n", 1/det, d)
+	}
+	if _, ok := (math3d.Mat4{}).Inverse(); ok {
+		t.Errorf("Inverse: singular: want false, got true\n")
+	}
+
+	// scale, then rotate, then translate
+	xf := math3d.Mat4FromTranslation(math3d.NewVec3(10, 0, 0)).
+		Mul(math3d.Mat4FromAxisAngle(math3d.NewVec3(0, 0, 1), math.Pi/2)).
+		Mul(math3d.Mat4FromScale(math3d.NewVec3(2, 2, 2)))
+	p := xf.TransformPoint(math3d.Point{X: 1, Y: 0, Z: 0})
+	if expect := (math3d.Point{X: 10, Y: 2, Z: 0}); !p.ApproxEqual(expect, 1e-12) {
+		t.Errorf("TransformPoint: want %v, got %v\n", expect, p)
+	}
+	d := xf.TransformDirection(math3d.NewVec3(1, 0, 0))
+	if expect := math3d.NewVec3(0, 2, 0); !d.ApproxEqual(expect, 1e-12) {
+		t.Errorf("TransformDirection: want %v, got %v\n", expect, d)
+	}
+	v := xf.MulVec4(math3d.Vec4{X: 1, W: 1})
+	if expect := (math3d.Vec4{X: 10, Y: 2, W: 1}); !v.ApproxEqual(expect, 1e-12) {
+		t.Errorf("MulVec4: want %v, got %v\n", expect, v)
+	}
+}
+
+func TestAffine(t *testing.T) {
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(1, 2, 3)).
+		Mul(math3d.Mat4FromAxisAngle(math3d.NewVec3(1, 1, 0), 0.5)).
+		Mul(math3d.Mat4FromScale(math3d.NewVec3(2, 3, 4)))
+	n := math3d.Mat4FromAxisAngle(math3d.NewVec3(0, 1, 1), -1.2).
+		Mul(math3d.Mat4FromTranslation(math3d.NewVec3(-4, 0, 5)))
+	a, b := math3d.AffineFromMat4(m), math3d.AffineFromMat4(n)
+
+	if ab := a.Mul(b).Mat4(); !ab.ApproxEqual(m.Mul(n), 1e-12) {
+		t.Errorf("Mul: want %v, got %v\n", m.Mul(n), ab)
+	}
+	inv, ok := a.Inverse()
+	if !ok {
+		t.Fatalf("Inverse: want ok, got singular\n")
+	}
+	if id := a.Mul(inv); !id.ApproxEqual(math3d.IdentityAffine(), 1e-12) {
+		t.Errorf("Inverse: want identity, got %v\n", id)
+	}
+	p := math3d.Point{X: 1, Y: -2, Z: 0.5}
+	if q, expect := a.TransformPoint(p), m.TransformPoint(p); !q.ApproxEqual(expect, 1e-12) {
+		t.Errorf("TransformPoint: want %v, got %v\n", expect, q)
+	}
+	v := math3d.NewVec3(1, -2, 0.5)
+	if w, expect := a.TransformDirection(v), m.TransformDirection(v); !w.ApproxEqual(expect, 1e-12) {
+		t.Errorf("TransformDirection: want %v, got %v\n", expect, w)
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	angle := 0.8
+	for _, tt := range []struct {
+		name  string
+		scale math3d.Vec3
+	}{
+		{"uniform", math3d.NewVec3(2, 2, 2)},
+		{"non-uniform", math3d.NewVec3(1, 2, 3)},
+		{"mirrored", math3d.NewVec3(-1, 2, 3)},
+	} {
+		m := math3d.Mat4FromTranslation(math3d.NewVec3(1, 2, 3)).
+			Mul(math3d.Mat4FromAxisAngle(math3d.NewVec3(0, 0, 1), angle)).
+			Mul(math3d.Mat4FromScale(tt.scale))
+		tr, q, s, ok := m.Decompose()
+		if !ok {
+			t.Errorf("Decompose: %s: want ok, got degenerate\n", tt.name)
+			continue
+		}
+		if !tr.ApproxEqual(math3d.NewVec3(1, 2, 3), 1e-12) {
+			t.Errorf("Decompose: %s: translation: want %v, got %v\n", tt.name, math3d.NewVec3(1, 2, 3), tr)
+		}
+		if !s.ApproxEqual(tt.scale, 1e-12) {
+			t.Errorf("Decompose: %s: scale: want %v, got %v\n", tt.name, tt.scale, s)
+		}
+		expect := math3d.Quaternion{W: math.Cos(angle / 2), Z: math.Sin(angle / 2)}
+		if math.Abs(q.W-expect.W) > 1e-12 || math.Abs(q.X) > 1e-12 || math.Abs(q.Y) > 1e-12 || math.Abs(q.Z-expect.Z) > 1e-12 {
+			t.Errorf("Decompose: %s: rotation: want %v, got %v\n", tt.name, expect, q)
+		}
+	}
+
+	if _, _, _, ok := math3d.Mat4FromScale(math3d.NewVec3(1, 0, 1)).Decompose(); ok {
+		t.Errorf("Decompose: zero scale: want false, got true\n")
+	}
+}
+
+func TestMat4Constructors(t *testing.T) {
+	p := math3d.Point{X: 1, Y: 2, Z: 3}
+	for _, tt := range []struct {
+		name   string
+		m      math3d.Mat4
+		expect math3d.Point
+	}{
+		{"RotationX", math3d.Mat4FromRotationX(math.Pi / 2), math3d.Point{X: 1, Y: -3, Z: 2}},
+		{"RotationY", math3d.Mat4FromRotationY(math.Pi / 2), math3d.Point{X: 3, Y: 2, Z: -1}},
+		{"RotationZ", math3d.Mat4FromRotationZ(math.Pi / 2), math3d.Point{X: -2, Y: 1, Z: 3}},
+		{"UniformScale", math3d.Mat4FromUniformScale(2), math3d.Point{X: 2, Y: 4, Z: 6}},
+		{"ShearXY", math3d.Mat4FromShearXY(1, 2), math3d.Point{X: 4, Y: 8, Z: 3}},
+		{"ShearXZ", math3d.Mat4FromShearXZ(1, 2), math3d.Point{X: 3, Y: 2, Z: 7}},
+		{"ShearYZ", math3d.Mat4FromShearYZ(1, 2), math3d.Point{X: 1, Y: 3, Z: 5}},
+	} {
+		if q := tt.m.TransformPoint(p); !q.ApproxEqual(tt.expect, 1e-12) {
+			t.Errorf("%s: want %v, got %v\n", tt.name, tt.expect, q)
+		}
+	}
+}
+
+func TestTransformPointAtInfinity(t *testing.T) {
+	// the perspective divide sends points on the eye plane to infinity
+	m := math3d.Perspective(math.Pi/2, 1, 1, 100)
+	if p := m.TransformPoint(math3d.Point{X: 1, Y: 2, Z: 0}); p.IsFinite() {
+		t.Errorf("TransformPoint: eye plane: want non-finite point, got %v\n", p)
+	}
+	if p := m.TransformPoint(math3d.Point{X: 1, Y: 1, Z: -2}); !p.IsFinite() {
+		t.Errorf("TransformPoint: in front: want finite point, got %v\n", p)
+	}
+}
+
+func TestTransformNormal(t *testing.T) {
+	// the plane x + y = 0 has normal (1, 1, 0); stretching x by 2
+	// moves the plane to x/2 + y = 0 with normal (1, 2, 0)
+	m := math3d.Mat4FromScale(math3d.NewVec3(2, 1, 1))
+	n := math3d.NewVec3(1, 1, 0)
+	expect := math3d.NewVec3(1, 2, 0).Normalize()
+	if v := m.TransformNormal(n); !v.ApproxEqual(expect, 1e-12) {
+		t.Errorf("TransformNormal: want %v, got %v\n", expect, v)
+	}
+	nm, ok := m.NormalMatrix()
+	if !ok {
+		t.Fatalf("NormalMatrix: want ok, got singular\n")
+	}
+	if v := nm.MulVec3(n).Normalize(); !v.ApproxEqual(expect, 1e-12) {
+		t.Errorf("NormalMatrix: want %v, got %v\n", expect, v)
+	}
+
+	// mirroring flips the geometry, but normals must still face outward
+	mirror := math3d.Mat4FromScale(math3d.NewVec3(-1, 1, 1))
+	if v := mirror.TransformNormal(math3d.NewVec3(1, 0, 0)); !v.ApproxEqual(math3d.NewVec3(-1, 0, 0), 1e-12) {
+		t.Errorf("TransformNormal: mirrored: want %v, got %v\n", math3d.NewVec3(-1, 0, 0), v)
+	}
+}
+
+func TestTransformPointVsDirection(t *testing.T) {
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(5, 6, 7))
+	a, b := math3d.Point{X: 1, Y: 1, Z: 1}, math3d.Point{X: 2, Y: 3, Z: 4}
+
+	// translation moves points ...
+	if p := m.TransformPoint(a); p != (math3d.Point{X: 6, Y: 7, Z: 8}) {
+		t.Errorf("TransformPoint: want %v, got %v\n", math3d.Point{X: 6, Y: 7, Z: 8}, p)
+	}
+	// ... but not the directions between them
+	d := b.Sub(a)
+	if v := m.TransformDirection(d); v != d {
+		t.Errorf("TransformDirection: want %v, got %v\n", d, v)
+	}
+	if v := m.TransformPoint(b).Sub(m.TransformPoint(a)); v != m.TransformDirection(d) {
+		t.Errorf("TransformDirection: want %v, got %v\n", v, m.TransformDirection(d))
+	}
+	if p := a.Add(d); p != b {
+		t.Errorf("Point.Add: want %v, got %v\n", b, p)
+	}
+	if p := m.TransformPoint(d.Point()).Vec3(); p != math3d.NewVec3(6, 8, 10) {
+		t.Errorf("Vec3.Point: want %v, got %v\n", math3d.NewVec3(6, 8, 10), p)
+	}
+}
+
+func TestMatrixStack(t *testing.T) {
+	s := math3d.NewMatrixStack()
+	origin := math3d.Point{}
+
+	s.MultLocal(math3d.Mat4FromTranslation(math3d.NewVec3(1, 0, 0)))
+	s.Push()
+	s.MultLocal(math3d.Mat4FromRotationZ(math.Pi / 2))
+	s.MultLocal(math3d.Mat4FromTranslation(math3d.NewVec3(1, 0, 0)))
+	if p := s.Current().TransformPoint(origin); !p.ApproxEqual(math3d.Point{X: 1, Y: 1}, 1e-12) {
+		t.Errorf("MultLocal: want %v, got %v\n", math3d.Point{X: 1, Y: 1}, p)
+	}
+	if d := s.Depth(); d != 2 {
+		t.Errorf("Depth: want %d, got %d\n", 2, d)
+	}
+	if !s.Pop() {
+		t.Errorf("Pop: want true, got false\n")
+	}
+	if p := s.Current().TransformPoint(origin); p != (math3d.Point{X: 1}) {
+		t.Errorf("Pop: want %v, got %v\n", math3d.Point{X: 1}, p)
+	}
+	if s.Pop() {
+		t.Errorf("Pop: base: want false, got true\n")
+	}
+	s.Reset()
+	if s.Current() != math3d.Identity4() {
+		t.Errorf("Reset: want identity, got %v\n", s.Current())
+	}
+}
+
+func TestMat4FromRotationAround(t *testing.T) {
+	point, axis, angle := math3d.Point{X: 1, Y: 2, Z: 3}, math3d.NewVec3(1, -1, 2), 1.3
+	m := math3d.Mat4FromRotationAround(point, axis, angle)
+	want := math3d.Mat4FromTranslation(point.Vec3()).Mul(math3d.Mat4FromAxisAngle(axis, angle)).Mul(math3d.Mat4FromTranslation(point.Vec3().Mul(-1)))
+	if !m.ApproxEqual(want, 1e-12) {
+		t.Errorf("Mat4FromRotationAround: want %v, got %v\n", want, m)
+	}
+	if got := m.TransformPoint(point); !got.ApproxEqual(point, 1e-12) {
+		t.Errorf("Mat4FromRotationAround: pivot: want %v, got %v\n", point, got)
+	}
+	on := point.Add(axis.Mul(2))
+	if got := m.TransformPoint(on); !got.ApproxEqual(on, 1e-12) {
+		t.Errorf("Mat4FromRotationAround: axis: want %v, got %v\n", on, got)
+	}
+}
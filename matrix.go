@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"sort"
+)
+
+// Matrix implements a dense M×N matrix stored as a slice of rows.
+type Matrix []Vector
+
+// NewMatrix returns a zero matrix with the given number of rows and columns.
+func NewMatrix(rows, cols int) Matrix {
+	m := make(Matrix, rows, rows)
+	for i := range m {
+		m[i] = make(Vector, cols, cols)
+	}
+	return m
+}
+
+// IdentityMatrix returns the n×n identity matrix.
+func IdentityMatrix(n int) Matrix {
+	m := NewMatrix(n, n)
+	for i := range m {
+		m[i][i] = 1
+	}
+	return m
+}
+
+// Col returns a copy of column j of the matrix.
+func (m Matrix) Col(j int) Vector {
+	v := make(Vector, len(m), len(m))
+	for i, row := range m {
+		v[i] = row[j]
+	}
+	return v
+}
+
+// Cols returns the number of columns in the matrix.
+func (m Matrix) Cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+// Mul returns the matrix product m×n.
+// It panics if the number of columns in m does not match the number of rows in n.
+func (m Matrix) Mul(n Matrix) Matrix {
+	if m.Cols() != n.Rows() {
+		panic("math3d: matrix dimension mismatch")
+	}
+	p := NewMatrix(m.Rows(), n.Cols())
+	for i, row := range m {
+		for k, s := range row {
+			if s == 0 {
+				continue
+			}
+			for j, t := range n[k] {
+				p[i][j] += s * t
+			}
+		}
+	}
+	return p
+}
+
+// MulVector returns the matrix-vector product m×v.
+// It panics if the length of v does not match the number of columns in m.
+func (m Matrix) MulVector(v Vector) Vector {
+	if m.Cols() != len(v) {
+		panic("math3d: matrix dimension mismatch")
+	}
+	u := make(Vector, len(m), len(m))
+	for i, row := range m {
+		u[i] = row.Dot(v)
+	}
+	return u
+}
+
+// Row returns a copy of row i of the matrix.
+func (m Matrix) Row(i int) Vector {
+	return NewVector(m[i]...)
+}
+
+// Rows returns the number of rows in the matrix.
+func (m Matrix) Rows() int {
+	return len(m)
+}
+
+// Transpose returns the transpose of the matrix.
+func (m Matrix) Transpose() Matrix {
+	t := NewMatrix(m.Cols(), m.Rows())
+	for i, row := range m {
+		for j, s := range row {
+			t[j][i] = s
+		}
+	}
+	return t
+}
+
+// SymmetricEigen returns the eigenvalues of a symmetric matrix in
+// increasing order, along with a matrix whose columns are the
+// corresponding unit eigenvectors. It uses the cyclic Jacobi method,
+// which is slow for large matrices but accurate for small ones.
+// Only the upper triangle of m is read.
+func (m Matrix) SymmetricEigen() (Vector, Matrix) {
+	n := m.Rows()
+	a := NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a[i][j], a[j][i] = m[i][j], m[i][j]
+		}
+	}
+	v := IdentityMatrix(n)
+	for sweep := 0; sweep < 100; sweep++ {
+		var off, diag float64
+		for i := 0; i < n; i++ {
+			diag += a[i][i] * a[i][i]
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off <= 1e-30*diag || off == 0 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				// choose the rotation that zeroes a[p][q]
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	// sort the eigenpairs by increasing eigenvalue
+	order := make([]int, n, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return a[order[i]][order[i]] < a[order[j]][order[j]]
+	})
+	values, vectors := make(Vector, n, n), NewMatrix(n, n)
+	for j, k := range order {
+		values[j] = a[k][k]
+		for i := 0; i < n; i++ {
+			vectors[i][j] = v[i][k]
+		}
+	}
+	return values, vectors
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	a := math3d.Matrix{
+		math3d.NewVector(1, 2, 3),
+		math3d.NewVector(4, 5, 6),
+	}
+	b := a.Transpose()
+	if b.Rows() != 3 || b.Cols() != 2 {
+		t.Fatalf("Transpose: want 3x2, got %dx%d\n", b.Rows(), b.Cols())
+	}
+	if !b.Col(1).ApproxEqual(a.Row(1), 0) {
+		t.Errorf("Transpose: want %v, got %v\n", a.Row(1), b.Col(1))
+	}
+
+	p := a.Mul(b)
+	expect := math3d.Matrix{
+		math3d.NewVector(14, 32),
+		math3d.NewVector(32, 77),
+	}
+	for i := range expect {
+		if !p[i].ApproxEqual(expect[i], 0) {
+			t.Errorf("Mul: row %d: want %v, got %v\n", i, expect[i], p[i])
+		}
+	}
+
+	if v := a.MulVector(math3d.NewVector(1, 0, -1)); !v.ApproxEqual(math3d.NewVector(-2, -2), 0) {
+		t.Errorf("MulVector: want %v, got %v\n", math3d.NewVector(-2, -2), v)
+	}
+
+	id := math3d.IdentityMatrix(3)
+	if q := a.Mul(id); !q[1].ApproxEqual(a[1], 0) {
+		t.Errorf("IdentityMatrix: want %v, got %v\n", a[1], q[1])
+	}
+}
+
+func TestSymmetricEigen(t *testing.T) {
+	m := math3d.Matrix{
+		math3d.NewVector(4, 1, 2),
+		math3d.NewVector(1, 3, 0),
+		math3d.NewVector(2, 0, 5),
+	}
+	values, vectors := m.SymmetricEigen()
+	for i := 1; i < len(values); i++ {
+		if values[i] < values[i-1] {
+			t.Errorf("SymmetricEigen: want increasing eigenvalues, got %v\n", values)
+		}
+	}
+	for j := range values {
+		v := vectors.Col(j)
+		if l := v.Length(); l < 1-1e-12 || l > 1+1e-12 {
+			t.Errorf("SymmetricEigen: %d: want unit eigenvector, got length %f\n", j, l)
+		}
+		if mv := m.MulVector(v); !mv.ApproxEqual(v.Mul(values[j]), 1e-12) {
+			t.Errorf("SymmetricEigen: %d: want Av = λv, got %v and %v\n", j, mv, v.Mul(values[j]))
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Node is an element of a transform hierarchy. Each node has a local
+// transform made of a scale, then a rotation, then a translation,
+// relative to its parent. World transforms are computed on demand and
+// cached until the node or one of its ancestors changes.
+//
+// Nodes must be created with NewNode.
+type Node struct {
+	parent   *Node
+	children []*Node
+
+	translation Vec3
+	rotation    Quaternion
+	scale       Vec3
+
+	local, world           Mat4
+	localDirty, worldDirty bool
+}
+
+// NewNode returns a node with the identity transform and no parent.
+func NewNode() *Node {
+	return &Node{
+		rotation: Quaternion{W: 1},
+		scale:    Vec3{X: 1, Y: 1, Z: 1},
+		local:    Identity4(),
+		world:    Identity4(),
+	}
+}
+
+// AddChild makes c a child of n. See SetParent.
+func (n *Node) AddChild(c *Node) bool {
+	return c.SetParent(n)
+}
+
+// Children returns the children of the node.
+// The returned slice must not be modified.
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// LocalMatrix returns the transform from the node's space to its parent's.
+//
+//	local = T × R × S
+func (n *Node) LocalMatrix() Mat4 {
+	if n.localDirty {
+		rs := n.rotation.ToMat3().Mul(Mat3FromScale(n.scale))
+		n.local = AffineFromLinear(rs, n.translation).Mat4()
+		n.localDirty = false
+	}
+	return n.local
+}
+
+// Parent returns the parent of the node, or nil if it is a root.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Rotation returns the local rotation of the node.
+func (n *Node) Rotation() Quaternion {
+	return n.rotation
+}
+
+// Scale returns the local scale of the node.
+func (n *Node) Scale() Vec3 {
+	return n.scale
+}
+
+// SetParent moves the node under a new parent, or makes it a root if
+// parent is nil. Its local transform is unchanged, so its world
+// transform follows the new parent. It returns false, leaving the
+// hierarchy unchanged, if parent is the node itself or one of its
+// descendants.
+func (n *Node) SetParent(parent *Node) bool {
+	for p := parent; p != nil; p = p.parent {
+		if p == n {
+			return false
+		}
+	}
+	if n.parent != nil {
+		siblings := n.parent.children
+		for i, c := range siblings {
+			if c == n {
+				n.parent.children = append(siblings[:i], siblings[i+1:]...)
+				break
+			}
+		}
+	}
+	n.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, n)
+	}
+	n.invalidateWorld()
+	return true
+}
+
+// SetRotation sets the local rotation of the node.
+// The rotation should be a unit quaternion.
+func (n *Node) SetRotation(q Quaternion) {
+	n.rotation = q
+	n.invalidateLocal()
+}
+
+// SetScale sets the local scale of the node.
+func (n *Node) SetScale(s Vec3) {
+	n.scale = s
+	n.invalidateLocal()
+}
+
+// SetTranslation sets the local translation of the node.
+func (n *Node) SetTranslation(t Vec3) {
+	n.translation = t
+	n.invalidateLocal()
+}
+
+// Translation returns the local translation of the node.
+func (n *Node) Translation() Vec3 {
+	return n.translation
+}
+
+// WorldMatrix returns the transform from the node's space to world space.
+func (n *Node) WorldMatrix() Mat4 {
+	if n.worldDirty {
+		if n.parent == nil {
+			n.world = n.LocalMatrix()
+		} else {
+			n.world = n.parent.WorldMatrix().Mul(n.LocalMatrix())
+		}
+		n.worldDirty = false
+	}
+	return n.world
+}
+
+// WorldPosition returns the origin of the node's space in world space.
+func (n *Node) WorldPosition() Point {
+	return n.WorldMatrix().Translation().Point()
+}
+
+func (n *Node) invalidateLocal() {
+	n.localDirty = true
+	n.invalidateWorld()
+}
+
+// invalidateWorld marks the world transforms of the node and its
+// descendants as stale. A stale node's descendants are always stale,
+// so the walk stops at nodes that are already marked.
+func (n *Node) invalidateWorld() {
+	if n.worldDirty {
+		return
+	}
+	n.worldDirty = true
+	for _, c := range n.children {
+		c.invalidateWorld()
+	}
+}
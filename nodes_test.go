@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestNode(t *testing.T) {
+	root, arm, hand := math3d.NewNode(), math3d.NewNode(), math3d.NewNode()
+	root.AddChild(arm)
+	arm.AddChild(hand)
+	arm.SetTranslation(math3d.NewVec3(1, 0, 0))
+	hand.SetTranslation(math3d.NewVec3(1, 0, 0))
+
+	if p := hand.WorldPosition(); !p.ApproxEqual(math3d.Point{X: 2}, 1e-12) {
+		t.Errorf("WorldPosition: want %v, got %v\n", math3d.Point{X: 2}, p)
+	}
+
+	// rotating the root swings the cached descendants
+	root.SetRotation(math3d.QuaternionFromRotationVector(math3d.NewVec3(0, 0, math.Pi/2)))
+	if p := hand.WorldPosition(); !p.ApproxEqual(math3d.Point{Y: 2}, 1e-12) {
+		t.Errorf("WorldPosition: rotated: want %v, got %v\n", math3d.Point{Y: 2}, p)
+	}
+	arm.SetScale(math3d.NewVec3(3, 3, 3))
+	if p := hand.WorldPosition(); !p.ApproxEqual(math3d.Point{Y: 4}, 1e-12) {
+		t.Errorf("WorldPosition: scaled: want %v, got %v\n", math3d.Point{Y: 4}, p)
+	}
+
+	if root.SetParent(hand) {
+		t.Errorf("SetParent: cycle: want false, got true\n")
+	}
+	hand.SetParent(nil)
+	if len(arm.Children()) != 0 || hand.Parent() != nil {
+		t.Errorf("SetParent: nil: want detached node\n")
+	}
+	if p := hand.WorldPosition(); !p.ApproxEqual(math3d.Point{X: 1}, 1e-12) {
+		t.Errorf("WorldPosition: detached: want %v, got %v\n", math3d.Point{X: 1}, p)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// OBB is an oriented bounding box. The columns of Orientation are the
+// unit axes of the box, which must be orthonormal, and HalfExtents are
+// the distances from Center to the faces along those axes.
+type OBB struct {
+	Center      Point
+	HalfExtents Vec3
+	Orientation Mat3
+}
+
+// OBBFromAABB returns the axis-aligned box b as an oriented box.
+func OBBFromAABB(b AABB) OBB {
+	return OBB{Center: b.Center(), HalfExtents: b.Extents(), Orientation: Identity3()}
+}
+
+// OBBFromPoints returns an oriented box containing all the points, with
+// axes along the principal axes of the points' covariance. This usually
+// fits elongated point sets much more tightly than an AABB, but it is
+// not the smallest possible box. It returns false if there are no points.
+func OBBFromPoints(points []Point) (OBB, bool) {
+	if len(points) == 0 {
+		return OBB{}, false
+	}
+	mean, cov := centroidCovariance(points)
+	axes, _ := principalAxes(cov)
+	var lo, hi [3]float64
+	for i := range lo {
+		lo[i], hi[i] = math.Inf(1), math.Inf(-1)
+	}
+	for _, p := range points {
+		d := p.Sub(mean)
+		for i := range lo {
+			s := d.Dot(axes.Col(i))
+			lo[i], hi[i] = math.Min(lo[i], s), math.Max(hi[i], s)
+		}
+	}
+	center := mean
+	for i := range lo {
+		center = center.Add(axes.Col(i).Mul((lo[i] + hi[i]) / 2))
+	}
+	return OBB{
+		Center:      center,
+		HalfExtents: Vec3{X: (hi[0] - lo[0]) / 2, Y: (hi[1] - lo[1]) / 2, Z: (hi[2] - lo[2]) / 2},
+		Orientation: axes,
+	}, true
+}
+
+// AABB returns the smallest axis-aligned box containing the box.
+func (o OBB) AABB() AABB {
+	var r Vec3
+	for i, e := range o.extents() {
+		c := o.Orientation.Col(i).Abs()
+		r = r.Add(c.Mul(e))
+	}
+	return AABB{Min: o.Center.Add(r.Mul(-1)), Max: o.Center.Add(r)}
+}
+
+// ClosestPointTo returns the point in the box nearest to p.
+// Points inside the box are returned unchanged.
+func (o OBB) ClosestPointTo(p Point) Point {
+	d := p.Sub(o.Center)
+	q := o.Center
+	for i, e := range o.extents() {
+		axis := o.Orientation.Col(i)
+		q = q.Add(axis.Mul(Clamp(d.Dot(axis), -e, e)))
+	}
+	return q
+}
+
+// ContainsPoint reports whether p is inside the box or on its boundary.
+func (o OBB) ContainsPoint(p Point) bool {
+	d := p.Sub(o.Center)
+	for i, e := range o.extents() {
+		if math.Abs(d.Dot(o.Orientation.Col(i))) > e {
+			return false
+		}
+	}
+	return true
+}
+
+// Corners returns the eight corners of the box. Bits 0, 1, and 2 of the
+// index select the positive end of the first, second, and third axis.
+func (o OBB) Corners() [8]Point {
+	var corners [8]Point
+	for i := range corners {
+		p := o.Center
+		for j, e := range o.extents() {
+			if i&(1<<j) == 0 {
+				e = -e
+			}
+			p = p.Add(o.Orientation.Col(j).Mul(e))
+		}
+		corners[i] = p
+	}
+	return corners
+}
+
+// IntersectsAABB reports whether the oriented box and the axis-aligned
+// box overlap or touch.
+func (o OBB) IntersectsAABB(b AABB) bool {
+	return o.IntersectsOBB(OBBFromAABB(b))
+}
+
+// IntersectsOBB reports whether the two boxes overlap or touch. It uses
+// the separating axis theorem, testing the three face axes of each box
+// and the nine cross products of their edges, following Ericson,
+// Real-Time Collision Detection, §4.4.1.
+func (o OBB) IntersectsOBB(o2 OBB) bool {
+	// an epsilon keeps near-parallel edge axes from being reported
+	// as separating because of rounding in their tiny cross products
+	const epsilon = 1e-12
+	ea, eb := o.extents(), o2.extents()
+	var r, absR [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = o.Orientation.Col(i).Dot(o2.Orientation.Col(j))
+			absR[i][j] = math.Abs(r[i][j]) + epsilon
+		}
+	}
+	d := o2.Center.Sub(o.Center)
+	t := [3]float64{d.Dot(o.Orientation.Col(0)), d.Dot(o.Orientation.Col(1)), d.Dot(o.Orientation.Col(2))}
+
+	for i := 0; i < 3; i++ {
+		ra, rb := ea[i], eb[0]*absR[i][0]+eb[1]*absR[i][1]+eb[2]*absR[i][2]
+		if math.Abs(t[i]) > ra+rb {
+			return false
+		}
+	}
+	for j := 0; j < 3; j++ {
+		ra, rb := ea[0]*absR[0][j]+ea[1]*absR[1][j]+ea[2]*absR[2][j], eb[j]
+		if math.Abs(t[0]*r[0][j]+t[1]*r[1][j]+t[2]*r[2][j]) > ra+rb {
+			return false
+		}
+	}
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra := ea[i1]*absR[i2][j] + ea[i2]*absR[i1][j]
+			rb := eb[j1]*absR[i][j2] + eb[j2]*absR[i][j1]
+			if math.Abs(t[i2]*r[i1][j]-t[i1]*r[i2][j]) > ra+rb {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IntersectsSphere reports whether the box and the sphere overlap or touch.
+func (o OBB) IntersectsSphere(s Sphere) bool {
+	return s.ContainsPoint(o.ClosestPointTo(s.Center))
+}
+
+// Support returns the corner of the box furthest in the direction d.
+// It implements the ConvexShape interface.
+func (o OBB) Support(d Vec3) Point {
+	p := o.Center
+	for i, e := range o.extents() {
+		u := o.Orientation.Col(i)
+		p = p.Add(u.Mul(math.Copysign(e, u.Dot(d))))
+	}
+	return p
+}
+
+// extents returns the half-extents as an array indexed by axis.
+func (o OBB) extents() [3]float64 {
+	return [3]float64{o.HalfExtents.X, o.HalfExtents.Y, o.HalfExtents.Z}
+}
+
+// projectedRadius returns half the length of the projection of the box
+// onto the unit vector n.
+func (o OBB) projectedRadius(n Vec3) float64 {
+	var r float64
+	for i, e := range o.extents() {
+		r += e * math.Abs(o.Orientation.Col(i).Dot(n))
+	}
+	return r
+}
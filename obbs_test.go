@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestOBB(t *testing.T) {
+	// a 4×2×2 box centered at (1, 1, 0), turned 45° about z
+	o := math3d.OBB{
+		Center:      math3d.Point{X: 1, Y: 1},
+		HalfExtents: math3d.NewVec3(2, 1, 1),
+		Orientation: math3d.Mat3FromRotationZ(math.Pi / 4),
+	}
+	s2 := math.Sqrt2
+	if !o.ContainsPoint(math3d.Point{X: 1 + s2, Y: 1 + s2}) || o.ContainsPoint(math3d.Point{X: 2.5, Y: -0.5}) {
+		t.Errorf("ContainsPoint: want along the long axis in and across it out\n")
+	}
+	if got, want := o.ClosestPointTo(math3d.Point{X: 1 + s2, Y: 1 - s2, Z: 5}), (math3d.Point{X: 1 + s2/2, Y: 1 - s2/2, Z: 1}); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("ClosestPointTo: want %v, got %v\n", want, got)
+	}
+	bounds := o.AABB()
+	want := math3d.EmptyAABB()
+	for _, c := range o.Corners() {
+		want = want.Include(c)
+	}
+	if !bounds.Min.ApproxEqual(want.Min, 1e-12) || !bounds.Max.ApproxEqual(want.Max, 1e-12) {
+		t.Errorf("AABB: want %v, got %v\n", want, bounds)
+	}
+
+	for _, tt := range []struct {
+		name string
+		o2   math3d.OBB
+		want bool
+	}{
+		{"overlapping", math3d.OBB{Center: math3d.Point{X: 3, Y: 3}, HalfExtents: math3d.NewVec3(1, 1, 1), Orientation: math3d.Identity3()}, true},
+		// inside the AABB of o but past its long face
+		{"corner gap", math3d.OBB{Center: math3d.Point{X: 3.2, Y: -0.6}, HalfExtents: math3d.NewVec3(0.3, 0.3, 0.3), Orientation: math3d.Identity3()}, false},
+		{"parallel", math3d.OBB{Center: math3d.Point{X: 1, Y: 1, Z: 2}, HalfExtents: math3d.NewVec3(2, 1, 1), Orientation: o.Orientation}, true},
+	} {
+		if got := o.IntersectsOBB(tt.o2); got != tt.want {
+			t.Errorf("IntersectsOBB: %s: want %v, got %v\n", tt.name, tt.want, got)
+		}
+		if got := tt.o2.IntersectsOBB(o); got != tt.want {
+			t.Errorf("IntersectsOBB: %s: reversed: want %v, got %v\n", tt.name, tt.want, got)
+		}
+	}
+
+	// two crossed sticks with diamond cross-sections are separated only
+	// by the cross product of their long edges
+	sx := math3d.OBB{HalfExtents: math3d.NewVec3(2, 0.1, 0.1), Orientation: math3d.Mat3FromRotationX(math.Pi / 4)}
+	for _, tt := range []struct {
+		z    float64
+		want bool
+	}{{0.3, false}, {0.28, true}} {
+		sy := math3d.OBB{Center: math3d.Point{Z: tt.z}, HalfExtents: math3d.NewVec3(0.1, 2, 0.1), Orientation: math3d.Mat3FromRotationY(math.Pi / 4)}
+		if got := sx.IntersectsOBB(sy); got != tt.want {
+			t.Errorf("IntersectsOBB: crossed at %v: want %v, got %v\n", tt.z, tt.want, got)
+		}
+	}
+
+	if !o.IntersectsAABB(math3d.AABB{Min: math3d.Point{X: 2, Y: 2, Z: -1}, Max: math3d.Point{X: 4, Y: 4, Z: 1}}) {
+		t.Errorf("IntersectsAABB: want true, got false\n")
+	}
+	if o.IntersectsAABB(math3d.AABB{Min: math3d.Point{X: 2.8, Y: -0.9, Z: -1}, Max: math3d.Point{X: 3.4, Y: -0.3, Z: 1}}) {
+		t.Errorf("IntersectsAABB: want false, got true\n")
+	}
+	if !o.IntersectsSphere(math3d.Sphere{Center: math3d.Point{X: 1, Y: 1, Z: 1.5}, Radius: 0.6}) {
+		t.Errorf("IntersectsSphere: want true, got false\n")
+	}
+	if o.IntersectsSphere(math3d.Sphere{Center: math3d.Point{X: 1, Y: 1, Z: 1.5}, Radius: 0.4}) {
+		t.Errorf("IntersectsSphere: want false, got true\n")
+	}
+}
+
+func TestOBBFromPoints(t *testing.T) {
+	o := math3d.OBB{
+		Center:      math3d.Point{X: 5, Y: -2, Z: 1},
+		HalfExtents: math3d.NewVec3(4, 2, 1),
+		Orientation: math3d.Mat3FromAxisAngle(math3d.NewVec3(1, 2, 3), 0.8),
+	}
+	corners := o.Corners()
+	fit, ok := math3d.OBBFromPoints(corners[:])
+	if !ok {
+		t.Fatalf("OBBFromPoints: want ok, got !ok\n")
+	}
+	if !fit.Center.ApproxEqual(o.Center, 1e-9) {
+		t.Errorf("OBBFromPoints: center: want %v, got %v\n", o.Center, fit.Center)
+	}
+	if d := fit.Orientation.Determinant(); math.Abs(d-1) > 1e-9 {
+		t.Errorf("OBBFromPoints: want a rotation, got determinant %v\n", d)
+	}
+	loose := fit
+	loose.HalfExtents = loose.HalfExtents.Add(math3d.NewVec3(1e-9, 1e-9, 1e-9))
+	for _, c := range corners {
+		if !loose.ContainsPoint(c) {
+			t.Errorf("OBBFromPoints: want corner %v inside, got outside\n", c)
+		}
+	}
+	vol := fit.HalfExtents.X * fit.HalfExtents.Y * fit.HalfExtents.Z
+	if want := 8.0; math.Abs(vol-want) > 1e-6 {
+		t.Errorf("OBBFromPoints: half-volume: want %v, got %v\n", want, vol)
+	}
+	if _, ok := math3d.OBBFromPoints(nil); ok {
+		t.Errorf("OBBFromPoints: no points: want !ok, got ok\n")
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "sort"
+
+// Octree is a spatial index of items with bounding boxes, such as points
+// or meshes, for box and ray queries. Each item is stored in the
+// smallest node that holds its whole box, so items that straddle the
+// center of a node stay in that node rather than being split. Nodes
+// divide into eight children when they hold more than a few items.
+// Items are identified by integers chosen by the caller.
+type Octree struct {
+	root  *octreeNode
+	nodes map[int]*octreeNode // the node holding each item
+}
+
+const (
+	// octreeMaxItems is the number of items a node holds before it splits.
+	octreeMaxItems = 8
+	// octreeMaxDepth limits the depth of the tree, which matters when many
+	// items share a position.
+	octreeMaxDepth = 16
+)
+
+type octreeNode struct {
+	bounds   AABB
+	depth    int
+	children *[8]*octreeNode
+	items    []octreeItem
+}
+
+type octreeItem struct {
+	id     int
+	bounds AABB
+}
+
+// OctreeFromBounds returns an empty octree covering the box. Items
+// outside the box may still be inserted, but they are kept in the root
+// and slow every query.
+func OctreeFromBounds(bounds AABB) *Octree {
+	return &Octree{root: &octreeNode{bounds: bounds}, nodes: map[int]*octreeNode{}}
+}
+
+// Insert adds the item with the given bounding box, replacing any item
+// with the same id.
+func (o *Octree) Insert(id int, bounds AABB) {
+	o.Remove(id)
+	o.root.insert(o, octreeItem{id: id, bounds: bounds})
+}
+
+// InsertPoint adds the item at the point p, replacing any item with the
+// same id.
+func (o *Octree) InsertPoint(id int, p Point) {
+	o.Insert(id, AABB{Min: p, Max: p})
+}
+
+// Len returns the number of items in the tree.
+func (o *Octree) Len() int {
+	return len(o.nodes)
+}
+
+// QueryAABB returns the ids of the items whose bounding boxes overlap or
+// touch the box b, in no particular order.
+func (o *Octree) QueryAABB(b AABB) []int {
+	var ids []int
+	o.root.visit(func(n *octreeNode) bool {
+		if !n.bounds.IntersectsAABB(b) && n != o.root {
+			return false
+		}
+		for _, it := range n.items {
+			if it.bounds.IntersectsAABB(b) {
+				ids = append(ids, it.id)
+			}
+		}
+		return true
+	})
+	return ids
+}
+
+// QueryRay returns the ids of the items whose bounding boxes the ray
+// passes through at or after its origin, ordered by where the ray enters
+// them. The caller tests the items themselves, stopping at the first hit
+// that is nearer than the next entry.
+func (o *Octree) QueryRay(r Ray) []int {
+	type hit struct {
+		id int
+		t  float64
+	}
+	var hits []hit
+	o.root.visit(func(n *octreeNode) bool {
+		if _, ok := r.intersectAABB(n.bounds); !ok && n != o.root {
+			return false
+		}
+		for _, it := range n.items {
+			if t, ok := r.intersectAABB(it.bounds); ok {
+				hits = append(hits, hit{id: it.id, t: t})
+			}
+		}
+		return true
+	})
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].t < hits[j].t })
+	ids := make([]int, len(hits))
+	for i, h := range hits {
+		ids[i] = h.id
+	}
+	return ids
+}
+
+// Remove deletes the item with the given id. It returns false if there
+// is no such item.
+func (o *Octree) Remove(id int) bool {
+	n, ok := o.nodes[id]
+	if !ok {
+		return false
+	}
+	for i, it := range n.items {
+		if it.id == id {
+			n.items = append(n.items[:i], n.items[i+1:]...)
+			break
+		}
+	}
+	delete(o.nodes, id)
+	return true
+}
+
+// insert stores the item in the smallest node under n that holds it.
+func (n *octreeNode) insert(o *Octree, it octreeItem) {
+	if n.children != nil {
+		if c := n.childFor(it.bounds); c != nil {
+			c.insert(o, it)
+			return
+		}
+	}
+	n.items = append(n.items, it)
+	o.nodes[it.id] = n
+	if n.children == nil && len(n.items) > octreeMaxItems && n.depth < octreeMaxDepth {
+		n.split(o)
+	}
+}
+
+// childFor returns the child of n that holds the whole box, or nil if
+// the box straddles the children or lies outside n.
+func (n *octreeNode) childFor(b AABB) *octreeNode {
+	for _, c := range n.children {
+		if c.bounds.ContainsPoint(b.Min) && c.bounds.ContainsPoint(b.Max) {
+			return c
+		}
+	}
+	return nil
+}
+
+// split divides n into eight children and moves down the items that fit
+// in one of them.
+func (n *octreeNode) split(o *Octree) {
+	center := n.bounds.Center()
+	n.children = &[8]*octreeNode{}
+	for i, corner := range n.bounds.Corners() {
+		n.children[i] = &octreeNode{bounds: AABB{Min: corner.Min(center), Max: corner.Max(center)}, depth: n.depth + 1}
+	}
+	items := n.items
+	n.items = nil
+	for _, it := range items {
+		n.insert(o, it)
+	}
+}
+
+// visit calls f for n and, while f returns true, for its descendants.
+func (n *octreeNode) visit(f func(*octreeNode) bool) {
+	if !f(n) || n.children == nil {
+		return
+	}
+	for _, c := range n.children {
+		c.visit(f)
+	}
+}
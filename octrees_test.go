@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestOctree(t *testing.T) {
+	rng := rand.New(rand.NewSource(837))
+	random := func(scale float64) math3d.Point {
+		return math3d.Point{X: (rng.Float64()*2 - 1) * scale, Y: (rng.Float64()*2 - 1) * scale, Z: (rng.Float64()*2 - 1) * scale}
+	}
+	world := math3d.AABB{Min: math3d.Point{X: -10, Y: -10, Z: -10}, Max: math3d.Point{X: 10, Y: 10, Z: 10}}
+	tree := math3d.OctreeFromBounds(world)
+	boxes := map[int]math3d.AABB{}
+	for id := 0; id < 400; id++ {
+		c := random(10)
+		b := math3d.AABB{Min: c, Max: c}
+		if id%2 == 0 {
+			b = b.Expand(rng.Float64() * 0.5)
+			tree.Insert(id, b)
+		} else {
+			tree.InsertPoint(id, c)
+		}
+		boxes[id] = b
+	}
+	// one item outside the bounds, and some removed or moved
+	boxes[400] = math3d.AABB{Min: math3d.Point{X: 20, Y: -1, Z: -1}, Max: math3d.Point{X: 21, Y: 1, Z: 1}}
+	tree.Insert(400, boxes[400])
+	for id, x := range map[int]float64{500: 4, 501: -6, 502: 0} {
+		boxes[id] = math3d.AABB{Min: math3d.Point{X: x, Y: -0.5, Z: -0.5}, Max: math3d.Point{X: x + 1, Y: 0.5, Z: 0.5}}
+		tree.Insert(id, boxes[id])
+	}
+	for id := 0; id < 100; id += 3 {
+		if !tree.Remove(id) {
+			t.Errorf("Remove(%d): want true, got false\n", id)
+		}
+		delete(boxes, id)
+	}
+	if tree.Remove(0) {
+		t.Errorf("Remove(0): again: want false, got true\n")
+	}
+	for id := 1; id < 100; id += 3 {
+		c := random(10)
+		boxes[id] = math3d.AABB{Min: c, Max: c}
+		tree.InsertPoint(id, c)
+	}
+	if tree.Len() != len(boxes) {
+		t.Errorf("Len: want %d, got %d\n", len(boxes), tree.Len())
+	}
+
+	for q := 0; q < 50; q++ {
+		c := random(12)
+		query := math3d.AABB{Min: c, Max: c}.Expand(3)
+		got := tree.QueryAABB(query)
+		var want []int
+		for id, b := range boxes {
+			if b.IntersectsAABB(query) {
+				want = append(want, id)
+			}
+		}
+		sort.Ints(got)
+		sort.Ints(want)
+		if !equalInts(got, want) {
+			t.Errorf("QueryAABB(%v): want %v, got %v\n", query, want, got)
+		}
+	}
+
+	r := math3d.Ray{Origin: math3d.Point{X: -30, Y: 0.1, Z: 0.2}, Direction: math3d.NewVec3(1, 0, 0)}
+	got := tree.QueryRay(r)
+	var want []int
+	for id, b := range boxes {
+		if b.Min.Y <= 0.1 && b.Max.Y >= 0.1 && b.Min.Z <= 0.2 && b.Max.Z >= 0.2 {
+			want = append(want, id)
+		}
+	}
+	// along the x-axis, the ray enters each box at its least x
+	sort.Slice(want, func(i, j int) bool { return boxes[want[i]].Min.X < boxes[want[j]].Min.X })
+	if !equalInts(got, want) || len(got) < 4 || got[len(got)-1] != 400 {
+		t.Errorf("QueryRay: want %v, got %v\n", want, got)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
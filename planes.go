@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Plane is the set of points p where Normal·p + D = 0.
+// Distances are only true distances when Normal is a unit vector.
+type Plane struct {
+	Normal Vec3
+	D      float64
+}
+
+// NewPlane returns the plane of points p where normal·p + d = 0.
+// When normal is a unit vector, the origin is at signed distance d
+// from the plane.
+func NewPlane(normal Vec3, d float64) Plane {
+	return Plane{Normal: normal, D: d}
+}
+
+// PlaneFromPointNormal returns the plane through p with the given normal.
+func PlaneFromPointNormal(p Point, normal Vec3) Plane {
+	return Plane{Normal: normal, D: -normal.Dot(p.Vec3())}
+}
+
+// PlaneFromPoints returns the plane through a, b, and c with a unit
+// normal. The normal faces the side from which the points appear in
+// counter-clockwise order. It returns false if the points are collinear.
+func PlaneFromPoints(a, b, c Point) (Plane, bool) {
+	n, ok := b.Sub(a).Cross(c.Sub(a)).Normalized()
+	if !ok {
+		return Plane{}, false
+	}
+	return PlaneFromPointNormal(a, n), true
+}
+
+// IntersectThreePlanes returns the single point shared by three planes.
+// It returns false if two or more of the planes are parallel, or if the
+// three planes share a line, so that no single point is shared.
+//
+//	p = −(D₁(n₂×n₃) + D₂(n₃×n₁) + D₃(n₁×n₂)) / n₁·(n₂×n₃)
+func IntersectThreePlanes(a, b, c Plane) (Point, bool) {
+	bc := b.Normal.Cross(c.Normal)
+	det := a.Normal.Dot(bc)
+	if det == 0 {
+		return Point{}, false
+	}
+	v := bc.Mul(a.D).Add(c.Normal.Cross(a.Normal).Mul(b.D)).Add(a.Normal.Cross(b.Normal).Mul(c.D))
+	p := v.Div(-det).Point()
+	return p, p.IsFinite()
+}
+
+// PlaneIntersectPlane returns the line shared by two planes. The line's
+// direction is the cross product of the normals, a × b. It returns false
+// if the planes are parallel or coincide.
+//
+//	u = n₁ × n₂
+//	p = ((D₂n₁ − D₁n₂) × u) / |u|²
+func PlaneIntersectPlane(a, b Plane) (Line, bool) {
+	u := a.Normal.Cross(b.Normal)
+	uu := u.LengthSquared()
+	if uu == 0 {
+		return Line{}, false
+	}
+	p := a.Normal.Mul(b.D).Sub(b.Normal.Mul(a.D)).Cross(u).Div(uu).Point()
+	if !p.IsFinite() {
+		return Line{}, false
+	}
+	return Line{Origin: p, Direction: u}, true
+}
+
+// Normalize returns the same plane scaled so that its normal is a unit
+// vector, which makes SignedDistance a true distance. It returns pl
+// unchanged if the normal is zero; see Normalized to detect that case.
+func (pl Plane) Normalize() Plane {
+	if n, ok := pl.Normalized(); ok {
+		return n
+	}
+	return pl
+}
+
+// Normalized returns the same plane scaled so that its normal is a unit
+// vector. It returns false if the normal is zero or not finite.
+func (pl Plane) Normalized() (Plane, bool) {
+	length := pl.Normal.Length()
+	if length == 0 || math.IsInf(length, 0) || math.IsNaN(length) {
+		return Plane{}, false
+	}
+	return Plane{Normal: pl.Normal.Div(length), D: pl.D / length}, true
+}
+
+// ProjectPoint returns the point on the plane nearest to p.
+// The normal need not be a unit vector, but it must not be zero.
+func (pl Plane) ProjectPoint(p Point) Point {
+	return p.Add(pl.Normal.Mul(-pl.SignedDistance(p) / pl.Normal.LengthSquared()))
+}
+
+// SignedDistance returns the distance from the plane to p.
+// It is positive on the side the normal points towards.
+func (pl Plane) SignedDistance(p Point) float64 {
+	return pl.Normal.Dot(p.Vec3()) + pl.D
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestPlane(t *testing.T) {
+	a, b, c := math3d.Point{X: 1, Y: 0, Z: 2}, math3d.Point{X: 0, Y: 1, Z: 2}, math3d.Point{X: 0, Y: 0, Z: 2}
+	pl, ok := math3d.PlaneFromPoints(c, a, b)
+	if !ok {
+		t.Fatalf("PlaneFromPoints: want ok, got !ok\n")
+	}
+	if want := math3d.NewPlane(math3d.NewVec3(0, 0, 1), -2); pl.Normal != want.Normal || pl.D != want.D {
+		t.Errorf("PlaneFromPoints: want %v, got %v\n", want, pl)
+	}
+	for _, p := range []math3d.Point{a, b, c} {
+		if d := pl.SignedDistance(p); d != 0 {
+			t.Errorf("PlaneFromPoints: SignedDistance(%v): want 0, got %v\n", p, d)
+		}
+	}
+	if _, ok := math3d.PlaneFromPoints(a, b, math3d.Point{X: 2, Y: -1, Z: 2}); ok {
+		t.Errorf("PlaneFromPoints: collinear: want !ok, got ok\n")
+	}
+
+	// a plane with a non-unit normal measures scaled distances until normalized
+	p := math3d.PlaneFromPointNormal(math3d.Point{X: 1, Y: 1, Z: 1}, math3d.NewVec3(0, 3, 4))
+	q := math3d.Point{X: 7, Y: 4, Z: 5}
+	if d := p.SignedDistance(q); d != 25 {
+		t.Errorf("SignedDistance: want 25, got %v\n", d)
+	}
+	n := p.Normalize()
+	if d := n.SignedDistance(q); math.Abs(d-5) > 1e-12 {
+		t.Errorf("Normalize: SignedDistance: want 5, got %v\n", d)
+	}
+	want := math3d.Point{X: 7, Y: 1, Z: 1}
+	if got := p.ProjectPoint(q); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("ProjectPoint: want %v, got %v\n", want, got)
+	}
+	if got := n.ProjectPoint(q); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("ProjectPoint: normalized: want %v, got %v\n", want, got)
+	}
+	if _, ok := (math3d.Plane{D: 1}).Normalized(); ok {
+		t.Errorf("Normalized: zero normal: want !ok, got ok\n")
+	}
+}
+
+func TestPlaneIntersections(t *testing.T) {
+	a := math3d.NewPlane(math3d.NewVec3(0, 0, 1), 0)
+	b := math3d.NewPlane(math3d.NewVec3(2, 0, 0), -2)
+	l, ok := math3d.PlaneIntersectPlane(a, b)
+	if !ok {
+		t.Fatalf("PlaneIntersectPlane: want ok, got !ok\n")
+	}
+	for _, s := range []float64{0, 1, -3} {
+		p := l.PointAt(s)
+		if math.Abs(a.SignedDistance(p)) > 1e-12 || math.Abs(b.SignedDistance(p)) > 1e-12 {
+			t.Errorf("PlaneIntersectPlane: want %v on both planes, got distances %v %v\n", p, a.SignedDistance(p), b.SignedDistance(p))
+		}
+	}
+	if l.Direction.Cross(math3d.NewVec3(0, 1, 0)) != (math3d.Vec3{}) {
+		t.Errorf("PlaneIntersectPlane: want direction along y, got %v\n", l.Direction)
+	}
+	if _, ok := math3d.PlaneIntersectPlane(a, math3d.NewPlane(math3d.NewVec3(0, 0, -3), 1)); ok {
+		t.Errorf("PlaneIntersectPlane: parallel: want !ok, got ok\n")
+	}
+
+	c := math3d.PlaneFromPointNormal(math3d.Point{X: 5, Y: -1, Z: 7}, math3d.NewVec3(1, 1, 1))
+	p, ok := math3d.IntersectThreePlanes(a, b, c)
+	if want := (math3d.Point{X: 1, Y: 10, Z: 0}); !ok || !p.ApproxEqual(want, 1e-12) {
+		t.Errorf("IntersectThreePlanes: want %v, got %v %v\n", want, p, ok)
+	}
+	if _, ok := math3d.IntersectThreePlanes(a, b, math3d.NewPlane(math3d.NewVec3(0, 0, 1), 5)); ok {
+		t.Errorf("IntersectThreePlanes: parallel: want !ok, got ok\n")
+	}
+	// three planes through the same line
+	if _, ok := math3d.IntersectThreePlanes(a, b, math3d.PlaneFromPointNormal(math3d.Point{X: 1}, math3d.NewVec3(1, 0, 1))); ok {
+		t.Errorf("IntersectThreePlanes: common line: want !ok, got ok\n")
+	}
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Centroid returns the mean of the points. It returns false if there are
+// no points.
+func Centroid(points []Point) (Point, bool) {
+	if len(points) == 0 {
+		return Point{}, false
+	}
+	var sum Vec3
+	for _, p := range points {
+		sum = sum.Add(p.Vec3())
+	}
+	return sum.Div(float64(len(points))).Point(), true
+}
+
+// Covariance returns the covariance matrix of the points about their
+// centroid, dividing by the number of points. Its diagonal holds the
+// variances of the x, y, and z coordinates. It returns false if there
+// are no points.
+func Covariance(points []Point) (Mat3, bool) {
+	if len(points) == 0 {
+		return Mat3{}, false
+	}
+	_, cov := centroidCovariance(points)
+	return cov, true
+}
+
+// EstimatePose returns the rigid transform that best maps each src point
+// onto the corresponding dst point, minimizing the sum of the squared
+// distances between them; this is the problem Kabsch's algorithm solves.
+// It uses Horn's closed-form solution with unit quaternions, "Closed-form
+// solution of absolute orientation using unit quaternions" (1987), which
+// finds the rotation as an eigenvector of a 4×4 matrix rather than by a
+// singular value decomposition and can never return a reflection. It
+// returns false if the slices differ in length, there are fewer than
+// three points, or the rotation is not unique because the points are
+// collinear.
+func EstimatePose(src, dst []Point) (Pose, bool) {
+	n := len(src)
+	if n < 3 || len(dst) != n {
+		return Pose{}, false
+	}
+	cs, _ := Centroid(src)
+	cd, _ := Centroid(dst)
+	// the cross-covariance of the centered points
+	var m Mat3
+	for i := range src {
+		m = m.Add(src[i].Sub(cs).OuterProduct(dst[i].Sub(cd)))
+	}
+	sxx, sxy, sxz := m[0][0], m[0][1], m[0][2]
+	syx, syy, syz := m[1][0], m[1][1], m[1][2]
+	szx, szy, szz := m[2][0], m[2][1], m[2][2]
+	k := Matrix{
+		{sxx + syy + szz, syz - szy, szx - sxz, sxy - syx},
+		{syz - szy, sxx - syy - szz, sxy + syx, szx + sxz},
+		{szx - sxz, sxy + syx, -sxx + syy - szz, syz + szy},
+		{sxy - syx, szx + sxz, syz + szy, -sxx - syy + szz},
+	}
+	values, vectors := k.SymmetricEigen()
+	if values[3]-values[2] <= 1e-12*(math.Abs(values[3])+math.Abs(values[0])) {
+		return Pose{}, false
+	}
+	q := vectors.Col(3)
+	r := Quaternion{W: q[0], X: q[1], Y: q[2], Z: q[3]}.Normalize()
+	return Pose{Position: cd.Add(r.RotateVec3(cs.Vec3()).Mul(-1)), Orientation: r}, true
+}
+
+// FitPlane returns the plane that best fits the points while ignoring
+// outliers, using RANSAC: it tries the planes through the given number of
+// random triples of points, keeps the one with the most points within
+// threshold of it, and then refits that plane to its inliers by least
+// squares. It also returns the indices of the inliers of the result in
+// increasing order. The plane has a unit normal, whose sign is arbitrary.
+// The triples are drawn in a fixed pseudo-random order, so the result is
+// repeatable. It returns false if there are fewer than three points or
+// none of the triples tried spans a plane.
+func FitPlane(points []Point, threshold float64, iterations int) (Plane, []int, bool) {
+	if len(points) < 3 {
+		return Plane{}, nil, false
+	}
+	inliers := func(pl Plane) []int {
+		var in []int
+		for i, p := range points {
+			if math.Abs(pl.SignedDistance(p)) <= threshold {
+				in = append(in, i)
+			}
+		}
+		return in
+	}
+	rng := rand.New(rand.NewSource(1))
+	var best Plane
+	var bestIn []int
+	found := false
+	for it := 0; it < iterations; it++ {
+		// draw three distinct indices by skipping over the ones taken
+		i, j, k := rng.Intn(len(points)), rng.Intn(len(points)-1), rng.Intn(len(points)-2)
+		if j >= i {
+			j++
+		}
+		lo, hi := i, j
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if k >= lo {
+			k++
+		}
+		if k >= hi {
+			k++
+		}
+		pl, ok := PlaneFromPoints(points[i], points[j], points[k])
+		if !ok {
+			continue
+		}
+		if in := inliers(pl); !found || len(in) > len(bestIn) {
+			best, bestIn, found = pl, in, true
+		}
+	}
+	if !found {
+		return Plane{}, nil, false
+	}
+
+	// the least-squares plane of the inliers is the one through their
+	// centroid normal to the axis along which they vary least
+	fit := make([]Point, len(bestIn))
+	for n, i := range bestIn {
+		fit[n] = points[i]
+	}
+	mean, cov := centroidCovariance(fit)
+	axes, _ := principalAxes(cov)
+	refined := PlaneFromPointNormal(mean, axes.Col(2))
+	if in := inliers(refined); len(in) >= len(bestIn) {
+		return refined, in, true
+	}
+	return best, bestIn, true
+}
+
+// PrincipalAxes returns the principal axes of the points as the columns
+// of a rotation matrix, in order of decreasing variance of the points
+// along them, and the extents of the points along each axis, half the
+// distance between the furthest points on either side. The first axis is
+// the direction in which the points spread the most, and the last is the
+// normal of the plane that fits them best. When the variances along two
+// axes are equal, any pair of perpendicular axes in their plane may be
+// returned. It returns false if there are no points.
+func PrincipalAxes(points []Point) (axes Mat3, extents Vec3, ok bool) {
+	if len(points) == 0 {
+		return Mat3{}, Vec3{}, false
+	}
+	_, cov := centroidCovariance(points)
+	axes, _ = principalAxes(cov)
+	var lo, hi [3]float64
+	for i := range lo {
+		lo[i], hi[i] = math.Inf(1), math.Inf(-1)
+	}
+	for _, p := range points {
+		for i := range lo {
+			s := p.Vec3().Dot(axes.Col(i))
+			lo[i], hi[i] = math.Min(lo[i], s), math.Max(hi[i], s)
+		}
+	}
+	return axes, Vec3{X: (hi[0] - lo[0]) / 2, Y: (hi[1] - lo[1]) / 2, Z: (hi[2] - lo[2]) / 2}, true
+}
+
+// centroidCovariance returns the mean of the points and their
+// covariance matrix. There must be at least one point.
+func centroidCovariance(points []Point) (Point, Mat3) {
+	mean, _ := Centroid(points)
+	var cov Mat3
+	for _, p := range points {
+		d := p.Sub(mean)
+		cov = cov.Add(d.OuterProduct(d))
+	}
+	return mean, cov.MulScalar(1 / float64(len(points)))
+}
+
+// principalAxes returns the eigenvectors of the covariance matrix as the
+// columns of a rotation matrix, in order of decreasing eigenvalue, and
+// the eigenvalues in the same order.
+func principalAxes(cov Mat3) (Mat3, Vec3) {
+	values, vectors := Matrix{cov.Row(0).toVector(), cov.Row(1).toVector(), cov.Row(2).toVector()}.SymmetricEigen()
+	axes := Mat3FromCols(vectors.Col(2).toVec3(), vectors.Col(1).toVec3(), vectors.Col(0).toVec3())
+	if axes.Determinant() < 0 {
+		axes = Mat3FromCols(axes.Col(0), axes.Col(1), axes.Col(2).Mul(-1))
+	}
+	return axes, Vec3{X: values[2], Y: values[1], Z: values[0]}
+}
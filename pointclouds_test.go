@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPointCloudStatistics(t *testing.T) {
+	points := []math3d.Point{{X: 2, Y: 1, Z: 3}, {X: 0, Y: 1, Z: 3}, {X: 1, Y: 3, Z: 3}, {X: 1, Y: -1, Z: 3}}
+	if got, ok := math3d.Centroid(points); !ok || got != (math3d.Point{X: 1, Y: 1, Z: 3}) {
+		t.Errorf("Centroid: want (1, 1, 3), got %v %v\n", got, ok)
+	}
+	want := math3d.Mat3FromRows(math3d.NewVec3(0.5, 0, 0), math3d.NewVec3(0, 2, 0), math3d.NewVec3(0, 0, 0))
+	if got, ok := math3d.Covariance(points); !ok || got != want {
+		t.Errorf("Covariance: want %v, got %v %v\n", want, got, ok)
+	}
+	if _, ok := math3d.Centroid(nil); ok {
+		t.Errorf("Centroid: no points: want !ok, got ok\n")
+	}
+	if _, ok := math3d.Covariance(nil); ok {
+		t.Errorf("Covariance: no points: want !ok, got ok\n")
+	}
+	if _, _, ok := math3d.PrincipalAxes(nil); ok {
+		t.Errorf("PrincipalAxes: no points: want !ok, got ok\n")
+	}
+
+	// the corners of a box have its axes as their principal axes
+	o := math3d.OBB{
+		Center:      math3d.Point{X: 5, Y: -2, Z: 1},
+		HalfExtents: math3d.NewVec3(4, 2, 1),
+		Orientation: math3d.Mat3FromAxisAngle(math3d.NewVec3(1, 2, 3).NormalizeOrZero(), 0.8),
+	}
+	corners := o.Corners()
+	axes, extents, ok := math3d.PrincipalAxes(corners[:])
+	if !ok || !extents.ApproxEqual(o.HalfExtents, 1e-9) {
+		t.Errorf("PrincipalAxes: extents: want %v, got %v %v\n", o.HalfExtents, extents, ok)
+	}
+	if d := axes.Determinant(); math.Abs(d-1) > 1e-9 {
+		t.Errorf("PrincipalAxes: want a rotation, got determinant %v\n", d)
+	}
+	for i := 0; i < 3; i++ {
+		if d := math.Abs(axes.Col(i).Dot(o.Orientation.Col(i))); math.Abs(d-1) > 1e-9 {
+			t.Errorf("PrincipalAxes: axis %d: want ±%v, got %v\n", i, o.Orientation.Col(i), axes.Col(i))
+		}
+	}
+}
+
+func TestEstimatePose(t *testing.T) {
+	want := math3d.Pose{
+		Position:    math3d.Point{X: 3, Y: -1, Z: 7},
+		Orientation: math3d.QuaternionFromAxisAngle(math3d.NewVec3(2, -1, 1).NormalizeOrZero(), 2.5),
+	}
+	rng := rand.New(rand.NewSource(852))
+	var src, dst, noisy []math3d.Point
+	for i := 0; i < 50; i++ {
+		p := math3d.Point{X: rng.NormFloat64() * 4, Y: rng.NormFloat64(), Z: rng.NormFloat64() * 2}
+		src = append(src, p)
+		dst = append(dst, want.TransformPoint(p))
+		noisy = append(noisy, want.TransformPoint(p).Add(math3d.NewVec3(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()).Mul(0.01)))
+	}
+	same := func(a, b math3d.Pose, epsilon float64) bool {
+		return a.Position.ApproxEqual(b.Position, epsilon) && math.Abs(a.Orientation.Dot(b.Orientation)) > 1-epsilon
+	}
+	if got, ok := math3d.EstimatePose(src, dst); !ok || !same(got, want, 1e-9) {
+		t.Errorf("EstimatePose: want %v, got %v %v\n", want, got, ok)
+	}
+	if got, ok := math3d.EstimatePose(src, noisy); !ok || !same(got, want, 1e-2) {
+		t.Errorf("EstimatePose: noisy: want about %v, got %v %v\n", want, got, ok)
+	}
+
+	// a mirror image is matched as well as a rotation can
+	mirror := make([]math3d.Point, len(src))
+	for i, p := range src {
+		mirror[i] = math3d.Point{X: -p.X, Y: p.Y, Z: p.Z}
+	}
+	if got, ok := math3d.EstimatePose(src, mirror); !ok || math.Abs(got.Orientation.ToMat3().Determinant()-1) > 1e-9 {
+		t.Errorf("EstimatePose: mirror: want a rotation, got %v %v\n", got, ok)
+	}
+
+	line := []math3d.Point{{}, {X: 1, Y: 1, Z: 1}, {X: 2, Y: 2, Z: 2}}
+	for _, tt := range []struct {
+		name     string
+		src, dst []math3d.Point
+	}{
+		{"mismatched", src, dst[1:]},
+		{"too few", src[:2], dst[:2]},
+		{"collinear", line, line},
+	} {
+		if _, ok := math3d.EstimatePose(tt.src, tt.dst); ok {
+			t.Errorf("EstimatePose: %s: want !ok, got ok\n", tt.name)
+		}
+	}
+}
+
+func TestFitPlane(t *testing.T) {
+	want, _ := math3d.NewPlane(math3d.NewVec3(0.5, -1, -1), 3).Normalized()
+	rng := rand.New(rand.NewSource(853))
+	var points []math3d.Point
+	for i := 0; i < 200; i++ {
+		x, y := rng.Float64()*10, rng.Float64()*10
+		points = append(points, math3d.Point{X: x, Y: y, Z: 0.5*x - y + 3 + (rng.Float64()-0.5)*0.002})
+	}
+	for i := 0; i < 60; i++ {
+		points = append(points, math3d.Point{X: rng.Float64() * 10, Y: rng.Float64() * 10, Z: rng.Float64()*20 - 10})
+	}
+	pl, inliers, ok := math3d.FitPlane(points, 0.01, 100)
+	if !ok {
+		t.Fatalf("FitPlane: want ok, got !ok\n")
+	}
+	if d := math.Abs(pl.Normal.Dot(want.Normal)); d < 1-1e-6 || math.Abs(pl.SignedDistance(math3d.Point{Z: 3})) > 1e-3 {
+		t.Errorf("FitPlane: want %v, got %v\n", want, pl)
+	}
+	in := map[int]bool{}
+	for _, i := range inliers {
+		in[i] = true
+	}
+	for i := 0; i < 200; i++ {
+		if !in[i] {
+			t.Errorf("FitPlane: want point %d among the inliers, got %v\n", i, inliers)
+			break
+		}
+	}
+	for _, i := range inliers {
+		if i >= 200 && math.Abs(want.SignedDistance(points[i])) > 0.02 {
+			t.Errorf("FitPlane: want outlier %d left out, got %v\n", i, inliers)
+		}
+	}
+
+	// every triple drawn is distinct, so one try finds the only plane
+	triangle := []math3d.Point{{}, {X: 1}, {Y: 1}}
+	if pl, inliers, ok := math3d.FitPlane(triangle, 0.01, 1); !ok || len(inliers) != 3 || math.Abs(math.Abs(pl.Normal.Z)-1) > 1e-12 {
+		t.Errorf("FitPlane(%v): want the plane z = 0, got %v %v %v\n", triangle, pl, inliers, ok)
+	}
+
+	for _, points := range [][]math3d.Point{{{}, {X: 1}}, {{}, {X: 1}, {X: 2}, {X: 3}}} {
+		if _, _, ok := math3d.FitPlane(points, 0.1, 50); ok {
+			t.Errorf("FitPlane(%v): want !ok, got ok\n", points)
+		}
+	}
+}
@@ -30,6 +30,55 @@ type Point struct {
 	X, Y, Z float64
 }
 
+// Abs returns a point with the absolute value of each coordinate.
+func (p Point) Abs() Point {
+	return Point{X: math.Abs(p.X), Y: math.Abs(p.Y), Z: math.Abs(p.Z)}
+}
+
+// Add returns the point displaced by the vector v.
+func (p Point) Add(v Vec3) Point {
+	return Point{X: p.X + v.X, Y: p.Y + v.Y, Z: p.Z + v.Z}
+}
+
+// ApproxEqual reports whether each coordinate of p is within epsilon of
+// the corresponding coordinate of p2.
+func (p Point) ApproxEqual(p2 Point, epsilon float64) bool {
+	return ApproxEqual(p.X, p2.X, epsilon) && ApproxEqual(p.Y, p2.Y, epsilon) && ApproxEqual(p.Z, p2.Z, epsilon)
+}
+
+// ApproxEqualTol reports whether each coordinate of p is equal to the
+// corresponding coordinate of p2 within the absolute or relative tolerance.
+func (p Point) ApproxEqualTol(p2 Point, absTol, relTol float64) bool {
+	return ApproxEqualTol(p.X, p2.X, absTol, relTol) && ApproxEqualTol(p.Y, p2.Y, absTol, relTol) && ApproxEqualTol(p.Z, p2.Z, absTol, relTol)
+}
+
+// Ceil returns a point with each coordinate rounded up to an integer value.
+func (p Point) Ceil() Point {
+	return Point{X: math.Ceil(p.X), Y: math.Ceil(p.Y), Z: math.Ceil(p.Z)}
+}
+
+// Clamp returns a point with each coordinate limited to the
+// range given by the corresponding coordinates of lo and hi.
+func (p Point) Clamp(lo, hi Point) Point {
+	return Point{X: Clamp(p.X, lo.X, hi.X), Y: Clamp(p.Y, lo.Y, hi.Y), Z: Clamp(p.Z, lo.Z, hi.Z)}
+}
+
+// Direction returns the unit vector pointing from p towards p2.
+// It returns the zero vector if the points coincide.
+func (p Point) Direction(p2 Point) Vec3 {
+	return p2.Sub(p).NormalizeOrZero()
+}
+
+// DirectionCosines returns the direction cosines of the line from p
+// towards p2. It returns false if the points coincide.
+func (p Point) DirectionCosines(p2 Point) (DirectionCosines, bool) {
+	u, ok := p2.Sub(p).Normalized()
+	if !ok {
+		return DirectionCosines{}, false
+	}
+	return DirectionCosines{X: u.X, Y: u.Y, Z: u.Z}, true
+}
+
 // DeltaXYZ returns the changes in x, y, and z between two points.
 func (p Point) DeltaXYZ(p2 Point) (dx, dy, dz float64) {
 	return p2.X - p.X, p2.Y - p.Y, p2.Z - p.Z
@@ -41,10 +90,43 @@ func (p Point) Distance(p2 Point) float64 {
 	return math.Sqrt(dx*dx + dy*dy + dz*dz)
 }
 
+// Floor returns a point with each coordinate rounded down to an integer value.
+func (p Point) Floor() Point {
+	return Point{X: math.Floor(p.X), Y: math.Floor(p.Y), Z: math.Floor(p.Z)}
+}
+
+// HasNaN reports whether any coordinate of the point is NaN.
+func (p Point) HasNaN() bool {
+	return math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsNaN(p.Z)
+}
+
+// IsFinite reports whether every coordinate of the point is neither NaN nor infinite.
+func (p Point) IsFinite() bool {
+	return !p.HasNaN() && !math.IsInf(p.X, 0) && !math.IsInf(p.Y, 0) && !math.IsInf(p.Z, 0)
+}
+
+// Lerp returns the point at parameter t on the line from p to p2.
+// The result is p when t is 0 and p2 when t is 1.
+func (p Point) Lerp(p2 Point, t float64) Point {
+	return Point{X: Lerp(p.X, p2.X, t), Y: Lerp(p.Y, p2.Y, t), Z: Lerp(p.Z, p2.Z, t)}
+}
+
+// Max returns the component-wise maximum of the two points.
+func (p Point) Max(p2 Point) Point {
+	return Point{X: math.Max(p.X, p2.X), Y: math.Max(p.Y, p2.Y), Z: math.Max(p.Z, p2.Z)}
+}
+
+// Min returns the component-wise minimum of the two points.
+func (p Point) Min(p2 Point) Point {
+	return Point{X: math.Min(p.X, p2.X), Y: math.Min(p.Y, p2.Y), Z: math.Min(p.Z, p2.Z)}
+}
+
 // PointSlope returns a function to produce points on the line connecting two points.
 //
 //	⟨mx,my,mz⟩ = ⟨x1,y1,z1⟩ −  ⟨x0,y0,z0⟩
 //	⟨x ,y ,z ⟩ = ⟨x0,y0,z0⟩ + t⟨mx,my,mz⟩
+//
+// See LineThrough for a Line value that supports further queries.
 func (p Point) PointSlope(p2 Point) func(t float64) (tx, ty, tz float64) {
 	// https://math.stackexchange.com/questions/799783/slope-of-a-line-in-3d-coordinate-system
 	mx, my, mz := p.DeltaXYZ(p2)
@@ -53,10 +135,47 @@ func (p Point) PointSlope(p2 Point) func(t float64) (tx, ty, tz float64) {
 	}
 }
 
+// Round returns a point with each coordinate rounded to the nearest integer value.
+func (p Point) Round() Point {
+	return Point{X: math.Round(p.X), Y: math.Round(p.Y), Z: math.Round(p.Z)}
+}
+
 // Slope returns the slope (really, the direction cosines) of the line connecting two points.
+// Note that the results are the cosines for z, y, and x, in that order;
+// DirectionCosines returns them labeled.
 func (p Point) Slope(p2 Point) (xy, xz, yz float64) {
 	// https://math.stackexchange.com/questions/799783/slope-of-a-line-in-3d-coordinate-system
 	dx, dy, dz := p.DeltaXYZ(p2)
 	d := p.Distance(p2)
 	return dz / d, dy / d, dx / d
 }
+
+// Sub returns the displacement vector from p2 to p.
+// The difference of two points is a direction, not a point.
+func (p Point) Sub(p2 Point) Vec3 {
+	return Vec3{X: p.X - p2.X, Y: p.Y - p2.Y, Z: p.Z - p2.Z}
+}
+
+// Vec3 returns the position vector of the point, the displacement
+// from the origin to p.
+func (p Point) Vec3() Vec3 {
+	return Vec3{X: p.X, Y: p.Y, Z: p.Z}
+}
+
+// DirectionCosines holds the cosines of the angles between a direction
+// and the positive x, y, and z axes. They are the components of the
+// unit vector in that direction, so their squares sum to 1.
+type DirectionCosines struct {
+	X, Y, Z float64
+}
+
+// Angles returns the direction angles α, β, and γ in radians, the
+// angles between the direction and the positive x, y, and z axes.
+func (dc DirectionCosines) Angles() (alpha, beta, gamma float64) {
+	return math.Acos(Clamp(dc.X, -1, 1)), math.Acos(Clamp(dc.Y, -1, 1)), math.Acos(Clamp(dc.Z, -1, 1))
+}
+
+// Vec3 returns the unit vector with the direction cosines as components.
+func (dc DirectionCosines) Vec3() Vec3 {
+	return Vec3{X: dc.X, Y: dc.Y, Z: dc.Z}
+}
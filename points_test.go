@@ -25,6 +25,7 @@ package math3d_test
 
 import (
 	"github.com/maloquacious/math3d"
+	"math"
 	"testing"
 )
 
@@ -57,3 +58,79 @@ func TestPoints(t *testing.T) {
 		}
 	}
 }
+
+func TestLerp(t *testing.T) {
+	for _, tt := range []struct {
+		a, b, t, expect float64
+	}{
+		{0, 10, 0, 0},
+		{0, 10, 1, 10},
+		{0, 10, 0.25, 2.5},
+		{-5, 5, 0.5, 0},
+		{0, 10, 2, 20},
+	} {
+		if v := math3d.Lerp(tt.a, tt.b, tt.t); v != tt.expect {
+			t.Errorf("Lerp: want %f, got %f\n", tt.expect, v)
+		}
+		if tt.t >= 0 && tt.t <= 1 {
+			if v := math3d.InverseLerp(tt.a, tt.b, tt.expect); v != tt.t {
+				t.Errorf("InverseLerp: want %f, got %f\n", tt.t, v)
+			}
+		}
+	}
+	if v := math3d.Remap(5, 0, 10, 100, 200); v != 150 {
+		t.Errorf("Remap: want %f, got %f\n", 150.0, v)
+	}
+
+	p1, p2 := math3d.Point{X: 0, Y: 0, Z: 0}, math3d.Point{X: 2, Y: 4, Z: 6}
+	if p := p1.Lerp(p2, 0.5); p != (math3d.Point{X: 1, Y: 2, Z: 3}) {
+		t.Errorf("Point.Lerp: want %v, got %v\n", math3d.Point{X: 1, Y: 2, Z: 3}, p)
+	}
+	if v := math3d.NewVec4(0, 0, 0, 0).Lerp(math3d.NewVec4(4, 8, 12, 16), 0.25); v != math3d.NewVec4(1, 2, 3, 4) {
+		t.Errorf("Vec4.Lerp: want %v, got %v\n", math3d.NewVec4(1, 2, 3, 4), v)
+	}
+}
+
+func TestPointComponentWise(t *testing.T) {
+	p, p2 := math3d.Point{X: -1.5, Y: 2, Z: 3.5}, math3d.Point{X: 1, Y: -2, Z: 4}
+	if m := p.Min(p2); m != (math3d.Point{X: -1.5, Y: -2, Z: 3.5}) {
+		t.Errorf("Min: want %v, got %v\n", math3d.Point{X: -1.5, Y: -2, Z: 3.5}, m)
+	}
+	if m := p.Max(p2); m != (math3d.Point{X: 1, Y: 2, Z: 4}) {
+		t.Errorf("Max: want %v, got %v\n", math3d.Point{X: 1, Y: 2, Z: 4}, m)
+	}
+	if f := p.Floor(); f != (math3d.Point{X: -2, Y: 2, Z: 3}) {
+		t.Errorf("Floor: want %v, got %v\n", math3d.Point{X: -2, Y: 2, Z: 3}, f)
+	}
+}
+
+func TestPointDirectionCosines(t *testing.T) {
+	p, p2 := math3d.Point{X: 1, Y: 1, Z: 1}, math3d.Point{X: 3, Y: -2, Z: 7}
+	dc, ok := p.DirectionCosines(p2)
+	if !ok {
+		t.Fatalf("DirectionCosines: want ok, got !ok\n")
+	}
+	if want := (math3d.DirectionCosines{X: 2.0 / 7, Y: -3.0 / 7, Z: 6.0 / 7}); math.Abs(dc.X-want.X) > 1e-15 || math.Abs(dc.Y-want.Y) > 1e-15 || math.Abs(dc.Z-want.Z) > 1e-15 {
+		t.Errorf("DirectionCosines: want %v, got %v\n", want, dc)
+	}
+	if xy, xz, yz := p.Slope(p2); xy != dc.Z || xz != dc.Y || yz != dc.X {
+		t.Errorf("Slope: want %v, got %v %v %v\n", dc, xy, xz, yz)
+	}
+	if d := p.Direction(p2); !d.ApproxEqual(dc.Vec3(), 1e-15) {
+		t.Errorf("Direction: want %v, got %v\n", dc.Vec3(), d)
+	}
+	alpha, beta, gamma := dc.Angles()
+	if s := math.Cos(alpha)*math.Cos(alpha) + math.Cos(beta)*math.Cos(beta) + math.Cos(gamma)*math.Cos(gamma); math.Abs(s-1) > 1e-12 {
+		t.Errorf("Angles: want cos²α + cos²β + cos²γ = 1, got %v\n", s)
+	}
+	if beta <= math.Pi/2 {
+		t.Errorf("Angles: want β > π/2 for a negative y cosine, got %v\n", beta)
+	}
+
+	if _, ok := p.DirectionCosines(p); ok {
+		t.Errorf("DirectionCosines: coincident: want !ok, got ok\n")
+	}
+	if d := p.Direction(p); d != (math3d.Vec3{}) {
+		t.Errorf("Direction: coincident: want %v, got %v\n", math3d.Vec3{}, d)
+	}
+}
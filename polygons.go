@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Polygon is a closed polygon with its vertices in order. The vertices
+// are assumed to lie in a plane; the last vertex joins back to the first.
+type Polygon []Point
+
+// Triangulate splits a simple polygon, which may be concave but must not
+// cross itself, into triangles by ear clipping. Each triangle is given by
+// the indices of its corners in the polygon and keeps the polygon's
+// winding, so it faces the same way. A polygon with n vertices gives n−2
+// triangles. It takes time proportional to n² in the worst case.
+// It returns false if the polygon has no area or no ear can be found,
+// as happens when it crosses itself.
+func Triangulate(poly Polygon) ([][3]int, bool) {
+	proj, _, ok := poly.projection()
+	if !ok {
+		return nil, false
+	}
+	uv := make([][2]float64, len(poly))
+	for i, p := range poly {
+		uv[i][0], uv[i][1] = proj(p)
+	}
+	// the orientation of the polygon in the projection
+	var area float64
+	for i, a := range uv {
+		b := uv[(i+1)%len(uv)]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	sign := math.Copysign(1, area)
+	cross := func(a, b, c int) float64 {
+		return sign * ((uv[b][0]-uv[a][0])*(uv[c][1]-uv[a][1]) - (uv[b][1]-uv[a][1])*(uv[c][0]-uv[a][0]))
+	}
+
+	remaining := make([]int, len(poly))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	tris := make([][3]int, 0, len(poly)-2)
+	for len(remaining) > 3 {
+		ear := -1
+		for i := range remaining {
+			a, b, c := remaining[(i+len(remaining)-1)%len(remaining)], remaining[i], remaining[(i+1)%len(remaining)]
+			if cross(a, b, c) <= 0 {
+				continue // reflex or flat
+			}
+			// no other vertex may be inside the ear or on its edges
+			empty := true
+			for _, p := range remaining {
+				if p == a || p == b || p == c || uv[p] == uv[a] || uv[p] == uv[b] || uv[p] == uv[c] {
+					continue
+				}
+				if cross(a, b, p) >= 0 && cross(b, c, p) >= 0 && cross(c, a, p) >= 0 {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			return nil, false
+		}
+		tris = append(tris, [3]int{remaining[(ear+len(remaining)-1)%len(remaining)], remaining[ear], remaining[(ear+1)%len(remaining)]})
+		remaining = append(remaining[:ear], remaining[ear+1:]...)
+	}
+	return append(tris, [3]int{remaining[0], remaining[1], remaining[2]}), true
+}
+
+// Area returns the area of the polygon, found by Newell's method.
+// For a self-intersecting polygon, regions wound in opposite directions
+// cancel.
+func (poly Polygon) Area() float64 {
+	return poly.newellNormal().Length() / 2
+}
+
+// Centroid returns the center of mass of the area of the polygon. It
+// splits the polygon into a fan of triangles from the first vertex and
+// averages their centroids weighted by their signed areas, which works
+// for nonconvex polygons. It returns false if the polygon has no area.
+func (poly Polygon) Centroid() (Point, bool) {
+	n := poly.newellNormal()
+	if len(poly) < 3 || n == (Vec3{}) {
+		return Point{}, false
+	}
+	var sum Vec3
+	var total float64
+	a := poly[0]
+	for i := 1; i+1 < len(poly); i++ {
+		b, c := poly[i], poly[i+1]
+		w := n.Dot(b.Sub(a).Cross(c.Sub(a)))
+		sum = sum.Add(a.Vec3().Add(b.Vec3()).Add(c.Vec3()).Mul(w))
+		total += w
+	}
+	if total == 0 {
+		return Point{}, false
+	}
+	centroid := sum.Div(3 * total)
+	return centroid.Point(), centroid.IsFinite()
+}
+
+// ClipByFrustum returns the part of the polygon inside the frustum,
+// clipping it by each plane in turn as in ClipByPlane.
+func (poly Polygon) ClipByFrustum(f Frustum) Polygon {
+	for _, pl := range f.Planes {
+		poly = poly.ClipByPlane(pl)
+	}
+	return poly
+}
+
+// ClipByPlane returns the part of the polygon on the side of the plane
+// that its normal points to, using the Sutherland–Hodgman algorithm.
+// Vertices on the plane are kept. The result is nil if no part of the
+// polygon is on that side. Clipping a convex polygon gives a convex
+// polygon; clipping a concave one may give edges that overlap along the
+// plane.
+func (poly Polygon) ClipByPlane(pl Plane) Polygon {
+	var out Polygon
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		da, db := pl.SignedDistance(a), pl.SignedDistance(b)
+		if da >= 0 {
+			out = append(out, a)
+		}
+		if (da < 0 && db > 0) || (da > 0 && db < 0) {
+			out = append(out, a.Lerp(b, da/(da-db)))
+		}
+	}
+	return out
+}
+
+// ContainsPoint reports whether the projection of p onto the plane of
+// the polygon lies inside it, using the nonzero winding rule so that
+// regions a self-intersecting polygon wraps around count as inside.
+// The test is done in the coordinate plane that the polygon faces most
+// directly. Points on an edge may go either way.
+// A polygon with fewer than three vertices or no area contains no points.
+func (poly Polygon) ContainsPoint(p Point) bool {
+	proj, n, ok := poly.projection()
+	if !ok {
+		return false
+	}
+
+	// project p along the normal first; dropping a coordinate alone would
+	// project it obliquely
+	p = p.Add(n.Mul(-n.Dot(p.Sub(poly[0])) / n.LengthSquared()))
+
+	// Sunday's winding number: count the signed crossings of the edges
+	// with the ray from p in the +u direction
+	pu, pv := proj(p)
+	winding := 0
+	for i := range poly {
+		au, av := proj(poly[i])
+		bu, bv := proj(poly[(i+1)%len(poly)])
+		// which side of the edge p is on
+		side := (bu-au)*(pv-av) - (pu-au)*(bv-av)
+		if av <= pv {
+			if bv > pv && side > 0 {
+				winding++ // an upward crossing with p to the left
+			}
+		} else if bv <= pv && side < 0 {
+			winding-- // a downward crossing with p to the right
+		}
+	}
+	return winding != 0
+}
+
+// Normal returns the unit normal of the polygon, found by Newell's
+// method. The vertices run counter-clockwise when viewed from the side
+// it points to. It returns the zero vector if the polygon has no area.
+func (poly Polygon) Normal() Vec3 {
+	return poly.newellNormal().NormalizeOrZero()
+}
+
+// projection returns a function that maps points to the coordinate
+// plane the polygon faces most directly by dropping the coordinate in
+// which its normal is largest, along with the normal. It returns false
+// if the polygon has no area.
+func (poly Polygon) projection() (func(Point) (float64, float64), Vec3, bool) {
+	if len(poly) < 3 {
+		return nil, Vec3{}, false
+	}
+	n := poly.newellNormal()
+	ax, ay, az := math.Abs(n.X), math.Abs(n.Y), math.Abs(n.Z)
+	switch {
+	case ax == 0 && ay == 0 && az == 0:
+		return nil, n, false
+	case ay >= ax && ay >= az:
+		return func(q Point) (float64, float64) { return q.Z, q.X }, n, true
+	case az >= ax && az >= ay:
+		return func(q Point) (float64, float64) { return q.X, q.Y }, n, true
+	}
+	return func(q Point) (float64, float64) { return q.Y, q.Z }, n, true
+}
+
+// newellNormal returns the normal of the polygon found by Newell's
+// method, whose length is twice the area of the polygon. It is robust
+// for nonplanar and nonconvex polygons.
+func (poly Polygon) newellNormal() Vec3 {
+	var n Vec3
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		n.X += (a.Y - b.Y) * (a.Z + b.Z)
+		n.Y += (a.Z - b.Z) * (a.X + b.X)
+		n.Z += (a.X - b.X) * (a.Y + b.Y)
+	}
+	return n
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestPolygonContainsPoint(t *testing.T) {
+	// an L shape in the xy-plane, turned so it faces mostly along y
+	lshape := []math3d.Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 0, Y: 2}}
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(5, -3, 1)).Mul(math3d.Mat4FromRotationX(-1.2))
+	var poly math3d.Polygon
+	for _, p := range lshape {
+		poly = append(poly, m.TransformPoint(p))
+	}
+	for _, tt := range []struct {
+		p    math3d.Point
+		want bool
+	}{
+		{math3d.Point{X: 0.5, Y: 0.5}, true},
+		{math3d.Point{X: 1.5, Y: 0.5}, true},
+		{math3d.Point{X: 0.5, Y: 1.5}, true},
+		{math3d.Point{X: 1.5, Y: 1.5}, false}, // in the notch
+		{math3d.Point{X: 2.5, Y: 0.5}, false},
+		{math3d.Point{X: 0.5, Y: 0.5, Z: 3}, true}, // projected onto the plane
+	} {
+		if got := poly.ContainsPoint(m.TransformPoint(tt.p)); got != tt.want {
+			t.Errorf("ContainsPoint(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+	}
+
+	// the center of a pentagram is wound around twice
+	var star math3d.Polygon
+	for i := 0; i < 5; i++ {
+		a := float64(i) * 4 * math.Pi / 5
+		star = append(star, math3d.Point{X: math.Cos(a), Z: math.Sin(a)})
+	}
+	if !star.ContainsPoint(math3d.Point{}) || !star.ContainsPoint(math3d.Point{X: 0.5}) || star.ContainsPoint(math3d.Point{X: 0.9, Z: 0.3}) {
+		t.Errorf("ContainsPoint: pentagram: want center and point in, notch out\n")
+	}
+
+	if (math3d.Polygon{{}, {X: 1}, {X: 2}}).ContainsPoint(math3d.Point{X: 1}) {
+		t.Errorf("ContainsPoint: degenerate: want false, got true\n")
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	// the L shape from TestPolygonContainsPoint, standing in the xz-plane
+	poly := math3d.Polygon{{X: 0, Z: 0}, {X: 0, Z: 2}, {X: 1, Z: 2}, {X: 1, Z: 1}, {X: 2, Z: 1}, {X: 2, Z: 0}}
+	if got := poly.Area(); math.Abs(got-3) > 1e-15 {
+		t.Errorf("Area: want 3, got %v\n", got)
+	}
+	if got, want := poly.Normal(), math3d.NewVec3(0, 1, 0); !got.ApproxEqual(want, 1e-15) {
+		t.Errorf("Normal: want %v, got %v\n", want, got)
+	}
+	// two unit squares and one more, centered at (0.5, 1.5), (0.5, 0.5), (1.5, 0.5)
+	want := math3d.Point{X: 5.0 / 6, Z: 5.0 / 6}
+	if got, ok := poly.Centroid(); !ok || !got.ApproxEqual(want, 1e-15) {
+		t.Errorf("Centroid: want %v, got %v %v\n", want, got, ok)
+	}
+
+	// moving the shape moves the centroid with it
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(1, 2, 3)).Mul(math3d.Mat4FromRotationZ(0.7))
+	var moved math3d.Polygon
+	for _, p := range poly {
+		moved = append(moved, m.TransformPoint(p))
+	}
+	got, _ := moved.Centroid()
+	if want := m.TransformPoint(want); !got.ApproxEqual(want, 1e-12) || math.Abs(moved.Area()-3) > 1e-12 {
+		t.Errorf("Centroid: moved: want %v, got %v\n", want, got)
+	}
+
+	if _, ok := (math3d.Polygon{{}, {X: 1}, {X: 2}}).Centroid(); ok {
+		t.Errorf("Centroid: degenerate: want !ok, got ok\n")
+	}
+}
+
+func TestPolygonClip(t *testing.T) {
+	square := math3d.Polygon{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}
+	for _, tt := range []struct {
+		name string
+		pl   math3d.Plane
+		want math3d.Polygon
+	}{
+		// keep x + y ≤ 2, the lower left half
+		{"diagonal", math3d.NewPlane(math3d.NewVec3(-1, -1, 0), 2), math3d.Polygon{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 2}}},
+		{"x ≥ 1", math3d.NewPlane(math3d.NewVec3(1, 0, 0), -1), math3d.Polygon{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 1, Y: 2}}},
+		{"all", math3d.NewPlane(math3d.NewVec3(0, 0, 1), 1), square},
+		{"none", math3d.NewPlane(math3d.NewVec3(1, 0, 0), -3), nil},
+	} {
+		got := square.ClipByPlane(tt.pl)
+		if len(got) != len(tt.want) {
+			t.Errorf("ClipByPlane: %s: want %v, got %v\n", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if !got[i].ApproxEqual(tt.want[i], 1e-15) {
+				t.Errorf("ClipByPlane: %s: want %v, got %v\n", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+
+	// a 90° frustum is 20 units wide at 10 units from the eye
+	f := math3d.FrustumFromMatrix(math3d.Perspective(math.Pi/2, 1, 1, 100), math3d.ClipSpace{})
+	big := math3d.Polygon{{X: -50, Y: -50, Z: -10}, {X: 50, Y: -50, Z: -10}, {X: 50, Y: 50, Z: -10}, {X: -50, Y: 50, Z: -10}}
+	clipped := big.ClipByFrustum(f)
+	if got := clipped.Area(); len(clipped) != 4 || math.Abs(got-400) > 1e-9 {
+		t.Errorf("ClipByFrustum: want area 400 with 4 vertices, got %v %v\n", got, clipped)
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	// a comb with three teeth, wound clockwise when seen from +z
+	comb := math3d.Polygon{{X: 0, Y: 0}, {X: 0, Y: 3}, {X: 1, Y: 3}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 4, Y: 3}, {X: 5, Y: 3}, {X: 5, Y: 0}}
+	for _, tt := range []struct {
+		name string
+		poly math3d.Polygon
+	}{
+		{"comb", comb},
+		{"tilted", func() math3d.Polygon {
+			m := math3d.Mat4FromRotationAround(math3d.Point{X: 1}, math3d.NewVec3(1, 2, 3), 2)
+			var poly math3d.Polygon
+			for _, p := range comb {
+				poly = append(poly, m.TransformPoint(p))
+			}
+			return poly
+		}()},
+		{"collinear", math3d.Polygon{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}},
+	} {
+		tris, ok := math3d.Triangulate(tt.poly)
+		if !ok || len(tris) != len(tt.poly)-2 {
+			t.Errorf("Triangulate: %s: want %d triangles, got %v %v\n", tt.name, len(tt.poly)-2, tris, ok)
+			continue
+		}
+		// the triangles cover the polygon and face the same way
+		var area float64
+		n := tt.poly.Normal()
+		for _, tri := range tris {
+			tr := math3d.Triangle{A: tt.poly[tri[0]], B: tt.poly[tri[1]], C: tt.poly[tri[2]]}
+			if tr.Area() > 1e-12 && tr.Normal().Dot(n) < 0.999 {
+				t.Errorf("Triangulate: %s: triangle %v faces %v, want %v\n", tt.name, tri, tr.Normal(), n)
+			}
+			area += tr.Area()
+		}
+		if want := tt.poly.Area(); math.Abs(area-want) > 1e-12 {
+			t.Errorf("Triangulate: %s: want area %v, got %v\n", tt.name, want, area)
+		}
+	}
+
+	bowtie := math3d.Polygon{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 2, Y: 0}, {X: 0, Y: 2}}
+	if _, ok := math3d.Triangulate(bowtie); ok {
+		t.Errorf("Triangulate: bowtie: want !ok, got ok\n")
+	}
+}
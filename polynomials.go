@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// solveQuadratic returns the real roots of ax² + bx + c = 0 in
+// increasing order. A repeated root is returned twice. It avoids the
+// cancellation in the textbook formula by computing the larger root
+// first and finding the other from their product. When a is zero the
+// equation is solved as linear.
+func solveQuadratic(a, b, c float64) []float64 {
+	if a == 0 {
+		if b == 0 {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return nil
+	}
+	q := -(b + math.Copysign(math.Sqrt(disc), b)) / 2
+	if q == 0 {
+		// b and c are both zero
+		return []float64{0, 0}
+	}
+	x0, x1 := q/a, c/q
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	return []float64{x0, x1}
+}
+
+// solvePolynomial returns the real roots of the polynomial with the
+// coefficients c, lowest degree first, in increasing order. The roots of
+// the derivative split the line into intervals on which the polynomial
+// is monotonic, and a root is found in each interval whose ends differ
+// in sign by Newton's method safeguarded with bisection. This is slower
+// than the closed-form solutions for cubics and quartics but does not
+// lose roots to cancellation. Roots of even multiplicity, where the
+// polynomial touches zero without crossing it, are only found if it
+// evaluates to exactly zero there.
+func solvePolynomial(c []float64) []float64 {
+	n := len(c) - 1
+	for n >= 0 && c[n] == 0 {
+		n--
+	}
+	c = c[:n+1]
+	if n <= 2 {
+		for len(c) < 3 {
+			c = append(c, 0)
+		}
+		return solveQuadratic(c[2], c[1], c[0])
+	}
+
+	deriv := make([]float64, n)
+	for i := 1; i <= n; i++ {
+		deriv[i-1] = float64(i) * c[i]
+	}
+	// every root lies within Cauchy's bound
+	bound := 0.0
+	for _, ci := range c[:n] {
+		bound = math.Max(bound, math.Abs(ci/c[n]))
+	}
+	bound++
+	ends := []float64{-bound}
+	for _, x := range solvePolynomial(deriv) {
+		if x > ends[len(ends)-1] && x < bound {
+			ends = append(ends, x)
+		}
+	}
+	ends = append(ends, bound)
+
+	var roots []float64
+	for i := 0; i+1 < len(ends); i++ {
+		lo, hi := ends[i], ends[i+1]
+		flo, _ := evalPolynomial(c, lo)
+		fhi, _ := evalPolynomial(c, hi)
+		switch {
+		case flo == 0:
+			if len(roots) == 0 || roots[len(roots)-1] != lo {
+				roots = append(roots, lo)
+			}
+		case fhi == 0:
+			// found as the low end of the next interval, or below
+			if i+2 == len(ends) {
+				roots = append(roots, hi)
+			}
+		case (flo < 0) != (fhi < 0):
+			roots = append(roots, refineRoot(c, lo, hi, flo))
+		}
+	}
+	return roots
+}
+
+// evalPolynomial returns the value and the derivative at x of the
+// polynomial with the coefficients c, lowest degree first.
+func evalPolynomial(c []float64, x float64) (f, df float64) {
+	for i := len(c) - 1; i >= 0; i-- {
+		df = df*x + f
+		f = f*x + c[i]
+	}
+	return f, df
+}
+
+// refineRoot returns the root of the polynomial with the coefficients c
+// in the interval [lo, hi], where it changes sign once and has the
+// value flo at lo.
+func refineRoot(c []float64, lo, hi, flo float64) float64 {
+	x := (lo + hi) / 2
+	for i := 0; i < 100; i++ {
+		f, df := evalPolynomial(c, x)
+		if f == 0 {
+			return x
+		}
+		if (f < 0) == (flo < 0) {
+			lo = x
+		} else {
+			hi = x
+		}
+		next := (lo + hi) / 2
+		if df != 0 {
+			if newton := x - f/df; newton > lo && newton < hi {
+				next = newton
+			}
+		}
+		if math.Abs(next-x) <= 1e-15*math.Max(1, math.Abs(x)) {
+			return next
+		}
+		x = next
+	}
+	return x
+}
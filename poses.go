@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Pose implements a rigid transform as a position and an orientation.
+// The transform rotates by Orientation and then translates to Position,
+// so Position is where the pose places the origin of its local frame.
+// The orientation should be a unit quaternion.
+type Pose struct {
+	Position    Point
+	Orientation Quaternion
+}
+
+// IdentityPose returns the pose at the origin with no rotation.
+func IdentityPose() Pose {
+	return Pose{Orientation: IdentityQuaternion()}
+}
+
+// ApproxEqual reports whether the positions and the orientations of p and
+// q are within epsilon component-wise. Note that a quaternion and its
+// negation represent the same rotation but are not approximately equal.
+func (p Pose) ApproxEqual(q Pose, epsilon float64) bool {
+	return p.Position.ApproxEqual(q.Position, epsilon) && p.Orientation.ApproxEqual(q.Orientation, epsilon)
+}
+
+// Affine returns the transform for p.
+func (p Pose) Affine() Affine {
+	return AffineFromLinear(p.Orientation.ToMat3(), p.Position.Vec3())
+}
+
+// Compose returns the pose that applies q and then p. If q is a pose
+// relative to the frame of p, the result is that pose in the frame p is
+// relative to.
+func (p Pose) Compose(q Pose) Pose {
+	return Pose{
+		Position:    p.TransformPoint(q.Position),
+		Orientation: p.Orientation.Mul(q.Orientation),
+	}
+}
+
+// Inverse returns the pose that undoes p.
+func (p Pose) Inverse() Pose {
+	r := p.Orientation.Conjugate()
+	return Pose{
+		Position:    r.RotateVec3(p.Position.Vec3()).Mul(-1).Point(),
+		Orientation: r,
+	}
+}
+
+// Mat4 returns the transform for p as a 4×4 matrix.
+func (p Pose) Mat4() Mat4 {
+	return p.Affine().Mat4()
+}
+
+// RotateAround returns p rotated counter-clockwise by angle radians about
+// the given axis through point. Both the position and the orientation
+// turn, as for a camera orbiting a target or a body on a hinge.
+func (p Pose) RotateAround(point Point, axis Vec3, angle float64) Pose {
+	r := QuaternionFromAxisAngle(axis, angle)
+	return Pose{
+		Position:    point.Add(r.RotateVec3(p.Position.Sub(point))),
+		Orientation: r.Mul(p.Orientation).Normalize(),
+	}
+}
+
+// TransformDirection returns v rotated by p.
+// Directions are not affected by the position.
+func (p Pose) TransformDirection(v Vec3) Vec3 {
+	return p.Orientation.RotateVec3(v)
+}
+
+// TransformPoint returns pt rotated and then translated by p.
+func (p Pose) TransformPoint(pt Point) Point {
+	return p.Position.Add(p.Orientation.RotateVec3(pt.Vec3()))
+}
+
+// ScrewLerp returns the pose at parameter t on the screw motion from a
+// to b, which rotates about a fixed axis and translates along it at
+// constant rates. Unlike interpolating the position and orientation
+// separately, every point of the body follows a helix, as a rigid body
+// coasting between the two states would. The result is a when t is 0
+// and b, possibly with a negated orientation, when t is 1.
+func ScrewLerp(a, b Pose, t float64) Pose {
+	tw := LogSE3(a.Inverse().Compose(b).Affine())
+	step := ExpSE3(tw.Mul(t))
+	return a.Compose(Pose{
+		Position:    step.Translation().Point(),
+		Orientation: QuaternionFromRotationVector(tw.Omega.Mul(t)),
+	})
+}
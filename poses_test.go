@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestPose(t *testing.T) {
+	p := math3d.Pose{
+		Position:    math3d.Point{X: 1, Y: 2, Z: 3},
+		Orientation: math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 0, 1), 0.8),
+	}
+	q := math3d.Pose{
+		Position:    math3d.Point{X: -2, Y: 0, Z: 5},
+		Orientation: math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 1, 0), -1.1),
+	}
+	pt := math3d.Point{X: 0.5, Y: -1, Z: 4}
+
+	if want, got := p.Affine().TransformPoint(pt), p.TransformPoint(pt); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("TransformPoint: want %v, got %v\n", want, got)
+	}
+	v := math3d.NewVec3(1, 1, 1)
+	if want, got := p.Affine().TransformDirection(v), p.TransformDirection(v); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("TransformDirection: want %v, got %v\n", want, got)
+	}
+	if want, got := p.Affine().Mul(q.Affine()), p.Compose(q).Affine(); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("Compose: want %v, got %v\n", want, got)
+	}
+	if got := p.Compose(p.Inverse()); !got.ApproxEqual(math3d.IdentityPose(), 1e-12) {
+		t.Errorf("Inverse: want identity, got %v\n", got)
+	}
+	if got := p.Inverse().TransformPoint(p.TransformPoint(pt)); !got.ApproxEqual(pt, 1e-12) {
+		t.Errorf("Inverse: want %v, got %v\n", pt, got)
+	}
+	if want, got := p.Affine().Mat4(), p.Mat4(); !got.ApproxEqual(want, 0) {
+		t.Errorf("Mat4: want %v, got %v\n", want, got)
+	}
+}
+
+func TestScrewLerp(t *testing.T) {
+	// a quarter turn about the vertical line through (1, 0, 0)
+	pivot := math3d.Point{X: 1}
+	rotate := func(angle float64) math3d.Pose {
+		q := math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 0, 1), angle)
+		return math3d.Pose{Position: pivot.Add(q.RotateVec3(math3d.Point{}.Sub(pivot))), Orientation: q}
+	}
+	a, b := math3d.IdentityPose(), rotate(math.Pi/2)
+	if got := math3d.ScrewLerp(a, b, 0); !got.ApproxEqual(a, 1e-12) {
+		t.Errorf("ScrewLerp: t = 0: want %v, got %v\n", a, got)
+	}
+	if got := math3d.ScrewLerp(a, b, 1); !got.ApproxEqual(b, 1e-12) {
+		t.Errorf("ScrewLerp: t = 1: want %v, got %v\n", b, got)
+	}
+	if want, got := rotate(math.Pi/8), math3d.ScrewLerp(a, b, 0.25); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("ScrewLerp: t = 0.25: want %v, got %v\n", want, got)
+	}
+
+	// the result is independent of the frame both poses are expressed in
+	c := math3d.Pose{Position: math3d.Point{X: 3, Y: -1, Z: 2}, Orientation: math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 2, 0), 0.6)}
+	want := c.Compose(math3d.ScrewLerp(a, b, 0.6))
+	if got := math3d.ScrewLerp(c.Compose(a), c.Compose(b), 0.6); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("ScrewLerp: in frame: want %v, got %v\n", want, got)
+	}
+}
+
+func TestPoseRotateAround(t *testing.T) {
+	p := math3d.Pose{
+		Position:    math3d.Point{X: 5, Y: 0, Z: 1},
+		Orientation: math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 1, 0), 0.3),
+	}
+	point, axis, angle := math3d.Point{X: 1, Y: 1}, math3d.NewVec3(0, 0, 1), math.Pi/2
+	got := p.RotateAround(point, axis, angle)
+	want := math3d.Mat4FromRotationAround(point, axis, angle).Mul(p.Mat4())
+	if !got.Mat4().ApproxEqual(want, 1e-12) {
+		t.Errorf("RotateAround: want %v, got %v\n", want, got.Mat4())
+	}
+	if w := (math3d.Point{X: 2, Y: 5, Z: 1}); !got.Position.ApproxEqual(w, 1e-12) {
+		t.Errorf("RotateAround: position: want %v, got %v\n", w, got.Position)
+	}
+}
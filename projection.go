@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// The view and projection builders follow the OpenGL conventions:
+// a right-handed view space with the camera looking down the negative
+// z-axis, and a clip space where visible depths map to [−1, 1].
+
+// DepthRange is the range of clip-space depths that are visible.
+type DepthRange int
+
+const (
+	// DepthNegOneToOne maps depths to [−1, 1], as in OpenGL.
+	DepthNegOneToOne DepthRange = iota
+	// DepthZeroToOne maps depths to [0, 1], as in Direct3D, Metal, and Vulkan.
+	DepthZeroToOne
+)
+
+// ClipSpace selects how projections map view-space depth to clip space.
+// The zero value is the OpenGL convention. When Reversed is set, the near
+// plane maps to the largest depth and the far plane to the smallest,
+// which spreads floating-point depth precision evenly over the scene.
+type ClipSpace struct {
+	Depth    DepthRange
+	Reversed bool
+}
+
+// depths returns the normalized device depths of the near and far planes.
+func (c ClipSpace) depths() (near, far float64) {
+	near, far = -1, 1
+	if c.Depth == DepthZeroToOne {
+		near = 0
+	}
+	if c.Reversed {
+		return far, near
+	}
+	return near, far
+}
+
+// LookAt returns the view transform for a camera at eye looking
+// towards center, with up giving the approximate upward direction.
+// The up vector must not be parallel to the viewing direction.
+func LookAt(eye, center Point, up Vec3) Mat4 {
+	f := center.Sub(eye).Normalize()
+	s := f.Cross(up).Normalize()
+	u := s.Cross(f)
+	e := eye.Vec3()
+	return Mat4{
+		{s.X, s.Y, s.Z, -s.Dot(e)},
+		{u.X, u.Y, u.Z, -u.Dot(e)},
+		{-f.X, -f.Y, -f.Z, f.Dot(e)},
+		{0, 0, 0, 1},
+	}
+}
+
+// Ortho returns an orthographic projection of the box bounded by the
+// left, right, bottom, and top planes and the near and far distances.
+func Ortho(left, right, bottom, top, near, far float64) Mat4 {
+	rl, tb, fn := right-left, top-bottom, far-near
+	return Mat4{
+		{2 / rl, 0, 0, -(right + left) / rl},
+		{0, 2 / tb, 0, -(top + bottom) / tb},
+		{0, 0, -2 / fn, -(far + near) / fn},
+		{0, 0, 0, 1},
+	}
+}
+
+// Perspective returns a symmetric perspective projection with the given
+// vertical field of view in radians, aspect ratio (width over height),
+// and positive near and far distances.
+func Perspective(fovy, aspect, near, far float64) Mat4 {
+	f := 1 / math.Tan(fovy/2)
+	nf := near - far
+	return Mat4{
+		{f / aspect, 0, 0, 0},
+		{0, f, 0, 0},
+		{0, 0, (far + near) / nf, 2 * far * near / nf},
+		{0, 0, -1, 0},
+	}
+}
+
+// PerspectiveFrustum returns a perspective projection of the view
+// frustum whose near plane is bounded by left, right, bottom, and top.
+// It allows off-center projections such as those used for stereo.
+func PerspectiveFrustum(left, right, bottom, top, near, far float64) Mat4 {
+	rl, tb, fn := right-left, top-bottom, far-near
+	return Mat4{
+		{2 * near / rl, 0, (right + left) / rl, 0},
+		{0, 2 * near / tb, (top + bottom) / tb, 0},
+		{0, 0, -(far + near) / fn, -2 * far * near / fn},
+		{0, 0, -1, 0},
+	}
+}
+
+// OrthoClip returns an orthographic projection like Ortho that maps
+// depths using the given clip-space convention.
+func OrthoClip(left, right, bottom, top, near, far float64, clip ClipSpace) Mat4 {
+	dn, df := clip.depths()
+	rl, tb := right-left, top-bottom
+	a := (dn - df) / (far - near)
+	return Mat4{
+		{2 / rl, 0, 0, -(right + left) / rl},
+		{0, 2 / tb, 0, -(top + bottom) / tb},
+		{0, 0, a, dn + a*near},
+		{0, 0, 0, 1},
+	}
+}
+
+// PerspectiveClip returns a symmetric perspective projection like
+// Perspective that maps depths using the given clip-space convention.
+// The far distance may be math.Inf(1) for an infinite far plane.
+//
+// With u = −z the view-space distance, the projected depth is
+//
+//	d(u) = −A + B/u, with d(near) = dn and d(far) = df
+func PerspectiveClip(fovy, aspect, near, far float64, clip ClipSpace) Mat4 {
+	dn, df := clip.depths()
+	var a, b float64
+	if math.IsInf(far, 1) {
+		a, b = -df, (dn-df)*near
+	} else {
+		a = (dn-df)*far/(far-near) - dn
+		b = (dn - df) * near * far / (far - near)
+	}
+	f := 1 / math.Tan(fovy/2)
+	return Mat4{
+		{f / aspect, 0, 0, 0},
+		{0, f, 0, 0},
+		{0, 0, a, b},
+		{0, 0, -1, 0},
+	}
+}
+
+// PerspectiveReversedInfinite returns a perspective projection with an
+// infinite far plane and reversed depth in [0, 1], mapping the near plane
+// to 1 and infinity to 0. It is the usual choice for modern renderers
+// with floating-point depth buffers.
+func PerspectiveReversedInfinite(fovy, aspect, near float64) Mat4 {
+	return PerspectiveClip(fovy, aspect, near, math.Inf(1), ClipSpace{Depth: DepthZeroToOne, Reversed: true})
+}
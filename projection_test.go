@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestLookAt(t *testing.T) {
+	eye := math3d.Point{X: 0, Y: 0, Z: 5}
+	view := math3d.LookAt(eye, math3d.Point{}, math3d.NewVec3(0, 1, 0))
+	if p := view.TransformPoint(eye); !p.ApproxEqual(math3d.Point{}, 1e-12) {
+		t.Errorf("LookAt: eye: want %v, got %v\n", math3d.Point{}, p)
+	}
+	if p := view.TransformPoint(math3d.Point{}); !p.ApproxEqual(math3d.Point{Z: -5}, 1e-12) {
+		t.Errorf("LookAt: center: want %v, got %v\n", math3d.Point{Z: -5}, p)
+	}
+	if p := view.TransformPoint(math3d.Point{X: 1, Y: 1}); !p.ApproxEqual(math3d.Point{X: 1, Y: 1, Z: -5}, 1e-12) {
+		t.Errorf("LookAt: want %v, got %v\n", math3d.Point{X: 1, Y: 1, Z: -5}, p)
+	}
+}
+
+func TestProjections(t *testing.T) {
+	near, far := 1.0, 100.0
+	proj := math3d.Perspective(math.Pi/2, 2, near, far)
+	for _, tt := range []struct {
+		name   string
+		p      math3d.Point
+		expect math3d.Point
+	}{
+		{"near", math3d.Point{Z: -near}, math3d.Point{Z: -1}},
+		{"far", math3d.Point{Z: -far}, math3d.Point{Z: 1}},
+		{"top right", math3d.Point{X: 2, Y: 1, Z: -near}, math3d.Point{X: 1, Y: 1, Z: -1}},
+	} {
+		if p := proj.TransformPoint(tt.p); !p.ApproxEqual(tt.expect, 1e-12) {
+			t.Errorf("Perspective: %s: want %v, got %v\n", tt.name, tt.expect, p)
+		}
+	}
+	if f := math3d.PerspectiveFrustum(-2, 2, -1, 1, near, far); !f.ApproxEqual(proj, 1e-12) {
+		t.Errorf("PerspectiveFrustum: want %v, got %v\n", proj, f)
+	}
+
+	ortho := math3d.Ortho(-10, 10, -5, 5, 0, 20)
+	if p := ortho.TransformPoint(math3d.Point{X: 10, Y: -5, Z: -20}); !p.ApproxEqual(math3d.Point{X: 1, Y: -1, Z: 1}, 1e-12) {
+		t.Errorf("Ortho: want %v, got %v\n", math3d.Point{X: 1, Y: -1, Z: 1}, p)
+	}
+}
+
+func TestClipSpace(t *testing.T) {
+	near, far := 0.5, 50.0
+	fovy, aspect := math.Pi/3, 1.5
+	if m := math3d.PerspectiveClip(fovy, aspect, near, far, math3d.ClipSpace{}); !m.ApproxEqual(math3d.Perspective(fovy, aspect, near, far), 1e-12) {
+		t.Errorf("PerspectiveClip: default: want %v, got %v\n", math3d.Perspective(fovy, aspect, near, far), m)
+	}
+	if m := math3d.OrthoClip(-1, 1, -1, 1, near, far, math3d.ClipSpace{}); !m.ApproxEqual(math3d.Ortho(-1, 1, -1, 1, near, far), 1e-12) {
+		t.Errorf("OrthoClip: default: want %v, got %v\n", math3d.Ortho(-1, 1, -1, 1, near, far), m)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		clip      math3d.ClipSpace
+		far       float64
+		dn, df    float64
+		isInfFar  bool
+		orthoTest bool
+	}{
+		{"gl", math3d.ClipSpace{}, far, -1, 1, false, true},
+		{"d3d", math3d.ClipSpace{Depth: math3d.DepthZeroToOne}, far, 0, 1, false, true},
+		{"gl reversed", math3d.ClipSpace{Reversed: true}, far, 1, -1, false, true},
+		{"d3d reversed", math3d.ClipSpace{Depth: math3d.DepthZeroToOne, Reversed: true}, far, 1, 0, false, true},
+		{"gl infinite", math3d.ClipSpace{}, math.Inf(1), -1, 1, true, false},
+		{"d3d reversed infinite", math3d.ClipSpace{Depth: math3d.DepthZeroToOne, Reversed: true}, math.Inf(1), 1, 0, true, false},
+	} {
+		m := math3d.PerspectiveClip(fovy, aspect, near, tt.far, tt.clip)
+		if p := m.TransformPoint(math3d.Point{Z: -near}); math.Abs(p.Z-tt.dn) > 1e-12 {
+			t.Errorf("PerspectiveClip: %s: near: want %f, got %f\n", tt.name, tt.dn, p.Z)
+		}
+		farZ := -tt.far
+		if tt.isInfFar {
+			farZ = -1e15
+		}
+		if p := m.TransformPoint(math3d.Point{Z: farZ}); math.Abs(p.Z-tt.df) > 1e-9 {
+			t.Errorf("PerspectiveClip: %s: far: want %f, got %f\n", tt.name, tt.df, p.Z)
+		}
+		if tt.orthoTest {
+			o := math3d.OrthoClip(-1, 1, -1, 1, near, far, tt.clip)
+			if p := o.TransformPoint(math3d.Point{Z: -near}); math.Abs(p.Z-tt.dn) > 1e-12 {
+				t.Errorf("OrthoClip: %s: near: want %f, got %f\n", tt.name, tt.dn, p.Z)
+			}
+			if p := o.TransformPoint(math3d.Point{Z: -far}); math.Abs(p.Z-tt.df) > 1e-12 {
+				t.Errorf("OrthoClip: %s: far: want %f, got %f\n", tt.name, tt.df, p.Z)
+			}
+		}
+	}
+
+	m := math3d.PerspectiveReversedInfinite(fovy, aspect, near)
+	if p := m.TransformPoint(math3d.Point{Z: -near}); math.Abs(p.Z-1) > 1e-12 {
+		t.Errorf("PerspectiveReversedInfinite: near: want %f, got %f\n", 1.0, p.Z)
+	}
+}
+
+func TestCamera(t *testing.T) {
+	for _, c := range []*math3d.Camera{
+		math3d.NewPerspectiveCamera(math3d.Point{X: 1, Y: 2, Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0), math.Pi/3, 1.5, 0.1, 100),
+		math3d.NewOrthographicCamera(math3d.Point{X: 1, Y: 2, Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0), 20, 1.5, 0.1, 100),
+	} {
+		if p := c.Project(math3d.Point{}); math.Abs(p.X) > 1e-12 || math.Abs(p.Y) > 1e-12 {
+			t.Errorf("Project: target: want center of view, got %v\n", p)
+		}
+		world := math3d.Point{X: 0.5, Y: -1, Z: 2}
+		ndc := c.Project(world)
+		if p, ok := c.Unproject(math3d.NewVec2(ndc.X, ndc.Y), ndc.Z); !ok || !p.ApproxEqual(world, 1e-9) {
+			t.Errorf("Unproject: want %v, got %v (%v)\n", world, p, ok)
+		}
+	}
+
+	// degenerate parameters leave the view-projection singular
+	for _, tc := range []struct {
+		name string
+		c    *math3d.Camera
+	}{
+		{"near == far", math3d.NewPerspectiveCamera(math3d.Point{Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0), math.Pi/3, 1.5, 1, 1)},
+		{"aspect 0", math3d.NewPerspectiveCamera(math3d.Point{Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0), math.Pi/3, 0, 0.1, 100)},
+		{"zero forward", math3d.NewPerspectiveCamera(math3d.Point{Z: 10}, math3d.Point{Z: 10}, math3d.NewVec3(0, 1, 0), math.Pi/3, 1.5, 0.1, 100)},
+		{"orthographic near == far", math3d.NewOrthographicCamera(math3d.Point{Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0), 20, 1.5, 1, 1)},
+	} {
+		if p, ok := tc.c.Unproject(math3d.NewVec2(0.25, -0.5), 0.5); ok {
+			t.Errorf("Unproject: %s: want false, got %v\n", tc.name, p)
+		}
+	}
+
+	// the far plane of an infinite projection maps to infinity
+	for _, clip := range []math3d.ClipSpace{{}, {Depth: math3d.DepthZeroToOne, Reversed: true}} {
+		c := math3d.NewPerspectiveCamera(math3d.Point{Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0), math.Pi/3, 1.5, 0.1, math.Inf(1))
+		c.Clip = clip
+		far := 1.0
+		if clip.Reversed {
+			far = 0
+		}
+		if p, ok := c.Unproject(math3d.NewVec2(0.25, -0.5), far); ok {
+			t.Errorf("Unproject: infinite far %v: want false, got %v\n", clip, p)
+		}
+		world := math3d.Point{X: 0.5, Y: -1, Z: 2}
+		ndc := c.Project(world)
+		if p, ok := c.Unproject(math3d.NewVec2(ndc.X, ndc.Y), ndc.Z); !ok || !p.ApproxEqual(world, 1e-6) {
+			t.Errorf("Unproject: infinite far %v: want %v, got %v (%v)\n", clip, world, p, ok)
+		}
+	}
+}
+
+func TestProjectUnproject(t *testing.T) {
+	vp := math3d.Viewport{X: 0, Y: 0, Width: 800, Height: 600}
+	mv := math3d.LookAt(math3d.Point{Z: 10}, math3d.Point{}, math3d.NewVec3(0, 1, 0))
+	proj := math3d.Perspective(math.Pi/2, 800.0/600.0, 1, 100)
+
+	win, ok := math3d.Project(math3d.Point{}, mv, proj, vp)
+	if !ok {
+		t.Fatalf("Project: want ok, got false\n")
+	}
+	if math.Abs(win.X-400) > 1e-9 || math.Abs(win.Y-300) > 1e-9 {
+		t.Errorf("Project: want center of viewport, got %v\n", win)
+	}
+	if win.Z <= 0 || win.Z >= 1 {
+		t.Errorf("Project: depth: want (0, 1), got %f\n", win.Z)
+	}
+
+	obj := math3d.Point{X: 2, Y: -3, Z: 1}
+	win, _ = math3d.Project(obj, mv, proj, vp)
+	if p, ok := math3d.Unproject(win, mv, proj, vp); !ok || !p.ApproxEqual(obj, 1e-9) {
+		t.Errorf("Unproject: want %v, got %v\n", obj, p)
+	}
+}
+
+func TestPickRay(t *testing.T) {
+	eye := math3d.Point{X: 0, Y: 0, Z: 10}
+	for _, clip := range []math3d.ClipSpace{
+		{},
+		{Depth: math3d.DepthZeroToOne, Reversed: true},
+	} {
+		c := math3d.NewPerspectiveCamera(eye, math3d.Point{}, math3d.NewVec3(0, 1, 0), math.Pi/2, 1, 1, math.Inf(1))
+		c.Clip = clip
+		r, ok := c.PickRay(math3d.NewVec2(0, 0))
+		if !ok {
+			t.Fatalf("PickRay: want ok, got false\n")
+		}
+		if !r.Origin.ApproxEqual(math3d.Point{Z: 9}, 1e-9) {
+			t.Errorf("PickRay: origin: want %v, got %v\n", math3d.Point{Z: 9}, r.Origin)
+		}
+		if !r.Direction.ApproxEqual(math3d.NewVec3(0, 0, -1), 1e-9) {
+			t.Errorf("PickRay: direction: want %v, got %v\n", math3d.NewVec3(0, 0, -1), r.Direction)
+		}
+
+		// a ray through the corner passes through points that project to the corner
+		r, _ = c.PickRay(math3d.NewVec2(1, 1))
+		p := r.Origin.Add(r.Direction.Mul(5))
+		if ndc := c.Project(p); math.Abs(ndc.X-1) > 1e-9 || math.Abs(ndc.Y-1) > 1e-9 {
+			t.Errorf("PickRay: corner: want (1, 1), got %v\n", ndc)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Quaternion implements a quaternion w + xi + yj + zk.
+// Unit quaternions represent rotations in three dimensions.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// IdentityQuaternion returns the quaternion for no rotation.
+func IdentityQuaternion() Quaternion {
+	return Quaternion{W: 1}
+}
+
+func NewQuaternion(w, x, y, z float64) Quaternion {
+	return Quaternion{W: w, X: x, Y: y, Z: z}
+}
+
+// QuaternionFromAxisAngle returns the unit quaternion that rotates
+// counter-clockwise by angle radians about the given axis, which need
+// not be normalized. Returns the identity if the axis is zero.
+func QuaternionFromAxisAngle(axis Vec3, angle float64) Quaternion {
+	k, ok := axis.Normalized()
+	if !ok {
+		return IdentityQuaternion()
+	}
+	sin, cos := math.Sincos(angle / 2)
+	return Quaternion{W: cos, X: k.X * sin, Y: k.Y * sin, Z: k.Z * sin}
+}
+
+// QuaternionBetween returns the unit quaternion for the shortest-arc
+// rotation that turns the direction of from onto the direction of to.
+// When the directions are opposite, the rotation is a half turn about an
+// arbitrary axis perpendicular to from. Returns the identity if either
+// vector is zero.
+func QuaternionBetween(from, to Vec3) Quaternion {
+	u, ok := from.Normalized()
+	if !ok {
+		return IdentityQuaternion()
+	}
+	v, ok := to.Normalized()
+	if !ok {
+		return IdentityQuaternion()
+	}
+	// (1 + u·v, u × v) is twice the half-angle rotation, up to scale
+	w := 1 + u.Dot(v)
+	if w < 1e-12 {
+		axis := u.anyPerpendicular().Normalize()
+		return Quaternion{X: axis.X, Y: axis.Y, Z: axis.Z}
+	}
+	c := u.Cross(v)
+	return Quaternion{W: w, X: c.X, Y: c.Y, Z: c.Z}.Normalize()
+}
+
+// QuaternionLookRotation returns the orientation that faces along forward
+// with its up axis as close to up as possible.
+// See Mat3FromLookRotation for the conventions used.
+func QuaternionLookRotation(forward, up Vec3) Quaternion {
+	return Mat3FromLookRotation(forward, up).ToQuaternion()
+}
+
+// RandomQuaternion returns a unit quaternion drawn uniformly from all
+// rotations, using Shoemake's method from Graphics Gems III. If rng is
+// nil, the default source of the math/rand package is used.
+func RandomQuaternion(rng *rand.Rand) Quaternion {
+	float := rand.Float64
+	if rng != nil {
+		float = rng.Float64
+	}
+	u1, u2, u3 := float(), float(), float()
+	r1, r2 := math.Sqrt(1-u1), math.Sqrt(u1)
+	sin1, cos1 := math.Sincos(2 * math.Pi * u2)
+	sin2, cos2 := math.Sincos(2 * math.Pi * u3)
+	return Quaternion{W: r2 * cos2, X: r1 * sin1, Y: r1 * cos1, Z: r2 * sin2}
+}
+
+func (q Quaternion) Add(r Quaternion) Quaternion {
+	return Quaternion{W: q.W + r.W, X: q.X + r.X, Y: q.Y + r.Y, Z: q.Z + r.Z}
+}
+
+// ApproxEqual reports whether each component of q is within epsilon of
+// the corresponding component of r. Note that q and −q represent the
+// same rotation but are not equal.
+func (q Quaternion) ApproxEqual(r Quaternion, epsilon float64) bool {
+	return ApproxEqual(q.W, r.W, epsilon) && ApproxEqual(q.X, r.X, epsilon) && ApproxEqual(q.Y, r.Y, epsilon) && ApproxEqual(q.Z, r.Z, epsilon)
+}
+
+// Conjugate returns the conjugate w − xi − yj − zk. For a unit
+// quaternion it is the inverse rotation.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// Dot returns the four-dimensional dot product of the two quaternions.
+// For unit quaternions it is the cosine of half the angle between the
+// rotations, up to sign.
+func (q Quaternion) Dot(r Quaternion) float64 {
+	return q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+}
+
+// Integrate advances the orientation q by the angular velocity omega,
+// given in radians per unit time about world axes, over the timestep dt.
+// The rotation over the step is applied exactly through the exponential
+// map and the result is renormalized to keep rounding from accumulating.
+func (q Quaternion) Integrate(omega Vec3, dt float64) Quaternion {
+	return QuaternionFromRotationVector(omega.Mul(dt)).Mul(q).Normalize()
+}
+
+// Inverse returns the multiplicative inverse of the quaternion.
+// It returns false if q is zero.
+func (q Quaternion) Inverse() (Quaternion, bool) {
+	n := q.LengthSquared()
+	if n == 0 {
+		return Quaternion{}, false
+	}
+	return q.Conjugate().MulScalar(1 / n), true
+}
+
+// IsFinite reports whether every component of the quaternion is neither NaN nor infinite.
+func (q Quaternion) IsFinite() bool {
+	return Vec4(q).IsFinite()
+}
+
+// Length returns the norm of the quaternion.
+func (q Quaternion) Length() float64 {
+	return math.Sqrt(q.LengthSquared())
+}
+
+// LengthSquared returns the square of the norm of the quaternion.
+func (q Quaternion) LengthSquared() float64 {
+	return q.Dot(q)
+}
+
+// Mul returns the Hamilton product q×r. As rotations, the product
+// applies r first and then q.
+func (q Quaternion) Mul(r Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+// MulScalar returns the quaternion with every component multiplied by scalar.
+func (q Quaternion) MulScalar(scalar float64) Quaternion {
+	return Quaternion{W: q.W * scalar, X: q.X * scalar, Y: q.Y * scalar, Z: q.Z * scalar}
+}
+
+// Nlerp returns the normalized linear interpolation between the unit
+// quaternions q and r, taking the shorter path between the rotations.
+// It is cheaper than Slerp and follows the same path, but its angular
+// speed is not constant.
+func (q Quaternion) Nlerp(r Quaternion, t float64) Quaternion {
+	if q.Dot(r) < 0 {
+		r = r.MulScalar(-1)
+	}
+	return Quaternion(Vec4(q).Lerp(Vec4(r), t)).Normalize()
+}
+
+// Normalize returns the unit quaternion in the direction of q.
+// It returns the identity if q is zero.
+func (q Quaternion) Normalize() Quaternion {
+	if u, ok := q.Normalized(); ok {
+		return u
+	}
+	return IdentityQuaternion()
+}
+
+// Normalized returns the unit quaternion in the direction of q.
+// It returns false if q is zero or its length is not finite.
+func (q Quaternion) Normalized() (Quaternion, bool) {
+	u, ok := Vec4(q).Normalized()
+	return Quaternion(u), ok
+}
+
+// RotateVec3 returns v rotated by the unit quaternion q. It computes
+// q v q* without building a matrix.
+//
+//	t = 2(u × v)
+//	v' = v + w t + u × t
+func (q Quaternion) RotateVec3(v Vec3) Vec3 {
+	u := Vec3{X: q.X, Y: q.Y, Z: q.Z}
+	t := u.Cross(v).Mul(2)
+	return v.Add(t.Mul(q.W)).Add(u.Cross(t))
+}
+
+// Slerp returns the spherical linear interpolation between the unit
+// quaternions q and r, which rotates at a constant angular speed along
+// the shorter path between the rotations.
+func (q Quaternion) Slerp(r Quaternion, t float64) Quaternion {
+	if q.Dot(r) < 0 {
+		r = r.MulScalar(-1)
+	}
+	return q.slerp(r, t)
+}
+
+func (q Quaternion) Sub(r Quaternion) Quaternion {
+	return Quaternion{W: q.W - r.W, X: q.X - r.X, Y: q.Y - r.Y, Z: q.Z - r.Z}
+}
+
+// SwingTwist decomposes q into a twist about axis followed by a swing
+// about an axis perpendicular to it, so that q = swing·twist. When q is
+// a half turn about an axis perpendicular to axis, the twist is the
+// identity. The axis need not be normalized.
+func (q Quaternion) SwingTwist(axis Vec3) (swing, twist Quaternion) {
+	a, ok := axis.Normalized()
+	if !ok {
+		return q, IdentityQuaternion()
+	}
+	p := a.Mul(q.X*a.X + q.Y*a.Y + q.Z*a.Z)
+	twist, ok = Quaternion{W: q.W, X: p.X, Y: p.Y, Z: p.Z}.Normalized()
+	if !ok {
+		twist = IdentityQuaternion()
+	}
+	return q.Mul(twist.Conjugate()), twist
+}
+
+// ToMat3 returns the rotation matrix for the unit quaternion q.
+func (q Quaternion) ToMat3() Mat3 {
+	xx, yy, zz := q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+	return Mat3{
+		{1 - 2*(yy+zz), 2 * (xy - wz), 2 * (xz + wy)},
+		{2 * (xy + wz), 1 - 2*(xx+zz), 2 * (yz - wx)},
+		{2 * (xz - wy), 2 * (yz + wx), 1 - 2*(xx+yy)},
+	}
+}
+
+// ToMat4 returns the rotation transform for the unit quaternion q.
+func (q Quaternion) ToMat4() Mat4 {
+	return Mat4FromMat3(q.ToMat3())
+}
+
+// slerp interpolates along the great arc from q to r on the unit
+// hypersphere without choosing the shorter path, which Squad requires.
+// Nearly equal quaternions fall back to normalized linear interpolation
+// to avoid dividing by a vanishing sine.
+func (q Quaternion) slerp(r Quaternion, t float64) Quaternion {
+	cos := q.Dot(r)
+	if math.Abs(cos) > 0.9995 {
+		return Quaternion(Vec4(q).Lerp(Vec4(r), t)).Normalize()
+	}
+	theta := math.Acos(Clamp(cos, -1, 1))
+	sin := math.Sin(theta)
+	a, b := math.Sin((1-t)*theta)/sin, math.Sin(t*theta)/sin
+	return q.MulScalar(a).Add(r.MulScalar(b))
+}
+
+// Squad returns the spherical quadrangle interpolation between the unit
+// quaternions q1 and q2 at parameter t, using the control points s1 and
+// s2 from SquadControlPoint. Chaining Squad over a sequence of keyframes
+// gives a rotation path with continuous angular velocity.
+//
+//	squad = slerp(slerp(q1, q2, t), slerp(s1, s2, t), 2t(1 − t))
+func Squad(q1, q2, s1, s2 Quaternion, t float64) Quaternion {
+	return q1.slerp(q2, t).slerp(s1.slerp(s2, t), 2*t*(1-t))
+}
+
+// SquadControlPoint returns the inner control point for the keyframe q
+// between the keyframes prev and next. The keyframes should already be
+// sign-aligned so that neighbors have non-negative dot products.
+//
+//	s = q exp(−(log(q⁻¹ next) + log(q⁻¹ prev)) / 4)
+func SquadControlPoint(prev, q, next Quaternion) Quaternion {
+	qinv := q.Conjugate()
+	// the rotation vector is twice the quaternion logarithm
+	a := qinv.Mul(next).RotationVector()
+	b := qinv.Mul(prev).RotationVector()
+	return q.Mul(QuaternionFromRotationVector(a.Add(b).Mul(-0.25)))
+}
@@ -0,0 +1,321 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuaternion(t *testing.T) {
+	qz := math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 0, 1), math.Pi/2)
+	qx := math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 0, 0), math.Pi/2)
+
+	if v := qz.RotateVec3(math3d.NewVec3(1, 0, 0)); !v.ApproxEqual(math3d.NewVec3(0, 1, 0), 1e-12) {
+		t.Errorf("RotateVec3: want %v, got %v\n", math3d.NewVec3(0, 1, 0), v)
+	}
+
+	// the product applies the right-hand rotation first
+	v := math3d.NewVec3(0, 1, 0)
+	if got, expect := qz.Mul(qx).RotateVec3(v), qz.RotateVec3(qx.RotateVec3(v)); !got.ApproxEqual(expect, 1e-12) {
+		t.Errorf("Mul: want %v, got %v\n", expect, got)
+	}
+
+	q := math3d.NewQuaternion(1, 2, 3, 4)
+	inv, ok := q.Inverse()
+	if !ok {
+		t.Fatalf("Inverse: want ok, got false\n")
+	}
+	if p := q.Mul(inv); !p.ApproxEqual(math3d.IdentityQuaternion(), 1e-12) {
+		t.Errorf("Inverse: want identity, got %v\n", p)
+	}
+	if n := q.Normalize().Length(); math.Abs(n-1) > 1e-12 {
+		t.Errorf("Normalize: want length %f, got %f\n", 1.0, n)
+	}
+	if n := (math3d.Quaternion{}).Normalize(); n != math3d.IdentityQuaternion() {
+		t.Errorf("Normalize: zero: want identity, got %v\n", n)
+	}
+	if c := qz.Conjugate().RotateVec3(math3d.NewVec3(0, 1, 0)); !c.ApproxEqual(math3d.NewVec3(1, 0, 0), 1e-12) {
+		t.Errorf("Conjugate: want %v, got %v\n", math3d.NewVec3(1, 0, 0), c)
+	}
+	if d := q.Dot(q); d != 30 {
+		t.Errorf("Dot: want %f, got %f\n", 30.0, d)
+	}
+}
+
+func TestQuaternionMatrix(t *testing.T) {
+	for _, q := range []math3d.Quaternion{
+		math3d.IdentityQuaternion(),
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 2, 3), 0.7),
+		// rotations by nearly π exercise each branch of the extraction
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 0.1, 0.1), math.Pi-1e-3),
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(0.1, 1, 0.1), math.Pi-1e-3),
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(0.1, 0.1, 1), math.Pi),
+	} {
+		m := q.ToMat3()
+		v := math3d.NewVec3(0.3, -1, 2)
+		if got, expect := m.MulVec3(v), q.RotateVec3(v); !got.ApproxEqual(expect, 1e-12) {
+			t.Errorf("ToMat3: want %v, got %v\n", expect, got)
+		}
+		r := m.ToQuaternion()
+		if r.Dot(q) < 0 {
+			r = r.MulScalar(-1)
+		}
+		if !r.ApproxEqual(q, 1e-12) {
+			t.Errorf("ToQuaternion: want %v, got %v\n", q, r)
+		}
+		if r := q.ToMat4().ToQuaternion(); math.Abs(math.Abs(r.Dot(q))-1) > 1e-12 {
+			t.Errorf("Mat4.ToQuaternion: want %v, got %v\n", q, r)
+		}
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	axis := math3d.NewVec3(0, 0, 1)
+	q := math3d.IdentityQuaternion()
+	r := math3d.QuaternionFromAxisAngle(axis, math.Pi/2)
+	for _, tt := range []float64{0, 0.25, 0.5, 1} {
+		expect := math3d.QuaternionFromAxisAngle(axis, tt*math.Pi/2)
+		if s := q.Slerp(r, tt); !s.ApproxEqual(expect, 1e-12) {
+			t.Errorf("Slerp(%f): want %v, got %v\n", tt, expect, s)
+		}
+		// −r is the same rotation; the shortest path must not go the long way round
+		if s := q.Slerp(r.MulScalar(-1), tt); !s.ApproxEqual(expect, 1e-12) {
+			t.Errorf("Slerp(%f): negated: want %v, got %v\n", tt, expect, s)
+		}
+	}
+	if n := q.Nlerp(r, 0.5); !n.ApproxEqual(math3d.QuaternionFromAxisAngle(axis, math.Pi/4), 1e-12) {
+		t.Errorf("Nlerp: want %v, got %v\n", math3d.QuaternionFromAxisAngle(axis, math.Pi/4), n)
+	}
+
+	// Squad passes through its keyframes, and with evenly spaced
+	// rotations about one axis it reduces to Slerp
+	keys := []math3d.Quaternion{
+		math3d.QuaternionFromAxisAngle(axis, 0),
+		math3d.QuaternionFromAxisAngle(axis, 0.5),
+		math3d.QuaternionFromAxisAngle(axis, 1),
+		math3d.QuaternionFromAxisAngle(axis, 1.5),
+	}
+	s1 := math3d.SquadControlPoint(keys[0], keys[1], keys[2])
+	s2 := math3d.SquadControlPoint(keys[1], keys[2], keys[3])
+	for _, tt := range []float64{0, 0.3, 1} {
+		expect := keys[1].Slerp(keys[2], tt)
+		if s := math3d.Squad(keys[1], keys[2], s1, s2, tt); !s.ApproxEqual(expect, 1e-12) {
+			t.Errorf("Squad(%f): want %v, got %v\n", tt, expect, s)
+		}
+	}
+}
+
+func TestEulerAngles(t *testing.T) {
+	for _, order := range []math3d.RotationOrder{
+		math3d.OrderXYZ, math3d.OrderXZY, math3d.OrderYXZ,
+		math3d.OrderYZX, math3d.OrderZXY, math3d.OrderZYX,
+	} {
+		for _, e := range []math3d.EulerAngles{
+			{X: 0.1, Y: 0.2, Z: 0.3, Order: order},
+			{X: -2.5, Y: 1.2, Z: 3, Order: order},
+		} {
+			m := e.ToMat3()
+			if q := e.ToQuaternion().ToMat3(); !q.ApproxEqual(m, 1e-12) {
+				t.Errorf("%s: ToQuaternion: want %v, got %v\n", order, m, q)
+			}
+			// the angles are only unique when the middle one is in [−π/2, π/2]
+			got := math3d.EulerAnglesFromMat3(m, order)
+			if got.Order != order || !got.ToMat3().ApproxEqual(m, 1e-9) {
+				t.Errorf("%s: EulerAnglesFromMat3: want %v, got %v\n", order, e, got)
+			}
+			if math.Abs(e.X) < math.Pi/2 && math.Abs(e.Y) < math.Pi/2 && math.Abs(e.Z) < math.Pi/2 {
+				if !math3d.NewVec3(got.X, got.Y, got.Z).ApproxEqual(math3d.NewVec3(e.X, e.Y, e.Z), 1e-9) {
+					t.Errorf("%s: EulerAnglesFromMat3: want %v, got %v\n", order, e, got)
+				}
+			}
+			got = math3d.EulerAnglesFromQuaternion(e.ToQuaternion(), order)
+			if !got.ToMat3().ApproxEqual(m, 1e-9) {
+				t.Errorf("%s: EulerAnglesFromQuaternion: want %v, got %v\n", order, e, got)
+			}
+		}
+
+		// at gimbal lock the angles are not unique, but the rotation is
+		var lock math3d.EulerAngles
+		switch order {
+		case math3d.OrderXYZ, math3d.OrderZYX:
+			lock = math3d.EulerAngles{X: 0.4, Y: math.Pi / 2, Z: 0.2, Order: order}
+		case math3d.OrderYXZ, math3d.OrderZXY:
+			lock = math3d.EulerAngles{X: -math.Pi / 2, Y: 0.4, Z: 0.2, Order: order}
+		default:
+			lock = math3d.EulerAngles{X: 0.4, Y: 0.2, Z: math.Pi / 2, Order: order}
+		}
+		if got := math3d.EulerAnglesFromMat3(lock.ToMat3(), order); !got.ToMat3().ApproxEqual(lock.ToMat3(), 1e-9) {
+			t.Errorf("%s: gimbal lock: want %v, got %v\n", order, lock.ToMat3(), got.ToMat3())
+		}
+	}
+}
+
+func TestAxisAngle(t *testing.T) {
+	axis := math3d.NewVec3(1, 2, 2).Normalize()
+	v := math3d.NewVec3(3, -1, 0.5)
+	for _, angle := range []float64{0, 0.3, math.Pi / 2, math.Pi} {
+		got := math3d.RotateAboutAxis(v, axis, angle)
+		if expect := math3d.Mat3FromAxisAngle(axis, angle).MulVec3(v); !got.ApproxEqual(expect, 1e-12) {
+			t.Errorf("RotateAboutAxis(%f): want %v, got %v\n", angle, expect, got)
+		}
+		a := math3d.AxisAngle{Axis: axis, Angle: angle}
+		if r := a.ToQuaternion().RotateVec3(v); !r.ApproxEqual(got, 1e-12) {
+			t.Errorf("ToQuaternion(%f): want %v, got %v\n", angle, got, r)
+		}
+		if angle == 0 {
+			continue
+		}
+		if b := math3d.AxisAngleFromQuaternion(a.ToQuaternion()); !b.Axis.ApproxEqual(axis, 1e-12) || math.Abs(b.Angle-angle) > 1e-12 {
+			t.Errorf("AxisAngleFromQuaternion(%f): want %v, got %v\n", angle, a, b)
+		}
+	}
+}
+
+func TestQuaternionBetween(t *testing.T) {
+	for _, tt := range []struct {
+		from, to math3d.Vec3
+	}{
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(0, 1, 0)},
+		{math3d.NewVec3(1, 2, 3), math3d.NewVec3(-2, 0.5, 1)},
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(3, 0, 0)},
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(-1, 0, 0)},
+		{math3d.NewVec3(0, 0, 2), math3d.NewVec3(0, 0, -1)},
+		{math3d.NewVec3(1, 1, 1), math3d.NewVec3(-1, -1, -1+1e-9)},
+	} {
+		q := math3d.QuaternionBetween(tt.from, tt.to)
+		if n := q.Length(); math.Abs(n-1) > 1e-12 {
+			t.Errorf("QuaternionBetween(%v, %v): want unit quaternion, got length %f\n", tt.from, tt.to, n)
+		}
+		if v := q.RotateVec3(tt.from.Normalize()); !v.ApproxEqual(tt.to.Normalize(), 1e-9) {
+			t.Errorf("QuaternionBetween(%v, %v): want %v, got %v\n", tt.from, tt.to, tt.to.Normalize(), v)
+		}
+	}
+}
+
+func TestQuaternionLookRotation(t *testing.T) {
+	for _, tt := range []struct {
+		forward, up math3d.Vec3
+	}{
+		{math3d.NewVec3(0, 0, -1), math3d.NewVec3(0, 1, 0)},
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(0, 1, 0)},
+		{math3d.NewVec3(1, 2, -3), math3d.NewVec3(0, 0, 1)},
+		{math3d.NewVec3(0, 5, 0), math3d.NewVec3(0, 1, 0)},
+	} {
+		q := math3d.QuaternionLookRotation(tt.forward, tt.up)
+		f := q.RotateVec3(math3d.NewVec3(0, 0, -1))
+		if want := tt.forward.Normalize(); !f.ApproxEqual(want, 1e-9) {
+			t.Errorf("QuaternionLookRotation(%v, %v): forward: want %v, got %v\n", tt.forward, tt.up, want, f)
+		}
+		u := q.RotateVec3(math3d.NewVec3(0, 1, 0))
+		if d := u.Dot(f); math.Abs(d) > 1e-9 {
+			t.Errorf("QuaternionLookRotation(%v, %v): up·forward: want 0, got %f\n", tt.forward, tt.up, d)
+		}
+		if d := u.Dot(tt.up.Normalize()); d < -1e-9 {
+			t.Errorf("QuaternionLookRotation(%v, %v): up·hint: want >= 0, got %f\n", tt.forward, tt.up, d)
+		}
+	}
+
+	// the orientation undoes the rotation of the matching view matrix
+	eye, center, up := math3d.Point{X: 1, Y: 2, Z: 3}, math3d.Point{X: -2, Z: 1}, math3d.NewVec3(0, 1, 0)
+	view := math3d.LookAt(eye, center, up).Mat3()
+	got := math3d.Mat3FromLookRotation(center.Sub(eye), up)
+	if want := view.Transpose(); !got.ApproxEqual(want, 1e-12) {
+		t.Errorf("Mat3FromLookRotation: want %v, got %v\n", want, got)
+	}
+}
+
+func TestQuaternionSwingTwist(t *testing.T) {
+	axis := math3d.NewVec3(0, 1, 0)
+	for _, q := range []math3d.Quaternion{
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 1, 0), 0.7),
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 0, 0), 0.7),
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 2, 3), 1.9),
+		math3d.QuaternionFromAxisAngle(math3d.NewVec3(0, 0, 1), math.Pi),
+	} {
+		swing, twist := q.SwingTwist(axis)
+		if got := swing.Mul(twist); !got.ApproxEqual(q, 1e-12) {
+			t.Errorf("SwingTwist(%v): swing·twist: want %v, got %v\n", q, q, got)
+		}
+		if twist.X != 0 || twist.Z != 0 {
+			t.Errorf("SwingTwist(%v): twist: want rotation about y, got %v\n", q, twist)
+		}
+		if d := swing.Y; math.Abs(d) > 1e-12 {
+			t.Errorf("SwingTwist(%v): swing: want axis perpendicular to y, got %v\n", q, swing)
+		}
+	}
+}
+
+func TestQuaternionIntegrate(t *testing.T) {
+	omega := math3d.NewVec3(0.3, -1.2, 0.5)
+	q := math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 1, 0), 0.4)
+	got := q
+	for i := 0; i < 1000; i++ {
+		got = got.Integrate(omega, 0.002)
+	}
+	want := math3d.QuaternionFromRotationVector(omega.Mul(2)).Mul(q)
+	if !got.ApproxEqual(want, 1e-9) {
+		t.Errorf("Integrate: want %v, got %v\n", want, got)
+	}
+	if n := got.Length(); math.Abs(n-1) > 1e-12 {
+		t.Errorf("Integrate: want unit quaternion, got length %f\n", n)
+	}
+}
+
+func TestRandomQuaternion(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 20000
+	var sq math3d.Vec4
+	var axis math3d.Vec3
+	for i := 0; i < n; i++ {
+		q := math3d.RandomQuaternion(rng)
+		if l := q.Length(); math.Abs(l-1) > 1e-12 {
+			t.Fatalf("RandomQuaternion: want unit quaternion, got length %v\n", l)
+		}
+		sq = sq.Add(math3d.Vec4(q).Hadamard(math3d.Vec4(q)))
+		axis = axis.Add(q.RotateVec3(math3d.NewVec3(1, 0, 0)))
+	}
+	// uniform on the 3-sphere, each squared component averages 1/4
+	sq = sq.Mul(1.0 / n)
+	if want := math3d.NewVec4(0.25, 0.25, 0.25, 0.25); !sq.ApproxEqual(want, 0.01) {
+		t.Errorf("RandomQuaternion: mean squares: want %v, got %v\n", want, sq)
+	}
+	// rotated directions are spread evenly over the sphere
+	if axis = axis.Mul(1.0 / n); axis.Length() > 0.03 {
+		t.Errorf("RandomQuaternion: mean direction: want %v, got %v\n", math3d.Vec3{}, axis)
+	}
+
+	m := math3d.RandomRotationMat3(rng)
+	if d := m.Determinant(); math.Abs(d-1) > 1e-12 {
+		t.Errorf("RandomRotationMat3: determinant: want 1, got %v\n", d)
+	}
+	if got := m.Mul(m.Transpose()); !got.ApproxEqual(math3d.Identity3(), 1e-12) {
+		t.Errorf("RandomRotationMat3: want orthogonal, got m×mᵀ = %v\n", got)
+	}
+	if q := math3d.RandomQuaternion(nil); math.Abs(q.Length()-1) > 1e-12 {
+		t.Errorf("RandomQuaternion: nil source: want unit quaternion, got %v\n", q)
+	}
+}
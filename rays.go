@@ -0,0 +1,401 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Ray is a half-line starting at Origin and extending along Direction.
+// The direction need not be normalized; the parameter t of a point on
+// the ray is measured in multiples of Direction. When Direction is a
+// unit vector, t is the distance from the origin.
+type Ray struct {
+	Origin    Point
+	Direction Vec3
+}
+
+// RayHit describes where a ray meets a surface: the parameter T of the
+// hit point along the ray, the Point itself, and the unit Normal of the
+// surface there.
+type RayHit struct {
+	T      float64
+	Point  Point
+	Normal Vec3
+}
+
+// TriangleHit describes where a ray meets a triangle. T is the parameter
+// of the hit point along the ray, and Barycentric holds its weights with
+// respect to the corners A, B, and C. FrontFace reports whether the ray
+// hit the side of the triangle from which its corners appear in
+// counter-clockwise order.
+type TriangleHit struct {
+	T           float64
+	Barycentric Vec3
+	FrontFace   bool
+}
+
+// At returns the point at parameter t along the ray.
+func (r Ray) At(t float64) Point {
+	return r.Origin.Add(r.Direction.Mul(t))
+}
+
+// ClosestPointTo returns the point on the ray nearest to p.
+// Points behind the origin are nearest to the origin itself.
+func (r Ray) ClosestPointTo(p Point) Point {
+	d := r.Direction.LengthSquared()
+	if d == 0 {
+		return r.Origin
+	}
+	t := p.Sub(r.Origin).Dot(r.Direction) / d
+	if t <= 0 {
+		return r.Origin
+	}
+	return r.At(t)
+}
+
+// IntersectCylinder returns the parameter of the first point at or after
+// the origin where the ray meets the surface of the cylinder, including
+// its caps. A ray starting inside the cylinder hits it on the way out.
+// It returns false if the ray misses or the cylinder has no length.
+func (r Ray) IntersectCylinder(c Cylinder) (float64, bool) {
+	axis := c.B.Sub(c.A)
+	h := axis.Length()
+	if h == 0 {
+		return 0, false
+	}
+	axis = axis.Div(h)
+	m := r.Origin.Sub(c.A)
+	md, dd := m.Dot(axis), r.Direction.Dot(axis)
+	rr := c.Radius * c.Radius
+
+	best, hit := 0.0, false
+	try := func(t float64) {
+		if t >= 0 && (!hit || t < best) {
+			best, hit = t, true
+		}
+	}
+
+	// the side, from the components perpendicular to the axis
+	mp, dp := m.Sub(axis.Mul(md)), r.Direction.Sub(axis.Mul(dd))
+	for _, t := range solveQuadratic(dp.Dot(dp), 2*mp.Dot(dp), mp.Dot(mp)-rr) {
+		if s := md + t*dd; s >= 0 && s <= h {
+			try(t)
+		}
+	}
+
+	// the caps, at heights 0 and h along the axis
+	if dd != 0 {
+		for _, s := range []float64{0, h} {
+			t := (s - md) / dd
+			if mp.Add(dp.Mul(t)).LengthSquared() <= rr {
+				try(t)
+			}
+		}
+	}
+	return best, hit
+}
+
+// IntersectPlane returns where the ray meets the plane, with the normal
+// of the plane. It returns false if the ray is parallel to the plane,
+// even when it lies in the plane, or if the plane is behind the origin.
+func (r Ray) IntersectPlane(pl Plane) (RayHit, bool) {
+	denom := pl.Normal.Dot(r.Direction)
+	if denom == 0 {
+		return RayHit{}, false
+	}
+	t := -pl.SignedDistance(r.Origin) / denom
+	if t < 0 || math.IsInf(t, 0) || math.IsNaN(t) {
+		return RayHit{}, false
+	}
+	return RayHit{T: t, Point: r.At(t), Normal: pl.Normal.NormalizeOrZero()}, true
+}
+
+// IntersectSphere returns where the line of the ray enters and leaves the
+// sphere, with the outward normals there. A ray starting inside the
+// sphere enters it at a negative parameter, behind the origin. A ray
+// that grazes the sphere enters and leaves at the same point. It
+// returns false if the ray misses the sphere or the sphere is entirely
+// behind the origin.
+func (r Ray) IntersectSphere(s Sphere) (enter, leave RayHit, ok bool) {
+	// Haines et al., "Precision Improvements for Ray/Sphere Intersection",
+	// Ray Tracing Gems (2019): the discriminant is computed from the
+	// distance of the line to the center, which avoids cancellation when
+	// the sphere is small or far away, and the roots from their product.
+	f := r.Origin.Sub(s.Center)
+	a := r.Direction.Dot(r.Direction)
+	if a == 0 {
+		return RayHit{}, RayHit{}, false
+	}
+	b := -f.Dot(r.Direction)
+	l := f.Add(r.Direction.Mul(b / a))
+	disc := a * (s.Radius*s.Radius - l.Dot(l))
+	if disc < 0 {
+		return RayHit{}, RayHit{}, false
+	}
+	c := f.Dot(f) - s.Radius*s.Radius
+	q := b + math.Copysign(math.Sqrt(disc), b)
+	var t0, t1 float64
+	if q != 0 {
+		t0, t1 = c/q, q/a
+	}
+	if t0 > t1 {
+		t0, t1 = t1, t0
+	}
+	if t1 < 0 {
+		return RayHit{}, RayHit{}, false
+	}
+	hit := func(t float64) RayHit {
+		p := r.At(t)
+		return RayHit{T: t, Point: p, Normal: p.Sub(s.Center).NormalizeOrZero()}
+	}
+	return hit(t0), hit(t1), true
+}
+
+// IntersectTorus returns the parameter of the first point at or after
+// the origin where the ray meets the torus. It solves the quartic
+// equation of the torus in a frame centered on it with the axis along z.
+// It returns false if the ray misses, or if the direction or the axis
+// of the torus is zero.
+func (r Ray) IntersectTorus(tor Torus) (float64, bool) {
+	n, ok := tor.Axis.Normalized()
+	if !ok {
+		return 0, false
+	}
+	length := r.Direction.Length()
+	if length == 0 {
+		return 0, false
+	}
+	u, v := BuildOrthonormalBasis(n)
+	frame := Mat3FromRows(u, v, n)
+	o, d := frame.MulVec3(r.Origin.Sub(tor.Center)), frame.MulVec3(r.Direction.Div(length))
+
+	// (|P|² + R² − r²)² = 4R²(Px² + Py²) with P = o + td and |d| = 1
+	R2 := tor.MajorRadius * tor.MajorRadius
+	od := o.Dot(d)
+	k := o.Dot(o) + R2 - tor.MinorRadius*tor.MinorRadius
+	roots := solvePolynomial([]float64{
+		k*k - 4*R2*(o.X*o.X+o.Y*o.Y),
+		4*od*k - 8*R2*(o.X*d.X+o.Y*d.Y),
+		4*od*od + 2*k - 4*R2*(d.X*d.X+d.Y*d.Y),
+		4 * od,
+		1,
+	})
+	best, hit := 0.0, false
+	for _, t := range roots {
+		if t >= 0 && (!hit || t < best) {
+			best, hit = t, true
+		}
+	}
+	return best / length, hit
+}
+
+// IntersectTriangle returns where the ray first meets the triangle, using
+// the Möller–Trumbore algorithm. Rays that lie in the plane of the
+// triangle miss it. Because the edge tests are done in floating point,
+// a ray through an edge shared by two triangles can occasionally miss
+// both; use IntersectTriangleWatertight when that matters.
+func (r Ray) IntersectTriangle(tr Triangle) (TriangleHit, bool) {
+	e1, e2 := tr.B.Sub(tr.A), tr.C.Sub(tr.A)
+	p := r.Direction.Cross(e2)
+	det := e1.Dot(p)
+	if det == 0 {
+		return TriangleHit{}, false
+	}
+	inv := 1 / det
+	s := r.Origin.Sub(tr.A)
+	u := s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return TriangleHit{}, false
+	}
+	q := s.Cross(e1)
+	v := r.Direction.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return TriangleHit{}, false
+	}
+	t := e2.Dot(q) * inv
+	if t < 0 || math.IsInf(t, 0) {
+		return TriangleHit{}, false
+	}
+	return TriangleHit{T: t, Barycentric: Vec3{X: 1 - u - v, Y: u, Z: v}, FrontFace: det > 0}, true
+}
+
+// IntersectTriangleWatertight returns where the ray first meets the
+// triangle, using the watertight algorithm of Woop, Benthin, and Wald,
+// "Watertight Ray/Triangle Intersection" (2013). A ray through an edge or
+// vertex shared by several triangles is guaranteed to hit at least one
+// of them, at some cost in speed.
+func (r Ray) IntersectTriangleWatertight(tr Triangle) (TriangleHit, bool) {
+	d := [3]float64{r.Direction.X, r.Direction.Y, r.Direction.Z}
+	// shear so the ray runs along the z-axis, choosing the dominant
+	// axis as z and keeping the winding of the triangle
+	kz := 0
+	for k := 1; k < 3; k++ {
+		if math.Abs(d[k]) > math.Abs(d[kz]) {
+			kz = k
+		}
+	}
+	if d[kz] == 0 {
+		return TriangleHit{}, false
+	}
+	kx, ky := (kz+1)%3, (kz+2)%3
+	if d[kz] < 0 {
+		kx, ky = ky, kx
+	}
+	sx, sy, sz := d[kx]/d[kz], d[ky]/d[kz], 1/d[kz]
+
+	shear := func(p Point) (x, y, z float64) {
+		v := p.Sub(r.Origin)
+		c := [3]float64{v.X, v.Y, v.Z}
+		return c[kx] - sx*c[kz], c[ky] - sy*c[kz], sz * c[kz]
+	}
+	ax, ay, az := shear(tr.A)
+	bx, by, bz := shear(tr.B)
+	cx, cy, cz := shear(tr.C)
+
+	// scaled barycentric coordinates from the 2D edge functions
+	u := cx*by - cy*bx
+	v := ax*cy - ay*cx
+	w := bx*ay - by*ax
+	if (u < 0 || v < 0 || w < 0) && (u > 0 || v > 0 || w > 0) {
+		return TriangleHit{}, false
+	}
+	det := u + v + w
+	if det == 0 {
+		return TriangleHit{}, false
+	}
+	t := (u*az + v*bz + w*cz) / det
+	if t < 0 {
+		return TriangleHit{}, false
+	}
+	return TriangleHit{T: t, Barycentric: Vec3{X: u / det, Y: v / det, Z: w / det}, FrontFace: det > 0}, true
+}
+
+// Transform returns the ray transformed by the affine matrix m. The
+// direction is transformed but not renormalized, so a point at parameter
+// t on r maps to the point at parameter t on the result.
+func (r Ray) Transform(m Mat4) Ray {
+	return Ray{Origin: m.TransformPoint(r.Origin), Direction: m.TransformDirection(r.Direction)}
+}
+
+// TraverseGrid visits the cells of a grid with cells of the given size
+// that the ray passes through, in order from the origin, using the
+// method of Amanatides and Woo. For each cell it calls visit with the
+// parameters where the ray enters and leaves it, and it stops when visit
+// returns false. The first cell is entered at 0. The boundary parameters
+// are computed from the cell coordinates rather than accumulated, so they
+// do not drift along long rays. Where the ray passes exactly through an
+// edge or corner of the grid, it steps diagonally and skips the cells
+// that it only touches there. A ray with no direction visits only the
+// cell holding its origin, which it leaves at +∞. The cell size must be
+// positive.
+func (r Ray) TraverseGrid(cellSize float64, visit func(c Cell, enter, leave float64) bool) {
+	start := CellOf(r.Origin, cellSize)
+	c := [3]int{start.X, start.Y, start.Z}
+	o := [3]float64{r.Origin.X, r.Origin.Y, r.Origin.Z}
+	d := [3]float64{r.Direction.X, r.Direction.Y, r.Direction.Z}
+	var step [3]int
+	var next [3]float64
+	// boundary returns the parameter where the ray leaves cell c along axis i
+	boundary := func(i int) float64 {
+		if step[i] > 0 {
+			return (float64(c[i]+1)*cellSize - o[i]) / d[i]
+		}
+		return (float64(c[i])*cellSize - o[i]) / d[i]
+	}
+	for i := range d {
+		switch {
+		case d[i] > 0:
+			step[i] = 1
+		case d[i] < 0:
+			step[i] = -1
+		default:
+			next[i] = math.Inf(1)
+			continue
+		}
+		// an origin on a boundary, or rounded onto the wrong side of one,
+		// starts in the cell the ray heads into
+		for next[i] = boundary(i); next[i] <= 0; next[i] = boundary(i) {
+			c[i] += step[i]
+		}
+	}
+	for enter := 0.0; ; {
+		leave := math.Min(next[0], math.Min(next[1], next[2]))
+		if !visit(Cell{X: c[0], Y: c[1], Z: c[2]}, enter, leave) || math.IsInf(leave, 1) {
+			return
+		}
+		for i := range next {
+			if next[i] == leave {
+				c[i] += step[i]
+				next[i] = boundary(i)
+			}
+		}
+		enter = leave
+	}
+}
+
+// PickRay returns the world-space ray through the given normalized device
+// coordinates, starting on the near plane and pointing away from the
+// viewer. Window coordinates can be converted with Viewport.WindowToNDC.
+// It returns false if the view-projection transform is singular.
+func PickRay(ndc Vec2, view, projection Mat4, clip ClipSpace) (Ray, bool) {
+	inv, ok := projection.Mul(view).Inverse()
+	if !ok {
+		return Ray{}, false
+	}
+	// the midpoint depth is finite even when the far plane is at infinity
+	dn, df := clip.depths()
+	near := inv.TransformPoint(Point{X: ndc.X, Y: ndc.Y, Z: dn})
+	mid := inv.TransformPoint(Point{X: ndc.X, Y: ndc.Y, Z: (dn + df) / 2})
+	dir, ok := mid.Sub(near).Normalized()
+	if !ok {
+		return Ray{}, false
+	}
+	return Ray{Origin: near, Direction: dir}, true
+}
+
+// intersectAABB returns the parameter where the ray enters the box,
+// using the slab method. A ray starting inside the box returns 0.
+func (r Ray) intersectAABB(b AABB) (float64, bool) {
+	o := [3]float64{r.Origin.X, r.Origin.Y, r.Origin.Z}
+	d := [3]float64{r.Direction.X, r.Direction.Y, r.Direction.Z}
+	lo := [3]float64{b.Min.X, b.Min.Y, b.Min.Z}
+	hi := [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+	tmin, tmax := 0.0, math.Inf(1)
+	for i := range o {
+		if d[i] == 0 {
+			if o[i] < lo[i] || o[i] > hi[i] {
+				return 0, false
+			}
+			continue
+		}
+		t0, t1 := (lo[i]-o[i])/d[i], (hi[i]-o[i])/d[i]
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		tmin, tmax = math.Max(tmin, t0), math.Min(tmax, t1)
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+	return tmin, true
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestRay(t *testing.T) {
+	r := math3d.Ray{Origin: math3d.Point{X: 1, Y: 2, Z: 3}, Direction: math3d.NewVec3(0, 2, 0)}
+	if got, want := r.At(1.5), (math3d.Point{X: 1, Y: 5, Z: 3}); !got.ApproxEqual(want, 0) {
+		t.Errorf("At: want %v, got %v\n", want, got)
+	}
+
+	for _, tt := range []struct {
+		p, want math3d.Point
+	}{
+		{math3d.Point{X: 4, Y: 7, Z: 3}, math3d.Point{X: 1, Y: 7, Z: 3}},
+		{math3d.Point{X: 1, Y: 2, Z: 9}, math3d.Point{X: 1, Y: 2, Z: 3}},
+		{math3d.Point{X: 0, Y: -5, Z: 0}, math3d.Point{X: 1, Y: 2, Z: 3}},
+	} {
+		if got := r.ClosestPointTo(tt.p); !got.ApproxEqual(tt.want, 1e-12) {
+			t.Errorf("ClosestPointTo(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+	}
+
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(10, 0, 0)).Mul(math3d.Mat4FromRotationZ(0.7)).Mul(math3d.Mat4FromUniformScale(3))
+	tr := r.Transform(m)
+	for _, s := range []float64{0, 0.5, 2} {
+		if want, got := m.TransformPoint(r.At(s)), tr.At(s); !got.ApproxEqual(want, 1e-12) {
+			t.Errorf("Transform: At(%v): want %v, got %v\n", s, want, got)
+		}
+	}
+}
+
+func TestRayIntersectCylinder(t *testing.T) {
+	c := math3d.Cylinder{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 0, Y: 0, Z: 4}, Radius: 1}
+	for _, tt := range []struct {
+		name string
+		r    math3d.Ray
+		t    float64
+		ok   bool
+	}{
+		{"side", math3d.Ray{Origin: math3d.Point{X: -5, Y: 0, Z: 2}, Direction: math3d.NewVec3(1, 0, 0)}, 4, true},
+		{"scaled direction", math3d.Ray{Origin: math3d.Point{X: -5, Y: 0, Z: 2}, Direction: math3d.NewVec3(2, 0, 0)}, 2, true},
+		{"cap", math3d.Ray{Origin: math3d.Point{X: 0.5, Y: 0, Z: 10}, Direction: math3d.NewVec3(0, 0, -1)}, 6, true},
+		{"bottom cap", math3d.Ray{Origin: math3d.Point{X: 0, Y: 0.5, Z: -1}, Direction: math3d.NewVec3(0, 0, 1)}, 1, true},
+		{"inside", math3d.Ray{Origin: math3d.Point{X: 0, Y: 0, Z: 1}, Direction: math3d.NewVec3(0, 1, 0)}, 1, true},
+		{"above", math3d.Ray{Origin: math3d.Point{X: -5, Y: 0, Z: 4.5}, Direction: math3d.NewVec3(1, 0, 0)}, 0, false},
+		{"beside", math3d.Ray{Origin: math3d.Point{X: 2, Y: 0, Z: 10}, Direction: math3d.NewVec3(0, 0, -1)}, 0, false},
+		{"behind", math3d.Ray{Origin: math3d.Point{X: 5, Y: 0, Z: 2}, Direction: math3d.NewVec3(1, 0, 0)}, 0, false},
+	} {
+		got, ok := tt.r.IntersectCylinder(c)
+		if ok != tt.ok || (ok && math.Abs(got-tt.t) > 1e-12) {
+			t.Errorf("IntersectCylinder: %s: want %v %v, got %v %v\n", tt.name, tt.t, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestRayIntersectTorus(t *testing.T) {
+	tor := math3d.Torus{Axis: math3d.NewVec3(0, 0, 1), MajorRadius: 3, MinorRadius: 1}
+	for _, tt := range []struct {
+		name string
+		r    math3d.Ray
+		t    float64
+		ok   bool
+	}{
+		{"outer rim", math3d.Ray{Origin: math3d.Point{X: -10}, Direction: math3d.NewVec3(1, 0, 0)}, 6, true},
+		{"from the hole", math3d.Ray{Origin: math3d.Point{}, Direction: math3d.NewVec3(0, 2, 0)}, 1, true},
+		{"through the tube", math3d.Ray{Origin: math3d.Point{X: 3, Z: 10}, Direction: math3d.NewVec3(0, 0, -1)}, 9, true},
+		{"through the hole", math3d.Ray{Origin: math3d.Point{Z: 10}, Direction: math3d.NewVec3(0, 0, -1)}, 0, false},
+		{"above", math3d.Ray{Origin: math3d.Point{X: -10, Z: 1.5}, Direction: math3d.NewVec3(1, 0, 0)}, 0, false},
+	} {
+		got, ok := tt.r.IntersectTorus(tor)
+		if ok != tt.ok || (ok && math.Abs(got-tt.t) > 1e-9) {
+			t.Errorf("IntersectTorus: %s: want %v %v, got %v %v\n", tt.name, tt.t, tt.ok, got, ok)
+		}
+	}
+
+	// a tilted, offset torus: the hit must lie on the surface
+	tilted := math3d.Torus{Center: math3d.Point{X: 1, Y: -2, Z: 3}, Axis: math3d.NewVec3(1, 1, 2), MajorRadius: 2, MinorRadius: 0.5}
+	r := math3d.Ray{Origin: math3d.Point{X: 8, Y: 5, Z: -4}, Direction: tilted.Center.Add(math3d.NewVec3(1, -1, 0).Mul(math.Sqrt2)).Sub(math3d.Point{X: 8, Y: 5, Z: -4})}
+	got, ok := r.IntersectTorus(tilted)
+	if !ok {
+		t.Fatalf("IntersectTorus: tilted: want hit, got miss\n")
+	}
+	p := r.At(got).Sub(tilted.Center)
+	n := tilted.Axis.Normalize()
+	h := p.Dot(n)
+	radial := p.Sub(n.Mul(h)).Length()
+	if d := math.Hypot(radial-tilted.MajorRadius, h) - tilted.MinorRadius; math.Abs(d) > 1e-9 {
+		t.Errorf("IntersectTorus: tilted: want point on surface, got distance %v\n", d)
+	}
+}
+
+func TestRayIntersectTriangle(t *testing.T) {
+	tr := math3d.Triangle{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 4, Y: 0, Z: 0}, C: math3d.Point{X: 0, Y: 4, Z: 0}}
+	for _, tt := range []struct {
+		name  string
+		r     math3d.Ray
+		ok    bool
+		t     float64
+		bary  math3d.Vec3
+		front bool
+	}{
+		{"front", math3d.Ray{Origin: math3d.Point{X: 1, Y: 1, Z: 5}, Direction: math3d.NewVec3(0, 0, -1)}, true, 5, math3d.NewVec3(0.5, 0.25, 0.25), true},
+		{"back", math3d.Ray{Origin: math3d.Point{X: 1, Y: 2, Z: -2}, Direction: math3d.NewVec3(0, 0, 2)}, true, 1, math3d.NewVec3(0.25, 0.25, 0.5), false},
+		{"slanted", math3d.Ray{Origin: math3d.Point{X: 3, Y: 0, Z: 1}, Direction: math3d.NewVec3(-1, 1, -1)}, true, 1, math3d.NewVec3(0.25, 0.5, 0.25), true},
+		{"outside", math3d.Ray{Origin: math3d.Point{X: 3, Y: 3, Z: 5}, Direction: math3d.NewVec3(0, 0, -1)}, false, 0, math3d.Vec3{}, false},
+		{"behind", math3d.Ray{Origin: math3d.Point{X: 1, Y: 1, Z: 5}, Direction: math3d.NewVec3(0, 0, 1)}, false, 0, math3d.Vec3{}, false},
+		{"parallel", math3d.Ray{Origin: math3d.Point{X: -1, Y: 1, Z: 0}, Direction: math3d.NewVec3(1, 0, 0)}, false, 0, math3d.Vec3{}, false},
+	} {
+		for _, intersect := range []struct {
+			name string
+			f    func(math3d.Triangle) (math3d.TriangleHit, bool)
+		}{
+			{"IntersectTriangle", tt.r.IntersectTriangle},
+			{"IntersectTriangleWatertight", tt.r.IntersectTriangleWatertight},
+		} {
+			hit, ok := intersect.f(tr)
+			if ok != tt.ok {
+				t.Errorf("%s: %s: want %v, got %v\n", intersect.name, tt.name, tt.ok, ok)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if math.Abs(hit.T-tt.t) > 1e-12 || !hit.Barycentric.ApproxEqual(tt.bary, 1e-12) || hit.FrontFace != tt.front {
+				t.Errorf("%s: %s: want %v %v %v, got %+v\n", intersect.name, tt.name, tt.t, tt.bary, tt.front, hit)
+			}
+			if p := tt.r.At(hit.T); !p.ApproxEqual(tr.FromBarycentric(hit.Barycentric), 1e-12) {
+				t.Errorf("%s: %s: want hit point %v, got %v\n", intersect.name, tt.name, tr.FromBarycentric(hit.Barycentric), p)
+			}
+		}
+	}
+
+	// rays through the shared vertex and edges of a fan of triangles
+	// must hit at least one of them
+	center := math3d.Point{X: 0.1, Y: 0.2, Z: 0.3}
+	var fan []math3d.Triangle
+	for i := 0; i < 7; i++ {
+		a0, a1 := float64(i)*2*math.Pi/7, float64(i+1)*2*math.Pi/7
+		fan = append(fan, math3d.Triangle{
+			A: center,
+			B: center.Add(math3d.NewVec3(math.Cos(a0), math.Sin(a0), 0.1*math.Sin(3*a0))),
+			C: center.Add(math3d.NewVec3(math.Cos(a1), math.Sin(a1), 0.1*math.Sin(3*a1))),
+		})
+	}
+	origin := math3d.Point{X: 0.37, Y: -0.71, Z: 3.3}
+	var targets []math3d.Point
+	targets = append(targets, center)
+	for _, tr := range fan {
+		for _, s := range []float64{0.1, 1.0 / 3, 0.5, 0.9} {
+			targets = append(targets, tr.A.Lerp(tr.B, s))
+		}
+	}
+	for _, target := range targets {
+		r := math3d.Ray{Origin: origin, Direction: target.Sub(origin)}
+		hits := 0
+		for _, tr := range fan {
+			if _, ok := r.IntersectTriangleWatertight(tr); ok {
+				hits++
+			}
+		}
+		if hits == 0 {
+			t.Errorf("IntersectTriangleWatertight: through %v: want a hit, got none\n", target)
+		}
+	}
+}
+
+func TestRayIntersectSphere(t *testing.T) {
+	s := math3d.Sphere{Center: math3d.Point{X: 0, Y: 0, Z: -10}, Radius: 2}
+	for _, tt := range []struct {
+		name   string
+		r      math3d.Ray
+		ok     bool
+		t0, t1 float64
+	}{
+		{"through", math3d.Ray{Direction: math3d.NewVec3(0, 0, -1)}, true, 8, 12},
+		{"scaled direction", math3d.Ray{Direction: math3d.NewVec3(0, 0, -4)}, true, 2, 3},
+		{"grazing", math3d.Ray{Origin: math3d.Point{X: 2}, Direction: math3d.NewVec3(0, 0, -1)}, true, 10, 10},
+		{"inside", math3d.Ray{Origin: math3d.Point{Z: -10}, Direction: math3d.NewVec3(0, 1, 0)}, true, -2, 2},
+		{"behind", math3d.Ray{Direction: math3d.NewVec3(0, 0, 1)}, false, 0, 0},
+		{"miss", math3d.Ray{Origin: math3d.Point{X: 2.01}, Direction: math3d.NewVec3(0, 0, -1)}, false, 0, 0},
+	} {
+		enter, leave, ok := tt.r.IntersectSphere(s)
+		if ok != tt.ok {
+			t.Errorf("IntersectSphere: %s: want %v, got %v\n", tt.name, tt.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if math.Abs(enter.T-tt.t0) > 1e-12 || math.Abs(leave.T-tt.t1) > 1e-12 {
+			t.Errorf("IntersectSphere: %s: want %v %v, got %v %v\n", tt.name, tt.t0, tt.t1, enter.T, leave.T)
+		}
+		for _, h := range []math3d.RayHit{enter, leave} {
+			if d := h.Point.Distance(s.Center); math.Abs(d-s.Radius) > 1e-12 {
+				t.Errorf("IntersectSphere: %s: want point on surface, got distance %v\n", tt.name, d)
+			}
+			if !h.Normal.ApproxEqual(h.Point.Sub(s.Center).Div(s.Radius), 1e-12) {
+				t.Errorf("IntersectSphere: %s: want outward normal, got %v\n", tt.name, h.Normal)
+			}
+		}
+	}
+
+	// a tiny, distant sphere is still hit accurately
+	far := math3d.Sphere{Center: math3d.Point{X: 1e-3, Z: -1e6}, Radius: 2e-3}
+	enter, _, ok := math3d.Ray{Direction: math3d.NewVec3(0, 0, -1)}.IntersectSphere(far)
+	if want := 1e6 - math.Sqrt(3)*1e-3; !ok || math.Abs(enter.T-want) > 1e-9 {
+		t.Errorf("IntersectSphere: distant: want %v, got %v %v\n", want, enter.T, ok)
+	}
+}
+
+func TestRayIntersectPlane(t *testing.T) {
+	pl := math3d.NewPlane(math3d.NewVec3(0, 2, 0), -4)
+	for _, tt := range []struct {
+		name string
+		r    math3d.Ray
+		ok   bool
+		t    float64
+	}{
+		{"down", math3d.Ray{Origin: math3d.Point{X: 1, Y: 5, Z: 1}, Direction: math3d.NewVec3(0, -1, 0)}, true, 3},
+		{"slanted", math3d.Ray{Origin: math3d.Point{Y: 0}, Direction: math3d.NewVec3(1, 1, 0)}, true, 2},
+		{"behind", math3d.Ray{Origin: math3d.Point{Y: 5}, Direction: math3d.NewVec3(0, 1, 0)}, false, 0},
+		{"parallel", math3d.Ray{Origin: math3d.Point{Y: 2}, Direction: math3d.NewVec3(1, 0, 0)}, false, 0},
+	} {
+		hit, ok := tt.r.IntersectPlane(pl)
+		if ok != tt.ok || (ok && math.Abs(hit.T-tt.t) > 1e-12) {
+			t.Errorf("IntersectPlane: %s: want %v %v, got %v %v\n", tt.name, tt.t, tt.ok, hit.T, ok)
+			continue
+		}
+		if ok && (math.Abs(hit.Point.Y-2) > 1e-12 || hit.Normal != math3d.NewVec3(0, 1, 0)) {
+			t.Errorf("IntersectPlane: %s: want point on y = 2 with normal +y, got %+v\n", tt.name, hit)
+		}
+	}
+}
+
+func TestRayTraverseGrid(t *testing.T) {
+	type step struct {
+		c            math3d.Cell
+		enter, leave float64
+	}
+	walk := func(r math3d.Ray, size float64, n int) []step {
+		var steps []step
+		r.TraverseGrid(size, func(c math3d.Cell, enter, leave float64) bool {
+			steps = append(steps, step{c, enter, leave})
+			return len(steps) < n
+		})
+		return steps
+	}
+	for _, tt := range []struct {
+		name string
+		r    math3d.Ray
+		size float64
+		want []step
+	}{
+		{"along x", math3d.Ray{Origin: math3d.Point{X: 0.5, Y: 0.5, Z: 0.5}, Direction: math3d.NewVec3(1, 0, 0)}, 1,
+			[]step{{math3d.Cell{}, 0, 0.5}, {math3d.Cell{X: 1}, 0.5, 1.5}, {math3d.Cell{X: 2}, 1.5, 2.5}}},
+		{"through corners", math3d.Ray{Origin: math3d.Point{X: 1, Y: 1, Z: 1}, Direction: math3d.NewVec3(-2, -2, -2)}, 2,
+			[]step{{math3d.Cell{}, 0, 0.5}, {math3d.Cell{X: -1, Y: -1, Z: -1}, 0.5, 1.5}, {math3d.Cell{X: -2, Y: -2, Z: -2}, 1.5, 2.5}}},
+		{"backward from a boundary", math3d.Ray{Origin: math3d.Point{X: 1, Y: 0.5, Z: 0.5}, Direction: math3d.NewVec3(-1, 0.5, 0)}, 1,
+			[]step{{math3d.Cell{}, 0, 1}, {math3d.Cell{X: -1, Y: 1}, 1, 2}, {math3d.Cell{X: -2, Y: 1}, 2, 3}}},
+		{"no direction", math3d.Ray{Origin: math3d.Point{X: -0.5, Y: 3, Z: 0}}, 1,
+			[]step{{math3d.Cell{X: -1, Y: 3}, 0, math.Inf(1)}}},
+	} {
+		got := walk(tt.r, tt.size, len(tt.want))
+		if len(got) != len(tt.want) {
+			t.Errorf("TraverseGrid: %s: want %v, got %v\n", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i].c != tt.want[i].c || math.Abs(got[i].enter-tt.want[i].enter) > 1e-12 || math.Abs(got[i].leave-tt.want[i].leave) > 1e-12 {
+				t.Errorf("TraverseGrid: %s: want %v, got %v\n", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+
+	// every cell holds the ray over its interval, the intervals meet, and
+	// each step crosses a single face
+	r := math3d.Ray{Origin: math3d.Point{X: 0.3127, Y: -1.7093, Z: 2.2241}, Direction: math3d.NewVec3(0.8137, 0.3519, -0.6071)}
+	steps := walk(r, 0.25, 200)
+	abs := func(n int) int {
+		if n < 0 {
+			return -n
+		}
+		return n
+	}
+	for i, s := range steps {
+		if c := math3d.CellOf(r.At((s.enter+s.leave)/2), 0.25); c != s.c || !(s.enter < s.leave) {
+			t.Errorf("TraverseGrid: step %d: want cell %v over a positive interval, got %v\n", i, c, s)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := steps[i-1]
+		d := abs(s.c.X-prev.c.X) + abs(s.c.Y-prev.c.Y) + abs(s.c.Z-prev.c.Z)
+		if prev.leave != s.enter || d != 1 {
+			t.Errorf("TraverseGrid: step %d: want a neighbor of %v entered at %v, got %v\n", i, prev.c, prev.leave, s)
+		}
+	}
+	if end := steps[len(steps)-1].leave; len(steps) != 200 || end < 10 {
+		t.Errorf("TraverseGrid: want 200 cells covering the ray, got %d ending at %v\n", len(steps), end)
+	}
+}
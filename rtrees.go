@@ -0,0 +1,358 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"sort"
+)
+
+// RTree is a dynamic index of bounding boxes for overlap and containment
+// queries, following Guttman's R-tree with quadratic splits. Boxes are
+// identified by integers chosen by the caller. Where the classic tree
+// compares volumes, this one compares surface areas, which stay useful
+// for flat boxes such as map features in the plane z = 0.
+type RTree struct {
+	root  *rtreeNode
+	items map[int]AABB
+}
+
+const (
+	// rtreeMaxEntries is the most entries a node holds before it splits.
+	rtreeMaxEntries = 8
+	// rtreeMinEntries is the fewest entries a node other than the root
+	// holds; emptier nodes are dissolved and their items reinserted.
+	rtreeMinEntries = 3
+)
+
+// rtreeNode is a node of the tree. Leaves have height 0 and entries
+// with ids; inner nodes have entries with children one level lower.
+type rtreeNode struct {
+	height  int
+	bounds  AABB
+	entries []rtreeEntry
+}
+
+type rtreeEntry struct {
+	bounds AABB
+	child  *rtreeNode
+	id     int
+}
+
+// NewRTree returns an empty R-tree.
+func NewRTree() *RTree {
+	return &RTree{root: &rtreeNode{bounds: EmptyAABB()}, items: map[int]AABB{}}
+}
+
+// RTreeFromAABBs builds an R-tree over the boxes in one pass using the
+// sort-tile-recursive packing of Leutenegger et al., which gives fuller
+// nodes and faster queries than inserting the boxes one at a time. Each
+// box is identified by its index in the slice.
+func RTreeFromAABBs(boxes []AABB) *RTree {
+	t := NewRTree()
+	if len(boxes) == 0 {
+		return t
+	}
+	entries := make([]rtreeEntry, len(boxes))
+	for i, b := range boxes {
+		entries[i] = rtreeEntry{bounds: b, id: i}
+		t.items[i] = b
+	}
+	height := 0
+	for {
+		nodes := packSTR(entries, height)
+		if len(nodes) == 1 {
+			t.root = nodes[0]
+			return t
+		}
+		entries = make([]rtreeEntry, len(nodes))
+		for i, n := range nodes {
+			entries[i] = rtreeEntry{bounds: n.bounds, child: n}
+		}
+		height++
+	}
+}
+
+// Insert adds the box with the given id, replacing any box with the same
+// id.
+func (t *RTree) Insert(id int, b AABB) {
+	t.Remove(id)
+	t.items[id] = b
+	t.insert(rtreeEntry{bounds: b, id: id})
+}
+
+// Len returns the number of boxes in the tree.
+func (t *RTree) Len() int {
+	return len(t.items)
+}
+
+// QueryAABB returns the ids of the boxes that overlap or touch b, in no
+// particular order.
+func (t *RTree) QueryAABB(b AABB) []int {
+	var ids []int
+	t.root.search(func(box AABB) bool { return box.IntersectsAABB(b) }, func(e rtreeEntry) {
+		if e.bounds.IntersectsAABB(b) {
+			ids = append(ids, e.id)
+		}
+	})
+	return ids
+}
+
+// QueryContained returns the ids of the boxes that lie entirely inside
+// b, in no particular order.
+func (t *RTree) QueryContained(b AABB) []int {
+	var ids []int
+	t.root.search(func(box AABB) bool { return box.IntersectsAABB(b) }, func(e rtreeEntry) {
+		if b.ContainsPoint(e.bounds.Min) && b.ContainsPoint(e.bounds.Max) {
+			ids = append(ids, e.id)
+		}
+	})
+	return ids
+}
+
+// QueryContaining returns the ids of the boxes that hold the point p,
+// in no particular order.
+func (t *RTree) QueryContaining(p Point) []int {
+	var ids []int
+	t.root.search(func(box AABB) bool { return box.ContainsPoint(p) }, func(e rtreeEntry) {
+		if e.bounds.ContainsPoint(p) {
+			ids = append(ids, e.id)
+		}
+	})
+	return ids
+}
+
+// Remove deletes the box with the given id. It returns false if there is
+// no such box.
+func (t *RTree) Remove(id int) bool {
+	b, ok := t.items[id]
+	if !ok {
+		return false
+	}
+	delete(t.items, id)
+	var orphans []rtreeEntry
+	t.root.remove(id, b, &orphans)
+	for t.root.height > 0 && len(t.root.entries) == 1 {
+		t.root = t.root.entries[0].child
+	}
+	if len(t.root.entries) == 0 {
+		t.root = &rtreeNode{bounds: EmptyAABB()}
+	}
+	for _, e := range orphans {
+		t.insert(e)
+	}
+	return true
+}
+
+// insert adds a leaf entry, growing a new root when the old one splits.
+func (t *RTree) insert(e rtreeEntry) {
+	if split := t.root.insert(e); split != nil {
+		old := t.root
+		t.root = &rtreeNode{height: old.height + 1, entries: []rtreeEntry{
+			{bounds: old.bounds, child: old},
+			{bounds: split.bounds, child: split},
+		}}
+		t.root.recompute()
+	}
+}
+
+// insert adds a leaf entry under n, returning the new sibling of n if n
+// had to split.
+func (n *rtreeNode) insert(e rtreeEntry) *rtreeNode {
+	if n.height == 0 {
+		n.entries = append(n.entries, e)
+	} else {
+		// descend into the child that grows least, then the smallest
+		best, bestGrowth, bestArea := 0, math.Inf(1), math.Inf(1)
+		for i, c := range n.entries {
+			area := c.bounds.SurfaceArea()
+			growth := c.bounds.Union(e.bounds).SurfaceArea() - area
+			if growth < bestGrowth || (growth == bestGrowth && area < bestArea) {
+				best, bestGrowth, bestArea = i, growth, area
+			}
+		}
+		child := n.entries[best].child
+		if split := child.insert(e); split != nil {
+			n.entries = append(n.entries, rtreeEntry{bounds: split.bounds, child: split})
+		}
+		n.entries[best].bounds = child.bounds
+	}
+	if len(n.entries) > rtreeMaxEntries {
+		split := n.split()
+		n.recompute()
+		return split
+	}
+	n.bounds = n.bounds.Union(e.bounds)
+	return nil
+}
+
+// split moves about half the entries of n into a new node using
+// Guttman's quadratic method and returns the new node.
+func (n *rtreeNode) split() *rtreeNode {
+	entries := n.entries
+	// the seeds are the pair that would waste the most space together
+	s1, s2, worst := 0, 1, math.Inf(-1)
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			waste := entries[i].bounds.Union(entries[j].bounds).SurfaceArea() - entries[i].bounds.SurfaceArea() - entries[j].bounds.SurfaceArea()
+			if waste > worst {
+				s1, s2, worst = i, j, waste
+			}
+		}
+	}
+	a := &rtreeNode{height: n.height, bounds: entries[s1].bounds, entries: []rtreeEntry{entries[s1]}}
+	b := &rtreeNode{height: n.height, bounds: entries[s2].bounds, entries: []rtreeEntry{entries[s2]}}
+	rest := make([]rtreeEntry, 0, len(entries)-2)
+	for i, e := range entries {
+		if i != s1 && i != s2 {
+			rest = append(rest, e)
+		}
+	}
+	for len(rest) > 0 {
+		// fill a group that needs every remaining entry to reach the minimum
+		if len(a.entries)+len(rest) == rtreeMinEntries || len(b.entries)+len(rest) == rtreeMinEntries {
+			g := a
+			if len(b.entries) < len(a.entries) {
+				g = b
+			}
+			for _, e := range rest {
+				g.entries = append(g.entries, e)
+				g.bounds = g.bounds.Union(e.bounds)
+			}
+			break
+		}
+		// otherwise place the entry with the strongest preference
+		pick, pickDiff := 0, math.Inf(-1)
+		var da, db float64
+		for i, e := range rest {
+			ga := a.bounds.Union(e.bounds).SurfaceArea() - a.bounds.SurfaceArea()
+			gb := b.bounds.Union(e.bounds).SurfaceArea() - b.bounds.SurfaceArea()
+			if diff := math.Abs(ga - gb); diff > pickDiff {
+				pick, pickDiff, da, db = i, diff, ga, gb
+			}
+		}
+		e := rest[pick]
+		rest = append(rest[:pick], rest[pick+1:]...)
+		g := a
+		if db < da || (db == da && len(b.entries) < len(a.entries)) {
+			g = b
+		}
+		g.entries = append(g.entries, e)
+		g.bounds = g.bounds.Union(e.bounds)
+	}
+	n.entries = a.entries
+	return b
+}
+
+// remove deletes the leaf entry with the id and bounds b from the
+// subtree, adding the leaf entries of any node left too empty to
+// orphans. It returns false if the entry is not in the subtree.
+func (n *rtreeNode) remove(id int, b AABB, orphans *[]rtreeEntry) bool {
+	if n.height == 0 {
+		for i, e := range n.entries {
+			if e.id == id {
+				n.entries = append(n.entries[:i], n.entries[i+1:]...)
+				n.recompute()
+				return true
+			}
+		}
+		return false
+	}
+	for i, e := range n.entries {
+		if !e.bounds.ContainsPoint(b.Min) || !e.bounds.ContainsPoint(b.Max) || !e.child.remove(id, b, orphans) {
+			continue
+		}
+		if len(e.child.entries) < rtreeMinEntries {
+			e.child.search(func(AABB) bool { return true }, func(leaf rtreeEntry) {
+				*orphans = append(*orphans, leaf)
+			})
+			n.entries = append(n.entries[:i], n.entries[i+1:]...)
+		} else {
+			n.entries[i].bounds = e.child.bounds
+		}
+		n.recompute()
+		return true
+	}
+	return false
+}
+
+// recompute sets the bounds of n from its entries.
+func (n *rtreeNode) recompute() {
+	n.bounds = EmptyAABB()
+	for _, e := range n.entries {
+		n.bounds = n.bounds.Union(e.bounds)
+	}
+}
+
+// search calls found for each leaf entry under n, descending only into
+// nodes whose bounds satisfy descend.
+func (n *rtreeNode) search(descend func(AABB) bool, found func(rtreeEntry)) {
+	for _, e := range n.entries {
+		if n.height == 0 {
+			found(e)
+		} else if descend(e.bounds) {
+			e.child.search(descend, found)
+		}
+	}
+}
+
+// packSTR groups the entries into full nodes of the given height by
+// sorting them into slabs along x, then y, then z.
+func packSTR(entries []rtreeEntry, height int) []*rtreeNode {
+	leaves := (len(entries) + rtreeMaxEntries - 1) / rtreeMaxEntries
+	slabs := int(math.Ceil(math.Cbrt(float64(leaves))))
+	var nodes []*rtreeNode
+	var tile func(entries []rtreeEntry, axis int)
+	tile = func(entries []rtreeEntry, axis int) {
+		sort.Slice(entries, func(i, j int) bool {
+			return pointAxis(entries[i].bounds.Center(), axis) < pointAxis(entries[j].bounds.Center(), axis)
+		})
+		if axis == 2 {
+			for lo := 0; lo < len(entries); lo += rtreeMaxEntries {
+				hi := lo + rtreeMaxEntries
+				if hi > len(entries) {
+					hi = len(entries)
+				}
+				n := &rtreeNode{height: height, entries: append([]rtreeEntry(nil), entries[lo:hi]...)}
+				n.recompute()
+				nodes = append(nodes, n)
+			}
+			return
+		}
+		// each slab holds enough entries for slabs^(2−axis) full nodes
+		size := rtreeMaxEntries
+		for i := axis; i < 2; i++ {
+			size *= slabs
+		}
+		for lo := 0; lo < len(entries); lo += size {
+			hi := lo + size
+			if hi > len(entries) {
+				hi = len(entries)
+			}
+			tile(entries[lo:hi], axis+1)
+		}
+	}
+	tile(entries, 0)
+	return nodes
+}
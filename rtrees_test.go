@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestRTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(840))
+	randomBox := func(flat bool) math3d.AABB {
+		c := math3d.Point{X: rng.Float64()*100 - 50, Y: rng.Float64()*100 - 50, Z: rng.Float64()*100 - 50}
+		s := math3d.NewVec3(rng.Float64()*5, rng.Float64()*5, rng.Float64()*5)
+		if flat {
+			c.Z, s.Z = 0, 0
+		}
+		return math3d.AABB{Min: c, Max: c.Add(s)}
+	}
+	for _, flat := range []bool{false, true} {
+		boxes := make([]math3d.AABB, 2000)
+		for i := range boxes {
+			boxes[i] = randomBox(flat)
+		}
+		bulk := math3d.RTreeFromAABBs(boxes)
+		dynamic := math3d.NewRTree()
+		for i, b := range boxes {
+			dynamic.Insert(i, b)
+		}
+		live := map[int]math3d.AABB{}
+		for i, b := range boxes {
+			live[i] = b
+		}
+		// remove and move some boxes in both trees
+		for i := 0; i < len(boxes); i += 3 {
+			for _, tree := range []*math3d.RTree{bulk, dynamic} {
+				if !tree.Remove(i) {
+					t.Errorf("Remove(%d): want true, got false\n", i)
+				}
+			}
+			delete(live, i)
+		}
+		for i := 1; i < len(boxes); i += 7 {
+			b := randomBox(flat)
+			bulk.Insert(i, b)
+			dynamic.Insert(i, b)
+			live[i] = b
+		}
+
+		for name, tree := range map[string]*math3d.RTree{"bulk": bulk, "dynamic": dynamic} {
+			if tree.Len() != len(live) {
+				t.Errorf("%s: Len: want %d, got %d\n", name, len(live), tree.Len())
+			}
+			for q := 0; q < 20; q++ {
+				query := randomBox(flat).Expand(10)
+				p := query.Center()
+				var overlap, contained, containing []int
+				for id, b := range live {
+					if b.IntersectsAABB(query) {
+						overlap = append(overlap, id)
+					}
+					if query.ContainsPoint(b.Min) && query.ContainsPoint(b.Max) {
+						contained = append(contained, id)
+					}
+					if b.ContainsPoint(p) {
+						containing = append(containing, id)
+					}
+				}
+				for _, tt := range []struct {
+					query     string
+					got, want []int
+				}{
+					{"QueryAABB", tree.QueryAABB(query), overlap},
+					{"QueryContained", tree.QueryContained(query), contained},
+					{"QueryContaining", tree.QueryContaining(p), containing},
+				} {
+					sort.Ints(tt.got)
+					sort.Ints(tt.want)
+					if !equalInts(tt.got, tt.want) {
+						t.Errorf("%s: %s(%v): flat %v: want %v, got %v\n", name, tt.query, query, flat, tt.want, tt.got)
+					}
+				}
+			}
+		}
+	}
+
+	tree := math3d.NewRTree()
+	tree.Insert(7, math3d.AABB{Max: math3d.Point{X: 1, Y: 1, Z: 1}})
+	if !tree.Remove(7) || tree.Remove(7) || tree.Len() != 0 || len(tree.QueryContaining(math3d.Point{})) != 0 {
+		t.Errorf("Remove: want an empty tree\n")
+	}
+}
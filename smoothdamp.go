@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// The SmoothDamp functions move a value towards a target with the motion
+// of a critically damped spring, which approaches the target as quickly
+// as possible without overshooting it. The caller keeps the velocity
+// between calls; it should start at zero. smoothTime is roughly the
+// time taken to reach the target, and dt is the time since the last
+// call. The result is frame-rate independent because the spring is
+// solved in closed form rather than stepped.
+
+// SmoothDamp returns current moved towards target over the timestep dt
+// and updates velocity.
+func SmoothDamp(current, target float64, velocity *float64, smoothTime, dt float64) float64 {
+	vel := Vector{*velocity}
+	out := smoothDamp(Vector{current}, Vector{target}, vel, smoothTime, dt)
+	*velocity = vel[0]
+	return out[0]
+}
+
+// SmoothDamp returns v moved towards target over the timestep dt and
+// updates velocity.
+func (v Vec2) SmoothDamp(target Vec2, velocity *Vec2, smoothTime, dt float64) Vec2 {
+	vel := velocity.toVector()
+	out := smoothDamp(v.toVector(), target.toVector(), vel, smoothTime, dt)
+	*velocity = vel.toVec2()
+	return out.toVec2()
+}
+
+// SmoothDamp returns v moved towards target over the timestep dt and
+// updates velocity.
+func (v Vec3) SmoothDamp(target Vec3, velocity *Vec3, smoothTime, dt float64) Vec3 {
+	vel := velocity.toVector()
+	out := smoothDamp(v.toVector(), target.toVector(), vel, smoothTime, dt)
+	*velocity = vel.toVec3()
+	return out.toVec3()
+}
+
+// SmoothDamp returns the unit quaternion q turned towards target over
+// the timestep dt and updates velocity, the rate of change of the
+// quaternion components. The components are damped together and the
+// result renormalized, taking the shorter path between the rotations.
+func (q Quaternion) SmoothDamp(target Quaternion, velocity *Quaternion, smoothTime, dt float64) Quaternion {
+	if q.Dot(target) < 0 {
+		target = target.MulScalar(-1)
+	}
+	vel := Vec4(*velocity).toVector()
+	out := Quaternion(smoothDamp(Vec4(q).toVector(), Vec4(target).toVector(), vel, smoothTime, dt).toVec4()).Normalize()
+	// keep the velocity tangent to the unit sphere at the result
+	v := Quaternion(vel.toVec4())
+	*velocity = v.Sub(out.MulScalar(v.Dot(out)))
+	return out
+}
+
+// smoothDamp implements the SmoothDamp functions component-wise,
+// updating velocity in place. It uses the polynomial approximation of
+// exp(−x) from Game Programming Gems 4, §1.10. A non-positive smoothTime
+// snaps to the target and a non-positive dt leaves current unchanged.
+func smoothDamp(current, target, velocity Vector, smoothTime, dt float64) Vector {
+	out := make(Vector, len(current))
+	if dt <= 0 {
+		copy(out, current)
+		return out
+	}
+	if smoothTime <= 0 {
+		copy(out, target)
+		for i := range velocity {
+			velocity[i] = 0
+		}
+		return out
+	}
+	omega := 2 / smoothTime
+	x := omega * dt
+	decay := 1 / (1 + x + 0.48*x*x + 0.235*x*x*x)
+	var overshoot float64
+	for i := range current {
+		change := current[i] - target[i]
+		temp := (velocity[i] + omega*change) * dt
+		velocity[i] = (velocity[i] - omega*temp) * decay
+		out[i] = target[i] + (change+temp)*decay
+		overshoot += (target[i] - current[i]) * (out[i] - target[i])
+	}
+	if overshoot > 0 {
+		copy(out, target)
+		for i := range velocity {
+			velocity[i] = 0
+		}
+	}
+	return out
+}
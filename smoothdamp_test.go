@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestSmoothDamp(t *testing.T) {
+	// the value approaches the target monotonically and settles on it
+	x, vel := 0.0, 0.0
+	for i := 0; i < 600; i++ {
+		next := math3d.SmoothDamp(x, 10, &vel, 0.5, 1.0/60)
+		if next < x || next > 10 {
+			t.Fatalf("SmoothDamp: step %d: want monotonic approach to 10, got %v after %v\n", i, next, x)
+		}
+		x = next
+	}
+	if math.Abs(x-10) > 1e-6 || math.Abs(vel) > 1e-6 {
+		t.Errorf("SmoothDamp: want 10 at rest, got %v moving at %v\n", x, vel)
+	}
+
+	// the motion does not depend much on the frame rate
+	a, va := 0.0, 0.0
+	b, vb := 0.0, 0.0
+	for i := 0; i < 30; i++ {
+		a = math3d.SmoothDamp(a, 1, &va, 0.3, 1.0/30)
+	}
+	for i := 0; i < 120; i++ {
+		b = math3d.SmoothDamp(b, 1, &vb, 0.3, 1.0/120)
+	}
+	if math.Abs(a-b) > 1e-3 {
+		t.Errorf("SmoothDamp: frame rate: want %v, got %v\n", a, b)
+	}
+
+	if got := math3d.SmoothDamp(3, 5, &vel, 0, 0.1); got != 5 || vel != 0 {
+		t.Errorf("SmoothDamp: zero smoothTime: want 5 at rest, got %v moving at %v\n", got, vel)
+	}
+
+	v, target, vv := math3d.NewVec3(0, 0, 0), math3d.NewVec3(1, -2, 3), math3d.Vec3{}
+	for i := 0; i < 600; i++ {
+		v = v.SmoothDamp(target, &vv, 0.5, 1.0/60)
+	}
+	if !v.ApproxEqual(target, 1e-6) {
+		t.Errorf("Vec3.SmoothDamp: want %v, got %v\n", target, v)
+	}
+
+	w, wt, wv := math3d.NewVec2(4, 4), math3d.NewVec2(-1, 2), math3d.Vec2{}
+	for i := 0; i < 600; i++ {
+		w = w.SmoothDamp(wt, &wv, 0.5, 1.0/60)
+	}
+	if !w.ApproxEqual(wt, 1e-6) {
+		t.Errorf("Vec2.SmoothDamp: want %v, got %v\n", wt, w)
+	}
+
+	q, qt, qv := math3d.IdentityQuaternion(), math3d.QuaternionFromAxisAngle(math3d.NewVec3(1, 1, 0), 2.5).MulScalar(-1), math3d.Quaternion{}
+	for i := 0; i < 600; i++ {
+		q = q.SmoothDamp(qt, &qv, 0.5, 1.0/60)
+		if n := q.Length(); math.Abs(n-1) > 1e-12 {
+			t.Fatalf("Quaternion.SmoothDamp: want unit quaternion, got length %v\n", n)
+		}
+	}
+	if want := qt.MulScalar(-1); !q.ApproxEqual(want, 1e-6) {
+		t.Errorf("Quaternion.SmoothDamp: want %v, got %v\n", want, q)
+	}
+}
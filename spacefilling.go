@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Space-filling curves map the cells of a 3D grid to positions along a
+// line so that cells near each other in space tend to be near each
+// other on the line. Sorting points by their keys clusters them for
+// cache-friendly traversal and for building trees bottom up. The keys
+// hold 21 bits per axis in a uint64, so each coordinate must be less
+// than 2²¹; use QuantizePoint to bring points into that range.
+
+// curveBits is the number of bits per axis in a curve key.
+const curveBits = 21
+
+// QuantizePoint maps p to integer grid coordinates in [0, 2²¹) by
+// dividing the bounds into 2²¹ cells along each axis. Points outside the
+// bounds are clamped to the nearest cell. An axis along which the bounds
+// are flat maps to 0.
+func QuantizePoint(p Point, bounds AABB) (x, y, z uint32) {
+	const cells = 1 << curveBits
+	q := func(v, lo, hi float64) uint32 {
+		if !(hi > lo) {
+			return 0
+		}
+		return uint32(Clamp(math.Floor((v-lo)/(hi-lo)*cells), 0, cells-1))
+	}
+	return q(p.X, bounds.Min.X, bounds.Max.X), q(p.Y, bounds.Min.Y, bounds.Max.Y), q(p.Z, bounds.Min.Z, bounds.Max.Z)
+}
+
+// EncodeMorton3D returns the Morton code (Z-order key) of the grid
+// coordinates, which interleaves their bits with x in the lowest
+// position. Only the low 21 bits of each coordinate are used.
+func EncodeMorton3D(x, y, z uint32) uint64 {
+	return spreadBits(x) | spreadBits(y)<<1 | spreadBits(z)<<2
+}
+
+// DecodeMorton3D returns the grid coordinates of the Morton code.
+func DecodeMorton3D(code uint64) (x, y, z uint32) {
+	return compactBits(code), compactBits(code >> 1), compactBits(code >> 2)
+}
+
+// EncodeHilbert3D returns the position of the grid coordinates along a
+// Hilbert curve through the 2²¹ × 2²¹ × 2²¹ grid, using Skilling's
+// method from "Programming the Hilbert curve" (2004). Unlike the Morton
+// order, consecutive positions are always adjacent cells, which keeps
+// clusters tighter at some extra cost to compute. Only the low 21 bits
+// of each coordinate are used.
+func EncodeHilbert3D(x, y, z uint32) uint64 {
+	const mask = 1<<curveBits - 1
+	v := [3]uint32{x & mask, y & mask, z & mask}
+	// undo the rotations and reflections of each level
+	for q := uint32(1) << (curveBits - 1); q > 1; q >>= 1 {
+		p := q - 1
+		for i := range v {
+			if v[i]&q != 0 {
+				v[0] ^= p
+			} else {
+				t := (v[0] ^ v[i]) & p
+				v[0] ^= t
+				v[i] ^= t
+			}
+		}
+	}
+	// Gray encode
+	v[1] ^= v[0]
+	v[2] ^= v[1]
+	var t uint32
+	for q := uint32(1) << (curveBits - 1); q > 1; q >>= 1 {
+		if v[2]&q != 0 {
+			t ^= q - 1
+		}
+	}
+	for i := range v {
+		v[i] ^= t
+	}
+	// interleave with the first axis most significant
+	return EncodeMorton3D(v[2], v[1], v[0])
+}
+
+// DecodeHilbert3D returns the grid coordinates at the position along
+// the Hilbert curve of EncodeHilbert3D.
+func DecodeHilbert3D(code uint64) (x, y, z uint32) {
+	var v [3]uint32
+	v[2], v[1], v[0] = DecodeMorton3D(code)
+	// Gray decode
+	t := v[2] >> 1
+	v[2] ^= v[1]
+	v[1] ^= v[0]
+	v[0] ^= t
+	// redo the rotations and reflections of each level
+	for q := uint32(2); q != 1<<curveBits; q <<= 1 {
+		p := q - 1
+		for i := len(v) - 1; i >= 0; i-- {
+			if v[i]&q != 0 {
+				v[0] ^= p
+			} else {
+				t := (v[0] ^ v[i]) & p
+				v[0] ^= t
+				v[i] ^= t
+			}
+		}
+	}
+	return v[0], v[1], v[2]
+}
+
+// spreadBits moves bit i of the low 21 bits of x to bit 3i.
+func spreadBits(x uint32) uint64 {
+	v := uint64(x) & 0x1fffff
+	v = (v | v<<32) & 0x1f00000000ffff
+	v = (v | v<<16) & 0x1f0000ff0000ff
+	v = (v | v<<8) & 0x100f00f00f00f00f
+	v = (v | v<<4) & 0x10c30c30c30c30c3
+	v = (v | v<<2) & 0x1249249249249249
+	return v
+}
+
+// compactBits moves bit 3i of v to bit i, the inverse of spreadBits.
+func compactBits(v uint64) uint32 {
+	v &= 0x1249249249249249
+	v = (v ^ v>>2) & 0x10c30c30c30c30c3
+	v = (v ^ v>>4) & 0x100f00f00f00f00f
+	v = (v ^ v>>8) & 0x1f0000ff0000ff
+	v = (v ^ v>>16) & 0x1f00000000ffff
+	v = (v ^ v>>32) & 0x1fffff
+	return uint32(v)
+}
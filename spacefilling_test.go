@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"testing"
+)
+
+func TestMorton3D(t *testing.T) {
+	for _, tt := range []struct {
+		x, y, z uint32
+		want    uint64
+	}{
+		{1, 0, 0, 1},
+		{0, 1, 0, 2},
+		{0, 0, 1, 4},
+		{3, 5, 6, 0b110_101_011},
+		{1<<21 - 1, 1<<21 - 1, 1<<21 - 1, 1<<63 - 1},
+	} {
+		if got := math3d.EncodeMorton3D(tt.x, tt.y, tt.z); got != tt.want {
+			t.Errorf("EncodeMorton3D(%d, %d, %d): want %b, got %b\n", tt.x, tt.y, tt.z, tt.want, got)
+		}
+		if x, y, z := math3d.DecodeMorton3D(tt.want); x != tt.x || y != tt.y || z != tt.z {
+			t.Errorf("DecodeMorton3D(%b): want %d %d %d, got %d %d %d\n", tt.want, tt.x, tt.y, tt.z, x, y, z)
+		}
+	}
+
+	bounds := math3d.AABB{Min: math3d.Point{X: -1, Y: -1, Z: -1}, Max: math3d.Point{X: 1, Y: 1, Z: 1}}
+	if x, y, z := math3d.QuantizePoint(math3d.Point{X: -1, Y: 0, Z: 5}, bounds); x != 0 || y != 1<<20 || z != 1<<21-1 {
+		t.Errorf("QuantizePoint: want 0 %d %d, got %d %d %d\n", 1<<20, 1<<21-1, x, y, z)
+	}
+}
+
+func TestHilbert3D(t *testing.T) {
+	if got := math3d.EncodeHilbert3D(0, 0, 0); got != 0 {
+		t.Errorf("EncodeHilbert3D(0, 0, 0): want 0, got %d\n", got)
+	}
+	// consecutive positions along the curve are neighboring cells
+	abs := func(a, b uint32) uint32 {
+		if a > b {
+			return a - b
+		}
+		return b - a
+	}
+	rng := rand.New(rand.NewSource(842))
+	starts := []uint64{0, 1<<63 - 100}
+	for i := 0; i < 20; i++ {
+		starts = append(starts, rng.Uint64()>>1)
+	}
+	for _, start := range starts {
+		px, py, pz := math3d.DecodeHilbert3D(start)
+		for code := start + 1; code < start+100; code++ {
+			x, y, z := math3d.DecodeHilbert3D(code)
+			if d := abs(x, px) + abs(y, py) + abs(z, pz); d != 1 {
+				t.Errorf("DecodeHilbert3D(%d): want a neighbor of %d %d %d, got %d %d %d\n", code, px, py, pz, x, y, z)
+			}
+			if got := math3d.EncodeHilbert3D(x, y, z); got != code {
+				t.Errorf("EncodeHilbert3D(%d, %d, %d): want %d, got %d\n", x, y, z, code, got)
+			}
+			px, py, pz = x, y, z
+		}
+	}
+}
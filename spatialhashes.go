@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Cell is the integer coordinates of a cell in a uniform grid. The cell
+// (i, j, k) of a grid with cells of size s covers the points from
+// (i s, j s, k s) up to, but not including, ((i+1) s, (j+1) s, (k+1) s).
+type Cell struct {
+	X, Y, Z int
+}
+
+// CellOf returns the cell of a grid with cells of the given size that
+// holds p.
+func CellOf(p Point, size float64) Cell {
+	return Cell{X: int(math.Floor(p.X / size)), Y: int(math.Floor(p.Y / size)), Z: int(math.Floor(p.Z / size))}
+}
+
+// SpatialHash indexes moving points in a uniform grid, storing only the
+// cells that hold points. Inserting, moving, and removing a point take
+// constant time, and a neighborhood query looks only at the cells it
+// overlaps, so it suits many points that move every frame. Points are
+// identified by integers chosen by the caller. Queries are fastest when
+// the cell size is about the query radius.
+type SpatialHash struct {
+	size   float64
+	cells  map[Cell][]int
+	points map[int]Point
+}
+
+// NewSpatialHash returns an empty spatial hash with cells of the given
+// size, which must be positive.
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	return &SpatialHash{size: cellSize, cells: map[Cell][]int{}, points: map[int]Point{}}
+}
+
+// Insert adds the point with the given id at p, moving it if the id is
+// already present.
+func (h *SpatialHash) Insert(id int, p Point) {
+	if old, ok := h.points[id]; ok {
+		h.removeFromCell(id, CellOf(old, h.size))
+	}
+	h.points[id] = p
+	c := CellOf(p, h.size)
+	h.cells[c] = append(h.cells[c], id)
+}
+
+// Len returns the number of points in the hash.
+func (h *SpatialHash) Len() int {
+	return len(h.points)
+}
+
+// Move moves the point with the given id to p. It is cheap when the
+// point stays in the same cell. It returns false if there is no such
+// point.
+func (h *SpatialHash) Move(id int, p Point) bool {
+	old, ok := h.points[id]
+	if !ok {
+		return false
+	}
+	h.points[id] = p
+	if from, to := CellOf(old, h.size), CellOf(p, h.size); from != to {
+		h.removeFromCell(id, from)
+		h.cells[to] = append(h.cells[to], id)
+	}
+	return true
+}
+
+// QueryNeighborhood returns the ids of the points within distance r of
+// p, including those at exactly r, in no particular order. When the
+// neighborhood spans more cells than there are points, it checks every
+// point instead, so a large radius costs no more than a full scan.
+func (h *SpatialHash) QueryNeighborhood(p Point, r float64) []int {
+	var ids []int
+	r2 := r * r
+	// count the cells in floating point, where a huge or infinite radius
+	// cannot overflow
+	cells := 1.0
+	for _, c := range [3]float64{p.X, p.Y, p.Z} {
+		cells *= math.Floor((c+r)/h.size) - math.Floor((c-r)/h.size) + 1
+	}
+	if !(cells <= float64(len(h.points))) {
+		for id, q := range h.points {
+			if q.Sub(p).LengthSquared() <= r2 {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	lo := CellOf(Point{X: p.X - r, Y: p.Y - r, Z: p.Z - r}, h.size)
+	hi := CellOf(Point{X: p.X + r, Y: p.Y + r, Z: p.Z + r}, h.size)
+	for x := lo.X; x <= hi.X; x++ {
+		for y := lo.Y; y <= hi.Y; y++ {
+			for z := lo.Z; z <= hi.Z; z++ {
+				for _, id := range h.cells[Cell{X: x, Y: y, Z: z}] {
+					if h.points[id].Sub(p).LengthSquared() <= r2 {
+						ids = append(ids, id)
+					}
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// Remove deletes the point with the given id. It returns false if there
+// is no such point.
+func (h *SpatialHash) Remove(id int) bool {
+	p, ok := h.points[id]
+	if !ok {
+		return false
+	}
+	h.removeFromCell(id, CellOf(p, h.size))
+	delete(h.points, id)
+	return true
+}
+
+// removeFromCell deletes the id from the list for the cell, dropping the
+// cell when it becomes empty.
+func (h *SpatialHash) removeFromCell(id int, c Cell) {
+	ids := h.cells[c]
+	for i, other := range ids {
+		if other == id {
+			ids[i] = ids[len(ids)-1]
+			ids = ids[:len(ids)-1]
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(h.cells, c)
+	} else {
+		h.cells[c] = ids
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSpatialHash(t *testing.T) {
+	if got, want := math3d.CellOf(math3d.Point{X: 2.5, Y: -0.1, Z: 4}, 2), (math3d.Cell{X: 1, Y: -1, Z: 2}); got != want {
+		t.Errorf("CellOf: want %v, got %v\n", want, got)
+	}
+
+	rng := rand.New(rand.NewSource(838))
+	random := func() math3d.Point {
+		return math3d.Point{X: rng.Float64()*20 - 10, Y: rng.Float64()*20 - 10, Z: rng.Float64()*20 - 10}
+	}
+	h := math3d.NewSpatialHash(1.5)
+	points := map[int]math3d.Point{}
+	for id := 0; id < 1000; id++ {
+		points[id] = random()
+		h.Insert(id, points[id])
+	}
+	for step := 0; step < 3; step++ {
+		for id := 0; id < 1000; id += 2 {
+			// small moves mostly stay in the same cell
+			p := points[id].Add(math3d.NewVec3(rng.Float64()-0.5, rng.Float64()-0.5, rng.Float64()-0.5))
+			if !h.Move(id, p) {
+				t.Errorf("Move(%d): want true, got false\n", id)
+			}
+			points[id] = p
+		}
+	}
+	for id := 0; id < 1000; id += 5 {
+		h.Remove(id)
+		delete(points, id)
+	}
+	if h.Move(0, math3d.Point{}) || h.Remove(0) {
+		t.Errorf("Move, Remove: removed: want false, got true\n")
+	}
+	if h.Len() != len(points) {
+		t.Errorf("Len: want %d, got %d\n", len(points), h.Len())
+	}
+
+	for q := 0; q < 50; q++ {
+		p, r := random(), rng.Float64()*4
+		got := h.QueryNeighborhood(p, r)
+		var want []int
+		for id, pt := range points {
+			if pt.Distance(p) <= r {
+				want = append(want, id)
+			}
+		}
+		sort.Ints(got)
+		sort.Ints(want)
+		if !equalInts(got, want) {
+			t.Errorf("QueryNeighborhood(%v, %v): want %v, got %v\n", p, r, want, got)
+		}
+	}
+
+	// a radius spanning far more cells than there are points scans the
+	// points instead of the cells
+	for _, r := range []float64{1000, 1e300, math.Inf(1)} {
+		if got := h.QueryNeighborhood(math3d.Point{}, r); len(got) != len(points) {
+			t.Errorf("QueryNeighborhood(origin, %v): want all %d points, got %d\n", r, len(points), len(got))
+		}
+	}
+}
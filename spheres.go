@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Sphere is the set of points within Radius of Center.
+type Sphere struct {
+	Center Point
+	Radius float64
+}
+
+// SweepHit describes where a moving sphere first touches a shape: the
+// fraction T of the motion at which it happens, the contact Point on the
+// shape, and the unit Normal of the shape there, pointing towards the
+// center of the sphere.
+type SweepHit struct {
+	T      float64
+	Point  Point
+	Normal Vec3
+}
+
+// BoundingSphere returns the smallest sphere containing all the points,
+// using Welzl's randomized algorithm written as nested loops rather than
+// recursion, which takes expected linear time. The points are visited in a
+// fixed pseudo-random order, so the result is repeatable. It returns
+// false if there are no points.
+func BoundingSphere(points []Point) (Sphere, bool) {
+	if len(points) == 0 {
+		return Sphere{}, false
+	}
+	p := make([]Point, len(points))
+	copy(p, points)
+	rng := rand.New(rand.NewSource(1))
+	rng.Shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] })
+
+	s := Sphere{Center: p[0]}
+	for i := 1; i < len(p); i++ {
+		if s.containsWithin(p[i]) {
+			continue
+		}
+		// p[i] is on the boundary of the smallest sphere of p[:i+1]
+		s = Sphere{Center: p[i]}
+		for j := 0; j < i; j++ {
+			if s.containsWithin(p[j]) {
+				continue
+			}
+			s = sphereThrough(p[i], p[j])
+			for k := 0; k < j; k++ {
+				if s.containsWithin(p[k]) {
+					continue
+				}
+				s = sphereThrough(p[i], p[j], p[k])
+				for l := 0; l < k; l++ {
+					if !s.containsWithin(p[l]) {
+						s = sphereThrough(p[i], p[j], p[k], p[l])
+					}
+				}
+			}
+		}
+	}
+	return s, true
+}
+
+// BoundingSphereRitter returns a sphere containing all the points using
+// Ritter's method from Graphics Gems (1990): it starts from the sphere
+// on two far-apart points and grows it just enough to take in each point
+// outside it. It makes two passes over the points and is usually within
+// a few percent of the smallest sphere, though it can be as much as
+// about a fifth larger. It returns false if there are no points.
+func BoundingSphereRitter(points []Point) (Sphere, bool) {
+	if len(points) == 0 {
+		return Sphere{}, false
+	}
+	furthestFrom := func(q Point) Point {
+		best, far := -1.0, q
+		for _, p := range points {
+			if d := p.Sub(q).LengthSquared(); d > best {
+				best, far = d, p
+			}
+		}
+		return far
+	}
+	a := furthestFrom(points[0])
+	b := furthestFrom(a)
+	s := Sphere{Center: a.Lerp(b, 0.5), Radius: a.Distance(b) / 2}
+	for _, p := range points {
+		if d := p.Distance(s.Center); d > s.Radius {
+			r := (s.Radius + d) / 2
+			s.Center = s.Center.Add(p.Sub(s.Center).Mul((r - s.Radius) / d))
+			s.Radius = r
+		}
+	}
+	return s, true
+}
+
+// The Sweep methods move the sphere from its center by the displacement
+// v and find the first contact with a shape, which discrete overlap tests
+// miss when objects move further than their size in one step. Each
+// returns false if there is no contact during the motion. If the sphere
+// already overlaps the shape, T is 0 and Point is the point of the shape
+// nearest the center.
+
+// ContainsPoint reports whether p is inside the sphere or on its surface.
+func (s Sphere) ContainsPoint(p Point) bool {
+	return p.Sub(s.Center).LengthSquared() <= s.Radius*s.Radius
+}
+
+// IntersectsAABB reports whether the sphere and the box overlap or touch.
+func (s Sphere) IntersectsAABB(b AABB) bool {
+	return s.ContainsPoint(b.ClosestPointTo(s.Center))
+}
+
+// IntersectsSphere reports whether the two spheres overlap or touch.
+func (s Sphere) IntersectsSphere(s2 Sphere) bool {
+	r := s.Radius + s2.Radius
+	return s.Center.Sub(s2.Center).LengthSquared() <= r*r
+}
+
+// Support returns the point of the sphere furthest in the direction d.
+// It implements the ConvexShape interface.
+func (s Sphere) Support(d Vec3) Point {
+	return s.Center.Add(d.NormalizeOrZero().Mul(s.Radius))
+}
+
+// SweepAABB returns the first contact of the sphere moving by v with the
+// box. The sphere touches the box when its center reaches the box
+// grown by the radius with rounded edges and corners, so the motion of the
+// center is traced against the faces, edges, and corners of that shape.
+func (s Sphere) SweepAABB(v Vec3, b AABB) (SweepHit, bool) {
+	if s.IntersectsAABB(b) {
+		return s.sweepHit(0, v, b.ClosestPointTo(s.Center)), true
+	}
+	r := Ray{Origin: s.Center, Direction: v}
+	best, hit := math.Inf(1), false
+	try := func(t float64, ok bool) {
+		if ok && t < best {
+			best, hit = t, true
+		}
+	}
+	grow := [3]Vec3{{X: s.Radius}, {Y: s.Radius}, {Z: s.Radius}}
+	for _, g := range grow {
+		try(r.intersectAABB(AABB{Min: b.Min.Add(g.Mul(-1)), Max: b.Max.Add(g)}))
+	}
+	corners := b.Corners()
+	for i, c := range corners {
+		for bit := 1; bit < 8; bit <<= 1 {
+			if i&bit == 0 {
+				try(r.IntersectCylinder(Cylinder{A: c, B: corners[i|bit], Radius: s.Radius}))
+			}
+		}
+		if enter, _, ok := r.IntersectSphere(Sphere{Center: c, Radius: s.Radius}); ok && enter.T >= 0 {
+			try(enter.T, true)
+		}
+	}
+	if !hit || best > 1 {
+		return SweepHit{}, false
+	}
+	c := r.At(best)
+	return s.sweepHit(best, v, b.ClosestPointTo(c)), true
+}
+
+// SweepPlane returns the first contact of the sphere moving by v with
+// the plane, approached from whichever side the sphere starts on.
+func (s Sphere) SweepPlane(v Vec3, pl Plane) (SweepHit, bool) {
+	pl = pl.Normalize()
+	d := pl.SignedDistance(s.Center)
+	if math.Abs(d) <= s.Radius {
+		return s.sweepHit(0, v, pl.ProjectPoint(s.Center)), true
+	}
+	// the distance must shrink to the radius on the side of the center
+	side := math.Copysign(1, d)
+	approach := -side * pl.Normal.Dot(v)
+	if approach <= 0 {
+		return SweepHit{}, false
+	}
+	t := (math.Abs(d) - s.Radius) / approach
+	if t > 1 {
+		return SweepHit{}, false
+	}
+	n := pl.Normal.Mul(side)
+	return SweepHit{T: t, Point: s.Center.Add(v.Mul(t)).Add(n.Mul(-s.Radius)), Normal: n}, true
+}
+
+// SweepSphere returns the first contact of the sphere moving by v with
+// the stationary sphere s2. To sweep two moving spheres, pass the motion
+// of s relative to s2.
+func (s Sphere) SweepSphere(v Vec3, s2 Sphere) (SweepHit, bool) {
+	toward := func(c Point) Point {
+		return s2.Center.Add(c.Sub(s2.Center).NormalizeOrZero().Mul(s2.Radius))
+	}
+	if s.IntersectsSphere(s2) {
+		return s.sweepHit(0, v, toward(s.Center)), true
+	}
+	r := Ray{Origin: s.Center, Direction: v}
+	enter, _, ok := r.IntersectSphere(Sphere{Center: s2.Center, Radius: s.Radius + s2.Radius})
+	if !ok || enter.T < 0 || enter.T > 1 {
+		return SweepHit{}, false
+	}
+	return SweepHit{T: enter.T, Point: toward(enter.Point), Normal: enter.Normal}, true
+}
+
+// SweepTriangle returns the first contact of the sphere moving by v with
+// the triangle. As with SweepAABB, the motion of the center is traced
+// against the triangle grown by the radius: two offset copies of its
+// face, and rounded edges and corners.
+func (s Sphere) SweepTriangle(v Vec3, tr Triangle) (SweepHit, bool) {
+	if q := tr.ClosestPointTo(s.Center); s.ContainsPoint(q) {
+		return s.sweepHit(0, v, q), true
+	}
+	r := Ray{Origin: s.Center, Direction: v}
+	best, hit := math.Inf(1), false
+	try := func(t float64, ok bool) {
+		if ok && t < best {
+			best, hit = t, true
+		}
+	}
+	if n, ok := tr.Normal().Normalized(); ok {
+		for _, off := range []Vec3{n.Mul(s.Radius), n.Mul(-s.Radius)} {
+			h, ok := r.IntersectTriangle(Triangle{A: tr.A.Add(off), B: tr.B.Add(off), C: tr.C.Add(off)})
+			try(h.T, ok)
+		}
+	}
+	for _, e := range [3][2]Point{{tr.A, tr.B}, {tr.B, tr.C}, {tr.C, tr.A}} {
+		try(r.IntersectCylinder(Cylinder{A: e[0], B: e[1], Radius: s.Radius}))
+		if enter, _, ok := r.IntersectSphere(Sphere{Center: e[0], Radius: s.Radius}); ok && enter.T >= 0 {
+			try(enter.T, true)
+		}
+	}
+	if !hit || best > 1 {
+		return SweepHit{}, false
+	}
+	return s.sweepHit(best, v, tr.ClosestPointTo(r.At(best))), true
+}
+
+// Transform returns a sphere that bounds the sphere transformed by the
+// affine matrix m. The radius is scaled by the largest scale factor of
+// m, so the result is exact for rotations and uniform scales and
+// conservative otherwise.
+func (s Sphere) Transform(m Mat4) Sphere {
+	lin := m.Mat3()
+	scale := math.Max(lin.Col(0).LengthSquared(), math.Max(lin.Col(1).LengthSquared(), lin.Col(2).LengthSquared()))
+	return Sphere{Center: m.TransformPoint(s.Center), Radius: s.Radius * math.Sqrt(scale)}
+}
+
+// sweepHit returns the hit at fraction t of the motion v, where the
+// shape's nearest point to the center is q. When the center is on the
+// shape, the normal opposes the motion.
+func (s Sphere) sweepHit(t float64, v Vec3, q Point) SweepHit {
+	n, ok := s.Center.Add(v.Mul(t)).Sub(q).Normalized()
+	if !ok {
+		n = v.Mul(-1).NormalizeOrZero()
+	}
+	return SweepHit{T: t, Point: q, Normal: n}
+}
+
+// boundingTolerance is the fraction of its radius, or of the size of its
+// center's coordinates if larger, by which a point may be outside a
+// sphere and still count as inside it in BoundingSphere. It keeps
+// rounding in the spheres through the boundary points from rejecting
+// those same points.
+const boundingTolerance = 1e-12
+
+// containsWithin reports whether p is inside the sphere, allowing for
+// rounding.
+func (s Sphere) containsWithin(p Point) bool {
+	c := s.Center
+	scale := math.Max(s.Radius, math.Max(math.Abs(c.X), math.Max(math.Abs(c.Y), math.Abs(c.Z))))
+	return p.Distance(c) <= s.Radius+boundingTolerance*scale
+}
+
+// sphereThrough returns the smallest sphere with one to four points on
+// its surface. When the points are too close to a line or a plane for a
+// sphere through them all, it returns the smallest sphere through some
+// of them that contains the rest.
+func sphereThrough(p ...Point) Sphere {
+	switch len(p) {
+	case 1:
+		return Sphere{Center: p[0]}
+	case 2:
+		return Sphere{Center: p[0].Lerp(p[1], 0.5), Radius: p[0].Distance(p[1]) / 2}
+	case 3:
+		if c, ok := (Triangle{A: p[0], B: p[1], C: p[2]}).Circumcenter(); ok {
+			return Sphere{Center: c, Radius: math.Max(c.Distance(p[0]), math.Max(c.Distance(p[1]), c.Distance(p[2])))}
+		}
+	case 4:
+		if s, ok := (Tetrahedron{A: p[0], B: p[1], C: p[2], D: p[3]}).Circumsphere(); ok {
+			for _, q := range p {
+				s.Radius = math.Max(s.Radius, s.Center.Distance(q))
+			}
+			return s
+		}
+	}
+	// the smallest of the spheres through fewer points that holds them all
+	best := Sphere{Radius: math.Inf(1)}
+	for skip := range p {
+		rest := make([]Point, 0, len(p)-1)
+		rest = append(rest, p[:skip]...)
+		rest = append(rest, p[skip+1:]...)
+		if s := sphereThrough(rest...); s.Radius < best.Radius && s.containsWithin(p[skip]) {
+			best = s
+		}
+	}
+	return best
+}
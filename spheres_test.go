@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSphere(t *testing.T) {
+	s := math3d.Sphere{Center: math3d.Point{X: 1, Y: 2, Z: 3}, Radius: 2}
+	for _, tt := range []struct {
+		p    math3d.Point
+		want bool
+	}{
+		{s.Center, true},
+		{math3d.Point{X: 3, Y: 2, Z: 3}, true},
+		{math3d.Point{X: 2, Y: 3, Z: 4}, true},
+		{math3d.Point{X: 2.2, Y: 3.2, Z: 4.2}, false},
+	} {
+		if got := s.ContainsPoint(tt.p); got != tt.want {
+			t.Errorf("ContainsPoint(%v): want %v, got %v\n", tt.p, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		s2   math3d.Sphere
+		want bool
+	}{
+		{math3d.Sphere{Center: math3d.Point{X: 4, Y: 2, Z: 3}, Radius: 1}, true},
+		{math3d.Sphere{Center: math3d.Point{X: 4, Y: 2, Z: 3}, Radius: 0.9}, false},
+		{math3d.Sphere{Center: math3d.Point{X: 1, Y: 2, Z: 3}, Radius: 0.1}, true},
+	} {
+		if got := s.IntersectsSphere(tt.s2); got != tt.want {
+			t.Errorf("IntersectsSphere(%v): want %v, got %v\n", tt.s2, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		b    math3d.AABB
+		want bool
+	}{
+		{math3d.AABB{Min: math3d.Point{X: 3, Y: 0, Z: 0}, Max: math3d.Point{X: 5, Y: 5, Z: 5}}, true},
+		{math3d.AABB{Min: math3d.Point{X: 2.5, Y: 3.5, Z: 4.5}, Max: math3d.Point{X: 5, Y: 5, Z: 5}}, false},
+		{math3d.AABB{Min: math3d.Point{X: -9, Y: -9, Z: -9}, Max: math3d.Point{X: 9, Y: 9, Z: 9}}, true},
+	} {
+		if got := s.IntersectsAABB(tt.b); got != tt.want {
+			t.Errorf("IntersectsAABB(%v): want %v, got %v\n", tt.b, tt.want, got)
+		}
+	}
+
+	m := math3d.Mat4FromTranslation(math3d.NewVec3(1, 0, 0)).Mul(math3d.Mat4FromRotationZ(math.Pi / 2)).Mul(math3d.Mat4FromScale(math3d.NewVec3(1, 3, 2)))
+	got := s.Transform(m)
+	if want := (math3d.Sphere{Center: m.TransformPoint(s.Center), Radius: 6}); !got.Center.ApproxEqual(want.Center, 1e-12) || math.Abs(got.Radius-want.Radius) > 1e-12 {
+		t.Errorf("Transform: want %v, got %v\n", want, got)
+	}
+}
+
+func TestSphereSweep(t *testing.T) {
+	s := math3d.Sphere{Center: math3d.Point{X: -5}, Radius: 1}
+	v := math3d.NewVec3(10, 0, 0)
+	box := math3d.AABB{Min: math3d.Point{X: -1, Y: -1, Z: -1}, Max: math3d.Point{X: 1, Y: 1, Z: 1}}
+	tri := math3d.Triangle{A: math3d.Point{X: 2, Y: -1, Z: -1}, B: math3d.Point{X: 2, Y: 1, Z: -1}, C: math3d.Point{X: 2, Z: 1}}
+	for _, tt := range []struct {
+		name string
+		got  func() (math3d.SweepHit, bool)
+		ok   bool
+		want math3d.SweepHit
+	}{
+		{"plane", func() (math3d.SweepHit, bool) {
+			return s.SweepPlane(v, math3d.NewPlane(math3d.NewVec3(-2, 0, 0), -6))
+		}, true, math3d.SweepHit{T: 0.1, Point: math3d.Point{X: -3}, Normal: math3d.NewVec3(-1, 0, 0)}},
+		{"plane receding", func() (math3d.SweepHit, bool) {
+			return s.SweepPlane(v.Mul(-1), math3d.NewPlane(math3d.NewVec3(1, 0, 0), 3))
+		}, false, math3d.SweepHit{}},
+		{"box face", func() (math3d.SweepHit, bool) { return s.SweepAABB(v, box) }, true,
+			math3d.SweepHit{T: 0.3, Point: math3d.Point{X: -1}, Normal: math3d.NewVec3(-1, 0, 0)}},
+		// passing above the box, the sphere clips the top edge
+		{"box edge", func() (math3d.SweepHit, bool) {
+			return math3d.Sphere{Center: math3d.Point{X: -5, Y: 1.6}, Radius: 1}.SweepAABB(v, box)
+		}, true, math3d.SweepHit{T: 0.32, Point: math3d.Point{X: -1, Y: 1}, Normal: math3d.NewVec3(-0.8, 0.6, 0)}},
+		{"box miss", func() (math3d.SweepHit, bool) {
+			return math3d.Sphere{Center: math3d.Point{X: -5, Y: 1.6, Z: 1.6}, Radius: 0.8}.SweepAABB(v, box)
+		}, false, math3d.SweepHit{}},
+		{"triangle", func() (math3d.SweepHit, bool) { return s.SweepTriangle(v, tri) }, true,
+			math3d.SweepHit{T: 0.6, Point: math3d.Point{X: 2}, Normal: math3d.NewVec3(-1, 0, 0)}},
+		{"triangle vertex", func() (math3d.SweepHit, bool) {
+			return math3d.Sphere{Center: math3d.Point{X: -5, Z: 1.6}, Radius: 1}.SweepTriangle(v, tri)
+		}, true, math3d.SweepHit{T: 0.62, Point: math3d.Point{X: 2, Z: 1}, Normal: math3d.NewVec3(-0.8, 0, 0.6)}},
+		{"triangle short", func() (math3d.SweepHit, bool) { return s.SweepTriangle(v.Mul(0.5), tri) }, false, math3d.SweepHit{}},
+		{"sphere", func() (math3d.SweepHit, bool) {
+			return s.SweepSphere(v, math3d.Sphere{Center: math3d.Point{X: 3}, Radius: 2})
+		}, true, math3d.SweepHit{T: 0.5, Point: math3d.Point{X: 1}, Normal: math3d.NewVec3(-1, 0, 0)}},
+		{"overlapping", func() (math3d.SweepHit, bool) {
+			return s.SweepSphere(v, math3d.Sphere{Center: math3d.Point{X: -4}, Radius: 0.5})
+		}, true, math3d.SweepHit{T: 0, Point: math3d.Point{X: -4.5}, Normal: math3d.NewVec3(-1, 0, 0)}},
+	} {
+		got, ok := tt.got()
+		if ok != tt.ok {
+			t.Errorf("Sweep: %s: want %v, got %v\n", tt.name, tt.ok, ok)
+		} else if ok && (math.Abs(got.T-tt.want.T) > 1e-12 || !got.Point.ApproxEqual(tt.want.Point, 1e-12) || !got.Normal.ApproxEqual(tt.want.Normal, 1e-12)) {
+			t.Errorf("Sweep: %s: want %v, got %v\n", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBoundingSphere(t *testing.T) {
+	box, _ := math3d.AABBFromPoints(math3d.Point{X: 1, Y: 2, Z: 3}, math3d.Point{X: 3, Y: 4, Z: 5})
+	cube := box.Corners()
+	for _, tt := range []struct {
+		name   string
+		points []math3d.Point
+		want   math3d.Sphere
+	}{
+		{"one point", []math3d.Point{{X: 1, Y: 2, Z: 3}}, math3d.Sphere{Center: math3d.Point{X: 1, Y: 2, Z: 3}}},
+		{"two points", []math3d.Point{{X: 1}, {X: 5}}, math3d.Sphere{Center: math3d.Point{X: 3}, Radius: 2}},
+		{"line", []math3d.Point{{Y: 1}, {Y: 4}, {Y: -2}, {Y: 3}, {Y: 4}}, math3d.Sphere{Center: math3d.Point{Y: 1}, Radius: 3}},
+		{"square", []math3d.Point{{}, {X: 2}, {X: 2, Y: 2}, {Y: 2}, {X: 1, Y: 1}}, math3d.Sphere{Center: math3d.Point{X: 1, Y: 1}, Radius: math.Sqrt2}},
+		{"cube", cube[:], math3d.Sphere{Center: math3d.Point{X: 2, Y: 3, Z: 4}, Radius: math.Sqrt(3)}},
+		// the obtuse corner is inside the sphere on the longest side
+		{"obtuse", []math3d.Point{{X: -2}, {X: 2}, {Y: 1}}, math3d.Sphere{Radius: 2}},
+	} {
+		got, ok := math3d.BoundingSphere(tt.points)
+		if !ok || !got.Center.ApproxEqual(tt.want.Center, 1e-12) || math.Abs(got.Radius-tt.want.Radius) > 1e-12 {
+			t.Errorf("BoundingSphere: %s: want %v, got %v %v\n", tt.name, tt.want, got, ok)
+		}
+	}
+	if _, ok := math3d.BoundingSphere(nil); ok {
+		t.Errorf("BoundingSphere: empty: want !ok, got ok\n")
+	}
+	if _, ok := math3d.BoundingSphereRitter(nil); ok {
+		t.Errorf("BoundingSphereRitter: empty: want !ok, got ok\n")
+	}
+
+	rng := rand.New(rand.NewSource(849))
+	for trial := 0; trial < 20; trial++ {
+		var points []math3d.Point
+		for i := 0; i < 10+trial*20; i++ {
+			points = append(points, math3d.Point{X: rng.NormFloat64() * 3, Y: rng.NormFloat64(), Z: rng.NormFloat64() + 10})
+		}
+		// reach returns the distance from c to the furthest point
+		reach := func(c math3d.Point) float64 {
+			var r float64
+			for _, p := range points {
+				r = math.Max(r, p.Distance(c))
+			}
+			return r
+		}
+		s, _ := math3d.BoundingSphere(points)
+		if r := reach(s.Center); r > s.Radius*(1+1e-12) {
+			t.Errorf("BoundingSphere: trial %d: want all points within %v, got %v\n", trial, s.Radius, r)
+		}
+		// moving the center any way takes it further from some point
+		for i := 0; i < 50; i++ {
+			step := math3d.NewVec3(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()).NormalizeOrZero().Mul(1e-6)
+			if r := reach(s.Center.Add(step)); r < s.Radius-1e-12 {
+				t.Errorf("BoundingSphere: trial %d: want the smallest radius %v, got %v nearby\n", trial, s.Radius, r)
+				break
+			}
+		}
+		ritter, _ := math3d.BoundingSphereRitter(points)
+		if r := reach(ritter.Center); r > ritter.Radius*(1+1e-12) || ritter.Radius < s.Radius || ritter.Radius > 1.25*s.Radius {
+			t.Errorf("BoundingSphereRitter: trial %d: want a radius from %v to %v holding %v, got %v\n", trial, s.Radius, 1.25*s.Radius, r, ritter.Radius)
+		}
+	}
+}
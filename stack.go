@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// MatrixStack implements an OpenGL-style stack of transforms for
+// hierarchical drawing. The stack always holds at least one matrix,
+// the current transform. Popped entries are kept in the underlying
+// slice, so pushing to a previously reached depth does not allocate.
+type MatrixStack struct {
+	stack []Mat4
+}
+
+// NewMatrixStack returns a stack holding the identity matrix.
+func NewMatrixStack() *MatrixStack {
+	return &MatrixStack{stack: append(make([]Mat4, 0, 16), Identity4())}
+}
+
+// Current returns the transform at the top of the stack.
+func (s *MatrixStack) Current() Mat4 {
+	return s.stack[len(s.stack)-1]
+}
+
+// Depth returns the number of matrices on the stack.
+func (s *MatrixStack) Depth() int {
+	return len(s.stack)
+}
+
+// Load replaces the transform at the top of the stack.
+func (s *MatrixStack) Load(m Mat4) {
+	s.stack[len(s.stack)-1] = m
+}
+
+// LoadIdentity replaces the transform at the top of the stack with the identity.
+func (s *MatrixStack) LoadIdentity() {
+	s.Load(Identity4())
+}
+
+// MultLocal multiplies the current transform by m on the right, so m is
+// applied in the local coordinate system of the current transform.
+func (s *MatrixStack) MultLocal(m Mat4) {
+	top := len(s.stack) - 1
+	s.stack[top] = s.stack[top].Mul(m)
+}
+
+// Pop removes the transform at the top of the stack, restoring the one
+// saved by the matching Push. It returns false, leaving the stack
+// unchanged, if only the base transform remains.
+func (s *MatrixStack) Pop() bool {
+	if len(s.stack) == 1 {
+		return false
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	return true
+}
+
+// Push saves the current transform by pushing a copy of it onto the stack.
+func (s *MatrixStack) Push() {
+	s.stack = append(s.stack, s.Current())
+}
+
+// Reset empties the stack down to a single identity matrix,
+// keeping the allocated storage for reuse.
+func (s *MatrixStack) Reset() {
+	s.stack = append(s.stack[:0], Identity4())
+}
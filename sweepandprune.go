@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"sort"
+)
+
+// Pair is two ids reported together, with A less than B.
+type Pair struct {
+	A, B int
+}
+
+// SweepAndPrune is a broad phase for collision detection that keeps the
+// ends of the boxes sorted along each axis. When a box moves, its ends
+// are moved along each sorted list by insertion sort, and each end they
+// pass flips whether the two boxes overlap along that axis. Two boxes
+// overlap when they overlap along all three axes. Because objects move
+// little from frame to frame, updates take close to constant time.
+// Boxes that touch count as overlapping, as in AABB.IntersectsAABB.
+// Boxes are identified by integers chosen by the caller.
+type SweepAndPrune struct {
+	axes   [3][]sapEnd
+	boxes  map[int]*sapBox
+	counts map[Pair]int  // axes along which each pair overlaps, if any
+	before map[Pair]bool // whether changed pairs overlapped at the last report
+}
+
+// sapEnd is one end of a box along an axis.
+type sapEnd struct {
+	value float64
+	id    int
+	max   bool
+}
+
+// sapBox holds the positions of the ends of a box in each axis list.
+type sapBox struct {
+	bounds   AABB
+	min, max [3]int
+}
+
+// NewSweepAndPrune returns an empty sweep-and-prune broad phase.
+func NewSweepAndPrune() *SweepAndPrune {
+	return &SweepAndPrune{boxes: map[int]*sapBox{}, counts: map[Pair]int{}, before: map[Pair]bool{}}
+}
+
+// Changes returns the pairs that have started and stopped overlapping
+// since the last call to Changes, each sorted.
+func (s *SweepAndPrune) Changes() (added, removed []Pair) {
+	for p, was := range s.before {
+		if is := s.counts[p] == 3; is && !was {
+			added = append(added, p)
+		} else if was && !is {
+			removed = append(removed, p)
+		}
+	}
+	s.before = map[Pair]bool{}
+	sortPairs(added)
+	sortPairs(removed)
+	return added, removed
+}
+
+// Insert adds the box with the given id, or moves it if the id is
+// already present.
+func (s *SweepAndPrune) Insert(id int, b AABB) {
+	if _, ok := s.boxes[id]; ok {
+		s.Update(id, b)
+		return
+	}
+	box := &sapBox{bounds: b}
+	s.boxes[id] = box
+	for axis := range s.axes {
+		// start both ends past every other end, where the box overlaps
+		// nothing, and slide them into place
+		ends := append(s.axes[axis], sapEnd{value: math.Inf(1), id: id}, sapEnd{value: math.Inf(1), id: id, max: true})
+		s.axes[axis] = ends
+		box.min[axis], box.max[axis] = len(ends)-2, len(ends)-1
+		s.move(axis, box.min[axis], pointAxis(b.Min, axis))
+		s.move(axis, box.max[axis], pointAxis(b.Max, axis))
+	}
+}
+
+// Len returns the number of boxes.
+func (s *SweepAndPrune) Len() int {
+	return len(s.boxes)
+}
+
+// Pairs returns every pair of boxes that currently overlap, sorted.
+func (s *SweepAndPrune) Pairs() []Pair {
+	var pairs []Pair
+	for p, n := range s.counts {
+		if n == 3 {
+			pairs = append(pairs, p)
+		}
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+// Remove deletes the box with the given id. It returns false if there is
+// no such box.
+func (s *SweepAndPrune) Remove(id int) bool {
+	box, ok := s.boxes[id]
+	if !ok {
+		return false
+	}
+	for axis := range s.axes {
+		// slide both ends back past every other end, then drop them
+		s.move(axis, box.max[axis], math.Inf(1))
+		s.move(axis, box.min[axis], math.Inf(1))
+		s.axes[axis] = s.axes[axis][:len(s.axes[axis])-2]
+	}
+	delete(s.boxes, id)
+	return true
+}
+
+// Update moves the box with the given id to b. It returns false if there
+// is no such box.
+func (s *SweepAndPrune) Update(id int, b AABB) bool {
+	box, ok := s.boxes[id]
+	if !ok {
+		return false
+	}
+	box.bounds = b
+	for axis := range s.axes {
+		lo, hi := pointAxis(b.Min, axis), pointAxis(b.Max, axis)
+		// move the leading end first so the ends never cross
+		if hi >= s.axes[axis][box.max[axis]].value {
+			s.move(axis, box.max[axis], hi)
+			s.move(axis, box.min[axis], lo)
+		} else {
+			s.move(axis, box.min[axis], lo)
+			s.move(axis, box.max[axis], hi)
+		}
+	}
+	return true
+}
+
+// move sets the value of the end at index i of the axis list and slides
+// it into sorted position, updating the overlap counts of the boxes
+// whose ends it passes.
+func (s *SweepAndPrune) move(axis, i int, value float64) {
+	ends := s.axes[axis]
+	ends[i].value = value
+	for i > 0 && sapBefore(ends[i], ends[i-1]) {
+		s.swap(axis, i-1, i)
+		i--
+	}
+	for i+1 < len(ends) && sapBefore(ends[i+1], ends[i]) {
+		s.swap(axis, i, i+1)
+		i++
+	}
+}
+
+// swap exchanges the adjacent ends at i and i+1 of the axis list. When
+// the start of one box passes the end of another, the two begin or stop
+// overlapping along the axis.
+func (s *SweepAndPrune) swap(axis, i, j int) {
+	ends := s.axes[axis]
+	a, b := ends[i], ends[j]
+	ends[i], ends[j] = b, a
+	for k, e := range []sapEnd{ends[i], ends[j]} {
+		box := s.boxes[e.id]
+		if e.max {
+			box.max[axis] = i + k
+		} else {
+			box.min[axis] = i + k
+		}
+	}
+	if a.id == b.id || a.max == b.max {
+		return
+	}
+	p := Pair{A: a.id, B: b.id}
+	if p.A > p.B {
+		p.A, p.B = p.B, p.A
+	}
+	if _, ok := s.before[p]; !ok {
+		s.before[p] = s.counts[p] == 3
+	}
+	// b moved ahead of a: a start passing an end separates the boxes,
+	// an end passing a start brings them together
+	if a.max {
+		s.counts[p]++
+	} else {
+		s.counts[p]--
+	}
+	if s.counts[p] == 0 {
+		delete(s.counts, p)
+	}
+}
+
+// sapBefore reports whether the end a sorts before b. At equal values a
+// start comes before an end, so touching boxes overlap.
+func sapBefore(a, b sapEnd) bool {
+	return a.value < b.value || (a.value == b.value && !a.max && b.max)
+}
+
+// sortPairs sorts the pairs by A, then B.
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].A < pairs[j].A || (pairs[i].A == pairs[j].A && pairs[i].B < pairs[j].B)
+	})
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"testing"
+)
+
+func TestSweepAndPrune(t *testing.T) {
+	rng := rand.New(rand.NewSource(841))
+	boxes := map[int]math3d.AABB{}
+	sap := math3d.NewSweepAndPrune()
+	randomBox := func() math3d.AABB {
+		c := math3d.Point{X: rng.Float64() * 20, Y: rng.Float64() * 20, Z: rng.Float64() * 20}
+		return math3d.AABB{Min: c, Max: c}.Expand(0.5 + rng.Float64())
+	}
+	bruteForce := func() map[math3d.Pair]bool {
+		pairs := map[math3d.Pair]bool{}
+		for a, ba := range boxes {
+			for b, bb := range boxes {
+				if a < b && ba.IntersectsAABB(bb) {
+					pairs[math3d.Pair{A: a, B: b}] = true
+				}
+			}
+		}
+		return pairs
+	}
+	for id := 0; id < 150; id++ {
+		boxes[id] = randomBox()
+		sap.Insert(id, boxes[id])
+	}
+	// two boxes that only touch
+	boxes[200] = math3d.AABB{Min: math3d.Point{X: 30, Y: 30, Z: 30}, Max: math3d.Point{X: 31, Y: 31, Z: 31}}
+	boxes[201] = math3d.AABB{Min: math3d.Point{X: 31, Y: 30, Z: 30}, Max: math3d.Point{X: 32, Y: 31, Z: 31}}
+	sap.Insert(200, boxes[200])
+	sap.Insert(201, boxes[201])
+
+	prev := map[math3d.Pair]bool{}
+	for step := 0; step < 20; step++ {
+		want := bruteForce()
+		got := sap.Pairs()
+		if len(got) != len(want) {
+			t.Errorf("Pairs: step %d: want %d pairs, got %d\n", step, len(want), len(got))
+		}
+		for _, p := range got {
+			if !want[p] {
+				t.Errorf("Pairs: step %d: %v do not overlap\n", step, p)
+			}
+		}
+		added, removed := sap.Changes()
+		for _, p := range added {
+			if prev[p] || !want[p] {
+				t.Errorf("Changes: step %d: %v was not added\n", step, p)
+			}
+		}
+		for _, p := range removed {
+			if !prev[p] || want[p] {
+				t.Errorf("Changes: step %d: %v was not removed\n", step, p)
+			}
+		}
+		if n := len(prev) + len(added) - len(removed); n != len(want) {
+			t.Errorf("Changes: step %d: want %d pairs after changes, got %d\n", step, len(want), n)
+		}
+		prev = want
+
+		// jiggle every box, and replace a few
+		for id, b := range boxes {
+			if id >= 200 {
+				continue
+			}
+			d := math3d.NewVec3(rng.Float64()-0.5, rng.Float64()-0.5, rng.Float64()-0.5)
+			b = math3d.AABB{Min: b.Min.Add(d), Max: b.Max.Add(d)}.Expand((rng.Float64() - 0.5) * 0.1)
+			boxes[id] = b
+			if !sap.Update(id, b) {
+				t.Errorf("Update(%d): want true, got false\n", id)
+			}
+		}
+		for k := 0; k < 5; k++ {
+			id := rng.Intn(150)
+			if _, ok := boxes[id]; ok {
+				sap.Remove(id)
+				delete(boxes, id)
+			} else {
+				boxes[id] = randomBox()
+				sap.Insert(id, boxes[id])
+			}
+		}
+	}
+	if sap.Len() != len(boxes) {
+		t.Errorf("Len: want %d, got %d\n", len(boxes), sap.Len())
+	}
+	if sap.Update(999, math3d.AABB{}) || sap.Remove(999) {
+		t.Errorf("Update, Remove: missing: want false, got true\n")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Swizzle accessors return the named components, in order, as a new vector.
+
+func (v Vec2) YX() Vec2 {
+	return Vec2{X: v.Y, Y: v.X}
+}
+
+func (v Vec3) XY() Vec2 {
+	return Vec2{X: v.X, Y: v.Y}
+}
+
+func (v Vec3) XZ() Vec2 {
+	return Vec2{X: v.X, Y: v.Z}
+}
+
+func (v Vec3) YX() Vec2 {
+	return Vec2{X: v.Y, Y: v.X}
+}
+
+func (v Vec3) YZ() Vec2 {
+	return Vec2{X: v.Y, Y: v.Z}
+}
+
+func (v Vec3) ZX() Vec2 {
+	return Vec2{X: v.Z, Y: v.X}
+}
+
+func (v Vec3) ZY() Vec2 {
+	return Vec2{X: v.Z, Y: v.Y}
+}
+
+func (v Vec3) XZY() Vec3 {
+	return Vec3{X: v.X, Y: v.Z, Z: v.Y}
+}
+
+func (v Vec3) YXZ() Vec3 {
+	return Vec3{X: v.Y, Y: v.X, Z: v.Z}
+}
+
+func (v Vec3) YZX() Vec3 {
+	return Vec3{X: v.Y, Y: v.Z, Z: v.X}
+}
+
+func (v Vec3) ZXY() Vec3 {
+	return Vec3{X: v.Z, Y: v.X, Z: v.Y}
+}
+
+func (v Vec3) ZYX() Vec3 {
+	return Vec3{X: v.Z, Y: v.Y, Z: v.X}
+}
+
+func (v Vec4) XY() Vec2 {
+	return Vec2{X: v.X, Y: v.Y}
+}
+
+func (v Vec4) XZ() Vec2 {
+	return Vec2{X: v.X, Y: v.Z}
+}
+
+func (v Vec4) YX() Vec2 {
+	return Vec2{X: v.Y, Y: v.X}
+}
+
+func (v Vec4) YZ() Vec2 {
+	return Vec2{X: v.Y, Y: v.Z}
+}
+
+func (v Vec4) ZX() Vec2 {
+	return Vec2{X: v.Z, Y: v.X}
+}
+
+func (v Vec4) ZY() Vec2 {
+	return Vec2{X: v.Z, Y: v.Y}
+}
+
+func (v Vec4) XYZ() Vec3 {
+	return Vec3{X: v.X, Y: v.Y, Z: v.Z}
+}
+
+func (v Vec4) XZY() Vec3 {
+	return Vec3{X: v.X, Y: v.Z, Z: v.Y}
+}
+
+func (v Vec4) YXZ() Vec3 {
+	return Vec3{X: v.Y, Y: v.X, Z: v.Z}
+}
+
+func (v Vec4) YZX() Vec3 {
+	return Vec3{X: v.Y, Y: v.Z, Z: v.X}
+}
+
+func (v Vec4) ZXY() Vec3 {
+	return Vec3{X: v.Z, Y: v.X, Z: v.Y}
+}
+
+func (v Vec4) ZYX() Vec3 {
+	return Vec3{X: v.Z, Y: v.Y, Z: v.X}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Tetrahedron is the solid with corners A, B, C, and D. It is positively
+// oriented when D is on the side of the triangle ABC from which A, B,
+// and C appear in counter-clockwise order.
+type Tetrahedron struct {
+	A, B, C, D Point
+}
+
+// Barycentric returns the barycentric weights of p with respect to the
+// corners A, B, C, and D, in that order, so that p is the weighted sum
+// of the corners and the weights sum to 1. It returns false if the
+// tetrahedron is degenerate.
+func (t Tetrahedron) Barycentric(p Point) ([4]float64, bool) {
+	vol := t.SignedVolume()
+	if vol == 0 {
+		return [4]float64{}, false
+	}
+	bary := [4]float64{
+		Tetrahedron{A: p, B: t.B, C: t.C, D: t.D}.SignedVolume() / vol,
+		Tetrahedron{A: t.A, B: p, C: t.C, D: t.D}.SignedVolume() / vol,
+		Tetrahedron{A: t.A, B: t.B, C: p, D: t.D}.SignedVolume() / vol,
+		Tetrahedron{A: t.A, B: t.B, C: t.C, D: p}.SignedVolume() / vol,
+	}
+	for _, w := range bary {
+		if math.IsInf(w, 0) || math.IsNaN(w) {
+			return [4]float64{}, false
+		}
+	}
+	return bary, true
+}
+
+// Centroid returns the center of mass of the tetrahedron, the average of
+// its corners.
+func (t Tetrahedron) Centroid() Point {
+	return Point{
+		X: (t.A.X + t.B.X + t.C.X + t.D.X) / 4,
+		Y: (t.A.Y + t.B.Y + t.C.Y + t.D.Y) / 4,
+		Z: (t.A.Z + t.B.Z + t.C.Z + t.D.Z) / 4,
+	}
+}
+
+// Circumsphere returns the sphere through the four corners.
+// It returns false if the tetrahedron is degenerate.
+//
+//	u = B − A, v = C − A, w = D − A
+//	o = A + (|u|²(v × w) + |v|²(w × u) + |w|²(u × v)) / 2u·(v × w)
+func (t Tetrahedron) Circumsphere() (Sphere, bool) {
+	u, v, w := t.B.Sub(t.A), t.C.Sub(t.A), t.D.Sub(t.A)
+	vw := v.Cross(w)
+	denom := 2 * u.Dot(vw)
+	if denom == 0 {
+		return Sphere{}, false
+	}
+	o := vw.Mul(u.LengthSquared()).Add(w.Cross(u).Mul(v.LengthSquared())).Add(u.Cross(v).Mul(w.LengthSquared())).Div(denom)
+	if !o.IsFinite() {
+		return Sphere{}, false
+	}
+	return Sphere{Center: t.A.Add(o), Radius: o.Length()}, true
+}
+
+// ContainsPoint reports whether p is inside the tetrahedron or on its
+// boundary. A degenerate tetrahedron contains no points.
+func (t Tetrahedron) ContainsPoint(p Point) bool {
+	_, ok := t.Locate(p)
+	return ok
+}
+
+// Locate reports whether p is inside the tetrahedron or on its boundary,
+// and returns its barycentric weights with respect to A, B, C, and D
+// when it is. Each weight comes from the volume that p forms with the
+// opposite face, measured relative to p, and weights that are negative
+// by no more than rounding error are taken to be zero as in
+// Triangle.Locate. A degenerate tetrahedron contains no points.
+func (t Tetrahedron) Locate(p Point) ([4]float64, bool) {
+	a, b, c, d := t.A.Sub(p), t.B.Sub(p), t.C.Sub(p), t.D.Sub(p)
+	w, ok := locateWeights([]float64{b.ScalarTriple(c, d), -a.ScalarTriple(c, d), a.ScalarTriple(b, d), -a.ScalarTriple(b, c)})
+	if !ok {
+		return [4]float64{}, false
+	}
+	return [4]float64{w[0], w[1], w[2], w[3]}, true
+}
+
+// SignedVolume returns the volume of the tetrahedron, which is negative
+// when the tetrahedron is negatively oriented.
+//
+//	V = (B − A)·((C − A) × (D − A)) / 6
+func (t Tetrahedron) SignedVolume() float64 {
+	return t.B.Sub(t.A).ScalarTriple(t.C.Sub(t.A), t.D.Sub(t.A)) / 6
+}
+
+// Support returns the vertex of the tetrahedron furthest in the
+// direction d. It implements the ConvexShape interface.
+func (t Tetrahedron) Support(d Vec3) Point {
+	return furthestPoint(d, t.A, t.B, t.C, t.D)
+}
+
+// Volume returns the volume of the tetrahedron.
+func (t Tetrahedron) Volume() float64 {
+	return math.Abs(t.SignedVolume())
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestTetrahedron(t *testing.T) {
+	o := math3d.Point{X: 1, Y: 1, Z: 1}
+	tet := math3d.Tetrahedron{A: o, B: math3d.Point{X: 3, Y: 1, Z: 1}, C: math3d.Point{X: 1, Y: 3, Z: 1}, D: math3d.Point{X: 1, Y: 1, Z: 3}}
+	if got := tet.SignedVolume(); math.Abs(got-8.0/6) > 1e-15 {
+		t.Errorf("SignedVolume: want %v, got %v\n", 8.0/6, got)
+	}
+	flipped := math3d.Tetrahedron{A: tet.A, B: tet.C, C: tet.B, D: tet.D}
+	if got := flipped.SignedVolume(); math.Abs(got+8.0/6) > 1e-15 || flipped.Volume() != tet.Volume() {
+		t.Errorf("SignedVolume: flipped: want %v, got %v\n", -8.0/6, got)
+	}
+	if got, want := tet.Centroid(), (math3d.Point{X: 1.5, Y: 1.5, Z: 1.5}); got != want {
+		t.Errorf("Centroid: want %v, got %v\n", want, got)
+	}
+
+	s, ok := tet.Circumsphere()
+	if want := (math3d.Point{X: 2, Y: 2, Z: 2}); !ok || !s.Center.ApproxEqual(want, 1e-12) || math.Abs(s.Radius-math.Sqrt(3)) > 1e-12 {
+		t.Errorf("Circumsphere: want center %v radius %v, got %v %v\n", want, math.Sqrt(3), s, ok)
+	}
+
+	for _, tt := range []struct {
+		p      math3d.Point
+		bary   [4]float64
+		inside bool
+	}{
+		{tet.A, [4]float64{1, 0, 0, 0}, true},
+		{tet.Centroid(), [4]float64{0.25, 0.25, 0.25, 0.25}, true},
+		{math3d.Point{X: 2, Y: 1, Z: 2}, [4]float64{0, 0.5, 0, 0.5}, true},
+		{math3d.Point{X: 3, Y: 3, Z: 1}, [4]float64{-1, 1, 1, 0}, false},
+	} {
+		bary, ok := tet.Barycentric(tt.p)
+		if !ok {
+			t.Errorf("Barycentric(%v): want ok, got !ok\n", tt.p)
+		}
+		for i := range bary {
+			if math.Abs(bary[i]-tt.bary[i]) > 1e-12 {
+				t.Errorf("Barycentric(%v): want %v, got %v\n", tt.p, tt.bary, bary)
+				break
+			}
+		}
+		if got := tet.ContainsPoint(tt.p); got != tt.inside {
+			t.Errorf("ContainsPoint(%v): want %v, got %v\n", tt.p, tt.inside, got)
+		}
+		if got, ok := tet.Locate(tt.p); ok != tt.inside || ok && got != bary {
+			t.Errorf("Locate(%v): want %v %v, got %v %v\n", tt.p, bary, tt.inside, got, ok)
+		}
+		if got := flipped.ContainsPoint(tt.p); got != tt.inside {
+			t.Errorf("ContainsPoint(%v): flipped: want %v, got %v\n", tt.p, tt.inside, got)
+		}
+	}
+
+	flat := math3d.Tetrahedron{A: o, B: math3d.Point{X: 2, Y: 1, Z: 1}, C: math3d.Point{X: 1, Y: 2, Z: 1}, D: math3d.Point{X: 2, Y: 2, Z: 1}}
+	if _, ok := flat.Circumsphere(); ok {
+		t.Errorf("Circumsphere: degenerate: want !ok, got ok\n")
+	}
+	if flat.ContainsPoint(o) {
+		t.Errorf("ContainsPoint: degenerate: want false, got true\n")
+	}
+}
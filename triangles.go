@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import "math"
+
+// Triangle is the triangle with corners A, B, and C. Its front face is
+// the side from which the corners appear in counter-clockwise order.
+type Triangle struct {
+	A, B, C Point
+}
+
+// ClosestPointOnTriangle returns the point on the triangle nearest to p.
+func ClosestPointOnTriangle(p Point, tr Triangle) Point {
+	return tr.ClosestPointTo(p)
+}
+
+// Area returns the area of the triangle.
+func (tr Triangle) Area() float64 {
+	return tr.B.Sub(tr.A).Cross(tr.C.Sub(tr.A)).Length() / 2
+}
+
+// Barycentric returns the barycentric weights (u, v, w) of the projection
+// of p onto the plane of the triangle, so that the projection is
+// uA + vB + wC and u + v + w = 1. It returns false if the triangle is
+// degenerate.
+func (tr Triangle) Barycentric(p Point) (Vec3, bool) {
+	// Ericson, Real-Time Collision Detection, §3.4
+	v0, v1, v2 := tr.B.Sub(tr.A), tr.C.Sub(tr.A), p.Sub(tr.A)
+	d00, d01, d11 := v0.Dot(v0), v0.Dot(v1), v1.Dot(v1)
+	d20, d21 := v2.Dot(v0), v2.Dot(v1)
+	denom := d00*d11 - d01*d01
+	if denom == 0 {
+		return Vec3{}, false
+	}
+	v := (d11*d20 - d01*d21) / denom
+	w := (d00*d21 - d01*d20) / denom
+	bary := Vec3{X: 1 - v - w, Y: v, Z: w}
+	return bary, bary.IsFinite()
+}
+
+// Centroid returns the center of mass of the triangle, the average of
+// its corners.
+func (tr Triangle) Centroid() Point {
+	return Point{
+		X: (tr.A.X + tr.B.X + tr.C.X) / 3,
+		Y: (tr.A.Y + tr.B.Y + tr.C.Y) / 3,
+		Z: (tr.A.Z + tr.B.Z + tr.C.Z) / 3,
+	}
+}
+
+// Circumcenter returns the center of the circle through the three
+// corners. It returns false if the triangle is degenerate.
+//
+//	a = A − C, b = B − C
+//	o = C + ((|a|²b − |b|²a) × (a × b)) / 2|a × b|²
+func (tr Triangle) Circumcenter() (Point, bool) {
+	a, b := tr.A.Sub(tr.C), tr.B.Sub(tr.C)
+	axb := a.Cross(b)
+	denom := 2 * axb.LengthSquared()
+	if denom == 0 {
+		return Point{}, false
+	}
+	o := tr.C.Add(b.Mul(a.LengthSquared()).Sub(a.Mul(b.LengthSquared())).Cross(axb).Div(denom))
+	return o, o.IsFinite()
+}
+
+// ClosestPointTo returns the point on the triangle nearest to p. It finds
+// which Voronoi region of the triangle's corners, edges, and face
+// contains p, following Ericson, Real-Time Collision Detection, §5.1.5.
+func (tr Triangle) ClosestPointTo(p Point) Point {
+	ab, ac, ap := tr.B.Sub(tr.A), tr.C.Sub(tr.A), p.Sub(tr.A)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return tr.A
+	}
+	bp := p.Sub(tr.B)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return tr.B
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return tr.A.Add(ab.Mul(d1 / (d1 - d3)))
+	}
+	cp := p.Sub(tr.C)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return tr.C
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return tr.A.Add(ac.Mul(d2 / (d2 - d6)))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return tr.B.Add(tr.C.Sub(tr.B).Mul((d4 - d3) / ((d4 - d3) + (d5 - d6))))
+	}
+	// inside the face
+	denom := va + vb + vc
+	return tr.A.Add(ab.Mul(vb / denom)).Add(ac.Mul(vc / denom))
+}
+
+// ContainsPoint reports whether the projection of p onto the plane of the
+// triangle lies inside the triangle or on its edges. A degenerate
+// triangle contains no points.
+func (tr Triangle) ContainsPoint(p Point) bool {
+	_, ok := tr.Locate(p)
+	return ok
+}
+
+// FromBarycentric returns the point uA + vB + wC for the weights (u, v, w).
+func (tr Triangle) FromBarycentric(bary Vec3) Point {
+	return tr.A.Vec3().Mul(bary.X).Add(tr.B.Vec3().Mul(bary.Y)).Add(tr.C.Vec3().Mul(bary.Z)).Point()
+}
+
+// Locate reports whether the projection of p onto the plane of the
+// triangle lies inside the triangle or on its edges, and returns its
+// barycentric weights (u, v, w) when it does. Each weight comes from the
+// area that p forms with the opposite edge, measured relative to p
+// rather than to a corner. Weights that are negative by no more than
+// rounding error are taken to be zero, so a point on an edge shared by
+// two triangles of a mesh is found in both rather than slipping between
+// them. A degenerate triangle contains no points.
+func (tr Triangle) Locate(p Point) (Vec3, bool) {
+	n := tr.B.Sub(tr.A).Cross(tr.C.Sub(tr.A))
+	a, b, c := tr.A.Sub(p), tr.B.Sub(p), tr.C.Sub(p)
+	w, ok := locateWeights([]float64{n.Dot(b.Cross(c)), n.Dot(c.Cross(a)), n.Dot(a.Cross(b))})
+	if !ok {
+		return Vec3{}, false
+	}
+	return Vec3{X: w[0], Y: w[1], Z: w[2]}, true
+}
+
+// Normal returns the unit normal of the front face of the triangle.
+// It returns the zero vector if the triangle is degenerate.
+func (tr Triangle) Normal() Vec3 {
+	return tr.B.Sub(tr.A).Cross(tr.C.Sub(tr.A)).NormalizeOrZero()
+}
+
+// Plane returns the plane of the triangle, with the normal of its front
+// face. It returns false if the triangle is degenerate.
+func (tr Triangle) Plane() (Plane, bool) {
+	return PlaneFromPoints(tr.A, tr.B, tr.C)
+}
+
+// Support returns the vertex of the triangle furthest in the direction d.
+// It implements the ConvexShape interface.
+func (tr Triangle) Support(d Vec3) Point {
+	return furthestPoint(d, tr.A, tr.B, tr.C)
+}
+
+// locateTolerance is the fraction of the total by which a weight may be
+// negative and still count as zero.
+const locateTolerance = 1e-12
+
+// locateWeights normalizes the unnormalized barycentric weights to sum
+// to 1. It returns false if any weight is negative beyond rounding error
+// or the weights are degenerate.
+func locateWeights(w []float64) ([]float64, bool) {
+	var sum float64
+	for _, x := range w {
+		sum += x
+	}
+	if sum == 0 || math.IsInf(sum, 0) || math.IsNaN(sum) {
+		return nil, false
+	}
+	// a negatively oriented simplex has all its weights negative inside
+	var total float64
+	for i := range w {
+		w[i] /= sum
+		if w[i] < -locateTolerance {
+			return nil, false
+		}
+		w[i] = math.Max(w[i], 0)
+		total += w[i]
+	}
+	for i := range w {
+		w[i] /= total
+	}
+	return w, true
+}
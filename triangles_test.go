@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestTriangle(t *testing.T) {
+	tr := math3d.Triangle{A: math3d.Point{X: 0, Y: 0, Z: 1}, B: math3d.Point{X: 4, Y: 0, Z: 1}, C: math3d.Point{X: 0, Y: 3, Z: 1}}
+	if got := tr.Area(); got != 6 {
+		t.Errorf("Area: want 6, got %v\n", got)
+	}
+	if got, want := tr.Normal(), math3d.NewVec3(0, 0, 1); got != want {
+		t.Errorf("Normal: want %v, got %v\n", want, got)
+	}
+	if got, want := tr.Centroid(), (math3d.Point{X: 4.0 / 3, Y: 1, Z: 1}); !got.ApproxEqual(want, 1e-15) {
+		t.Errorf("Centroid: want %v, got %v\n", want, got)
+	}
+	// the circumcenter of a right triangle is the midpoint of its hypotenuse
+	if got, ok := tr.Circumcenter(); !ok || !got.ApproxEqual(math3d.Point{X: 2, Y: 1.5, Z: 1}, 1e-12) {
+		t.Errorf("Circumcenter: want %v, got %v %v\n", math3d.Point{X: 2, Y: 1.5, Z: 1}, got, ok)
+	}
+	if pl, ok := tr.Plane(); !ok || pl.SignedDistance(math3d.Point{Z: 3}) != 2 {
+		t.Errorf("Plane: want z = 1, got %v %v\n", pl, ok)
+	}
+
+	for _, tt := range []struct {
+		p      math3d.Point
+		bary   math3d.Vec3
+		inside bool
+	}{
+		{tr.A, math3d.NewVec3(1, 0, 0), true},
+		{math3d.Point{X: 2, Y: 0, Z: 1}, math3d.NewVec3(0.5, 0.5, 0), true},
+		{math3d.Point{X: 1, Y: 1, Z: 5}, math3d.NewVec3(5.0/12, 0.25, 1.0/3), true},
+		{math3d.Point{X: 4, Y: 3, Z: 1}, math3d.NewVec3(-1, 1, 1), false},
+		{math3d.Point{X: -1, Y: 1, Z: 1}, math3d.NewVec3(1.0+1.0/4-1.0/3, -0.25, 1.0/3), false},
+	} {
+		bary, ok := tr.Barycentric(tt.p)
+		if !ok || !bary.ApproxEqual(tt.bary, 1e-12) {
+			t.Errorf("Barycentric(%v): want %v, got %v %v\n", tt.p, tt.bary, bary, ok)
+		}
+		if got := tr.ContainsPoint(tt.p); got != tt.inside {
+			t.Errorf("ContainsPoint(%v): want %v, got %v\n", tt.p, tt.inside, got)
+		}
+		if got, ok := tr.Locate(tt.p); ok != tt.inside || ok && !got.ApproxEqual(tt.bary, 1e-12) {
+			t.Errorf("Locate(%v): want %v %v, got %v %v\n", tt.p, tt.bary, tt.inside, got, ok)
+		}
+		proj := math3d.Point{X: tt.p.X, Y: tt.p.Y, Z: 1}
+		if got := tr.FromBarycentric(bary); !got.ApproxEqual(proj, 1e-12) {
+			t.Errorf("FromBarycentric(%v): want %v, got %v\n", bary, proj, got)
+		}
+	}
+
+	flat := math3d.Triangle{A: math3d.Point{}, B: math3d.Point{X: 1, Y: 1, Z: 1}, C: math3d.Point{X: 2, Y: 2, Z: 2}}
+	if _, ok := flat.Barycentric(math3d.Point{}); ok {
+		t.Errorf("Barycentric: degenerate: want !ok, got ok\n")
+	}
+	if _, ok := flat.Circumcenter(); ok {
+		t.Errorf("Circumcenter: degenerate: want !ok, got ok\n")
+	}
+	if flat.ContainsPoint(math3d.Point{}) {
+		t.Errorf("ContainsPoint: degenerate: want false, got true\n")
+	}
+	if n := flat.Normal(); n != (math3d.Vec3{}) || math.Abs(flat.Area()) > 0 {
+		t.Errorf("Normal: degenerate: want zero, got %v\n", n)
+	}
+
+	// points along the diagonal of a quad belong to one of its halves
+	a, b, c, d := math3d.Point{X: 0.1, Y: 0.3}, math3d.Point{X: 1.7, Y: 0.2}, math3d.Point{X: 1.9, Y: 1.3, Z: 0.4}, math3d.Point{X: 0.3, Y: 2.1, Z: 0.2}
+	t1, t2 := math3d.Triangle{A: a, B: b, C: c}, math3d.Triangle{A: a, B: c, C: d}
+	for i := 0; i <= 100; i++ {
+		p := a.Lerp(c, float64(i)/100)
+		_, ok1 := t1.Locate(p)
+		_, ok2 := t2.Locate(p)
+		if !ok1 && !ok2 {
+			t.Errorf("Locate(%v): diagonal: want ok, got !ok\n", p)
+		}
+	}
+}
+
+func TestClosestPointOnTriangle(t *testing.T) {
+	tr := math3d.Triangle{A: math3d.Point{X: 0, Y: 0, Z: 0}, B: math3d.Point{X: 4, Y: 0, Z: 0}, C: math3d.Point{X: 0, Y: 4, Z: 0}}
+	for _, tt := range []struct {
+		region string
+		p      math3d.Point
+		want   math3d.Point
+	}{
+		{"face", math3d.Point{X: 1, Y: 1, Z: 3}, math3d.Point{X: 1, Y: 1, Z: 0}},
+		{"corner A", math3d.Point{X: -1, Y: -2, Z: 1}, tr.A},
+		{"corner B", math3d.Point{X: 6, Y: -1, Z: -1}, tr.B},
+		{"corner C", math3d.Point{X: -1, Y: 5, Z: 2}, tr.C},
+		{"edge AB", math3d.Point{X: 2, Y: -3, Z: 1}, math3d.Point{X: 2, Y: 0, Z: 0}},
+		{"edge AC", math3d.Point{X: -2, Y: 1, Z: -1}, math3d.Point{X: 0, Y: 1, Z: 0}},
+		{"edge BC", math3d.Point{X: 3, Y: 3, Z: 5}, math3d.Point{X: 2, Y: 2, Z: 0}},
+	} {
+		if got := math3d.ClosestPointOnTriangle(tt.p, tr); !got.ApproxEqual(tt.want, 1e-12) {
+			t.Errorf("ClosestPointOnTriangle: %s: want %v, got %v\n", tt.region, tt.want, got)
+		}
+	}
+
+	// compare a tilted triangle against a dense sampling of its surface
+	tilted := math3d.Triangle{A: math3d.Point{X: 1, Y: 2, Z: 0}, B: math3d.Point{X: -1, Y: 0, Z: 3}, C: math3d.Point{X: 2, Y: -2, Z: 1}}
+	for _, p := range []math3d.Point{{X: 5, Y: 5, Z: 5}, {X: -3, Y: 1, Z: 0}, {X: 0.5, Y: 0, Z: 1.2}, {X: 2, Y: -4, Z: -1}} {
+		got := tilted.ClosestPointTo(p)
+		best := math.Inf(1)
+		const n = 200
+		for i := 0; i <= n; i++ {
+			for j := 0; i+j <= n; j++ {
+				u, v := float64(i)/n, float64(j)/n
+				q := tilted.FromBarycentric(math3d.NewVec3(1-u-v, u, v))
+				best = math.Min(best, p.Distance(q))
+			}
+		}
+		if d := p.Distance(got); d > best+1e-12 || d < best-0.05 {
+			t.Errorf("ClosestPointTo(%v): want distance about %v, got %v\n", p, best, d)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+import (
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Triangulation is a triangulation of points in the plane. Each element
+// of Triangles holds the indices of three points in counter-clockwise
+// order. The element of Neighbors with the same index holds the
+// triangles across its edges: Neighbors[t][i] is the triangle sharing
+// the edge from corner i to corner (i+1) mod 3 of triangle t, or −1 if
+// that edge is on the boundary of the convex hull.
+type Triangulation struct {
+	Triangles [][3]int
+	Neighbors [][3]int
+}
+
+// Delaunay2D returns the Delaunay triangulation of the points, in which
+// no point is strictly inside the circle through the corners of any
+// triangle. It inserts the points one at a time in Morton order with the
+// Bowyer–Watson algorithm, treating the outside of the hull as triangles
+// with a corner at infinity so that no enclosing triangle is needed. The
+// orientation and in-circle tests are exact, falling back from floating
+// point to rational arithmetic when rounding could change their sign, so
+// the result is correct for any input. Repeated points are used once,
+// and where four or more points lie on a circle, one of the possible
+// triangulations is chosen. It returns false if all the points lie on a
+// line.
+func Delaunay2D(points []Vec2) (Triangulation, bool) {
+	// three points not on a line start the triangulation
+	i0, i1, i2 := -1, -1, -1
+	for i, p := range points {
+		switch {
+		case i0 < 0:
+			i0 = i
+		case i1 < 0:
+			if p != points[i0] {
+				i1 = i
+			}
+		case orientExact(points[i0], points[i1], p) != 0:
+			i2 = i
+		}
+		if i2 >= 0 {
+			break
+		}
+	}
+	if i2 < 0 {
+		return Triangulation{}, false
+	}
+	if orientExact(points[i0], points[i1], points[i2]) < 0 {
+		i1, i2 = i2, i1
+	}
+	d := &delaunay{points: points}
+	d.tris = [][3]int{{i0, i1, i2}, {i1, i0, ghost}, {i2, i1, ghost}, {i0, i2, ghost}}
+	d.nbrs = [][3]int{{1, 2, 3}, {0, 3, 2}, {0, 1, 3}, {0, 2, 1}}
+	d.dead = make([]bool, 4)
+
+	// insert the rest in Morton order, so that each walk is short
+	bounds := EmptyAABB()
+	for _, p := range points {
+		bounds = bounds.Include(Point{X: p.X, Y: p.Y})
+	}
+	order := make([]int, 0, len(points))
+	keys := make([]uint64, len(points))
+	for i, p := range points {
+		if i != i0 && i != i1 && i != i2 {
+			order = append(order, i)
+			keys[i] = EncodeMorton3D(QuantizePoint(Point{X: p.X, Y: p.Y}, bounds))
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+	last := 0
+	for _, i := range order {
+		last = d.insert(i, last)
+	}
+	return d.result(), true
+}
+
+// ghost is the index of the point at infinity in a delaunay. A triangle
+// with a ghost corner stands for the half-plane outside the hull edge
+// between its other two corners.
+const ghost = -1
+
+// delaunay is a triangulation under construction by Delaunay2D. The
+// triangles and their neighbors are laid out as in Triangulation, and
+// triangles removed from the triangulation are marked dead.
+type delaunay struct {
+	points []Vec2
+	tris   [][3]int
+	nbrs   [][3]int
+	dead   []bool
+}
+
+// contains reports whether the point i is strictly inside the circle of
+// the triangle t. For a triangle with a ghost corner, that is the open
+// half-plane beyond its hull edge together with the inside of the edge.
+func (d *delaunay) contains(t, i int) bool {
+	v, p := d.tris[t], d.points[i]
+	if k := ghostCorner(v); k >= 0 {
+		a, b := d.points[v[(k+1)%3]], d.points[v[(k+2)%3]]
+		if o := orientExact(a, b, p); o != 0 {
+			return o > 0
+		}
+		return p.Sub(a).Dot(b.Sub(a)) > 0 && p.Sub(b).Dot(a.Sub(b)) > 0
+	}
+	return inCircleExact(d.points[v[0]], d.points[v[1]], d.points[v[2]], p) > 0
+}
+
+// insert adds the point i, starting the search for it at the triangle t,
+// and returns a triangle next to it to start the next search from.
+func (d *delaunay) insert(i, t int) int {
+	start := d.locate(i, t)
+	if start < 0 || !d.contains(start, i) {
+		return t // a repeated point
+	}
+
+	// the cavity is the triangles whose circles hold the point
+	cavity := []int{start}
+	d.dead[start] = true
+	type edge struct{ a, b, outside int }
+	var boundary []edge
+	for k := 0; k < len(cavity); k++ {
+		c := cavity[k]
+		for e := 0; e < 3; e++ {
+			n := d.nbrs[c][e]
+			if d.dead[n] {
+				continue
+			}
+			if d.contains(n, i) {
+				d.dead[n] = true
+				cavity = append(cavity, n)
+			} else {
+				boundary = append(boundary, edge{d.tris[c][e], d.tris[c][(e+1)%3], n})
+			}
+		}
+	}
+
+	// fan the cavity from the point, linking the new triangles to the
+	// ones outside and to each other around the point
+	from := map[int]int{}
+	for _, e := range boundary {
+		t := len(d.tris)
+		d.tris = append(d.tris, [3]int{e.a, e.b, i})
+		d.nbrs = append(d.nbrs, [3]int{e.outside, -1, -1})
+		d.dead = append(d.dead, false)
+		for k := range d.nbrs[e.outside] {
+			if d.tris[e.outside][k] == e.b && d.tris[e.outside][(k+1)%3] == e.a {
+				d.nbrs[e.outside][k] = t
+			}
+		}
+		from[e.a] = t
+	}
+	for _, t := range from {
+		next := from[d.tris[t][1]]
+		d.nbrs[t][1], d.nbrs[next][2] = next, t
+	}
+	return len(d.tris) - 1
+}
+
+// locate walks from the triangle t towards the point i and returns the
+// triangle holding it, or a triangle with a ghost corner whose hull edge
+// the point is beyond.
+func (d *delaunay) locate(i, t int) int {
+	p := d.points[i]
+	for steps := 0; steps < len(d.tris); steps++ {
+		v := d.tris[t]
+		if k := ghostCorner(v); k >= 0 {
+			if d.contains(t, i) {
+				return t
+			}
+			// step back inside across the hull edge
+			t = d.nbrs[t][(k+1)%3]
+			continue
+		}
+		moved := false
+		for k := 0; k < 3; k++ {
+			// vary the first edge tried so the walk cannot circle
+			e := (k + steps) % 3
+			if orientExact(d.points[v[e]], d.points[v[(e+1)%3]], p) < 0 {
+				t, moved = d.nbrs[t][e], true
+				break
+			}
+		}
+		if !moved {
+			return t
+		}
+	}
+	// the walk is guaranteed to end in a Delaunay triangulation, but
+	// search everything rather than trust that
+	for t := range d.tris {
+		if !d.dead[t] && d.contains(t, i) {
+			return t
+		}
+	}
+	return -1
+}
+
+// result returns the live triangles without ghost corners, renumbered.
+func (d *delaunay) result() Triangulation {
+	index := make([]int, len(d.tris))
+	var tr Triangulation
+	for t, v := range d.tris {
+		index[t] = -1
+		if !d.dead[t] && ghostCorner(v) < 0 {
+			index[t] = len(tr.Triangles)
+			tr.Triangles = append(tr.Triangles, v)
+		}
+	}
+	for t := range d.tris {
+		if index[t] >= 0 {
+			n := d.nbrs[t]
+			tr.Neighbors = append(tr.Neighbors, [3]int{index[n[0]], index[n[1]], index[n[2]]})
+		}
+	}
+	return tr
+}
+
+// ghostCorner returns the position of the ghost corner of the triangle
+// with corners v, or −1 if it has none.
+func ghostCorner(v [3]int) int {
+	for k, i := range v {
+		if i == ghost {
+			return k
+		}
+	}
+	return -1
+}
+
+// Error bounds on the floating-point orientation and in-circle tests
+// relative to the magnitudes of their terms, from Shewchuk, "Adaptive
+// Precision Floating-Point Arithmetic and Fast Robust Geometric
+// Predicates" (1997). Within the bound, the sign is found exactly.
+const (
+	predicateEpsilon   = 1.0 / (1 << 53)
+	orientErrorBound   = (3 + 16*predicateEpsilon) * predicateEpsilon
+	inCircleErrorBound = (10 + 96*predicateEpsilon) * predicateEpsilon
+)
+
+// orientExact returns a value with the sign of the orientation of the
+// points a, b, and c: positive when they are in counter-clockwise order,
+// negative when clockwise, and zero exactly when they are on a line.
+func orientExact(a, b, c Vec2) float64 {
+	l, r := (a.X-c.X)*(b.Y-c.Y), (a.Y-c.Y)*(b.X-c.X)
+	det := l - r
+	if math.Abs(det) > orientErrorBound*(math.Abs(l)+math.Abs(r)) {
+		return det
+	}
+	ax, ay, bx, by, cx, cy := rat(a.X), rat(a.Y), rat(b.X), rat(b.Y), rat(c.X), rat(c.Y)
+	acx, bcy := new(big.Rat).Sub(ax, cx), new(big.Rat).Sub(by, cy)
+	acy, bcx := new(big.Rat).Sub(ay, cy), new(big.Rat).Sub(bx, cx)
+	exact := new(big.Rat).Sub(new(big.Rat).Mul(acx, bcy), new(big.Rat).Mul(acy, bcx))
+	return float64(exact.Sign())
+}
+
+// inCircleExact returns a value that is positive when d is strictly
+// inside the circle through a, b, and c, which must be in
+// counter-clockwise order, negative when it is outside, and zero exactly
+// when it is on the circle.
+func inCircleExact(a, b, c, d Vec2) float64 {
+	adx, ady, bdx, bdy, cdx, cdy := a.X-d.X, a.Y-d.Y, b.X-d.X, b.Y-d.Y, c.X-d.X, c.Y-d.Y
+	bc, cb := bdx*cdy, cdx*bdy
+	ca, ac := cdx*ady, adx*cdy
+	ab, ba := adx*bdy, bdx*ady
+	alift, blift, clift := adx*adx+ady*ady, bdx*bdx+bdy*bdy, cdx*cdx+cdy*cdy
+	det := alift*(bc-cb) + blift*(ca-ac) + clift*(ab-ba)
+	permanent := (math.Abs(bc)+math.Abs(cb))*alift + (math.Abs(ca)+math.Abs(ac))*blift + (math.Abs(ab)+math.Abs(ba))*clift
+	if math.Abs(det) > inCircleErrorBound*permanent {
+		return det
+	}
+	var x, y, lift [3]*big.Rat
+	for k, p := range [3]Vec2{a, b, c} {
+		x[k], y[k] = new(big.Rat).Sub(rat(p.X), rat(d.X)), new(big.Rat).Sub(rat(p.Y), rat(d.Y))
+		lift[k] = new(big.Rat).Add(new(big.Rat).Mul(x[k], x[k]), new(big.Rat).Mul(y[k], y[k]))
+	}
+	exact := new(big.Rat)
+	for k := range lift {
+		i, j := (k+1)%3, (k+2)%3
+		minor := new(big.Rat).Sub(new(big.Rat).Mul(x[i], y[j]), new(big.Rat).Mul(x[j], y[i]))
+		exact.Add(exact, minor.Mul(minor, lift[k]))
+	}
+	return float64(exact.Sign())
+}
+
+// rat returns x as an exact rational number.
+func rat(x float64) *big.Rat {
+	return new(big.Rat).SetFloat64(x)
+}
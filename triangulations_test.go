@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math/rand"
+	"testing"
+)
+
+func TestDelaunay2D(t *testing.T) {
+	// checkDelaunay reports triangles that are not counter-clockwise,
+	// have a point inside their circles, or disagree with their neighbors
+	checkDelaunay := func(name string, tr math3d.Triangulation, points []math3d.Vec2) {
+		for ti, v := range tr.Triangles {
+			a, b, c := points[v[0]], points[v[1]], points[v[2]]
+			if b.Sub(a).Cross(c.Sub(a)) <= 0 {
+				t.Errorf("Delaunay2D: %s: want counter-clockwise, got %v\n", name, v)
+			}
+			ab, ac := b.Sub(a), c.Sub(a)
+			for _, p := range points {
+				ap := p.Sub(a)
+				m := [3][3]float64{
+					{ab.X, ab.Y, ab.LengthSquared()},
+					{ac.X, ac.Y, ac.LengthSquared()},
+					{ap.X, ap.Y, ap.LengthSquared()},
+				}
+				// the determinant is negative when p is inside the circle
+				det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) - m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) + m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+				if det < -1e-9 {
+					t.Errorf("Delaunay2D: %s: want %v outside the circle of %v, got %v\n", name, p, v, det)
+				}
+			}
+			for e, n := range tr.Neighbors[ti] {
+				if n < 0 {
+					continue
+				}
+				back := -1
+				for k := range tr.Neighbors[n] {
+					if tr.Neighbors[n][k] == ti && tr.Triangles[n][k] == v[(e+1)%3] && tr.Triangles[n][(k+1)%3] == v[e] {
+						back = k
+					}
+				}
+				if back < 0 {
+					t.Errorf("Delaunay2D: %s: want %v to share an edge with %v, got %v\n", name, v, tr.Triangles[n], tr.Neighbors[n])
+				}
+			}
+		}
+		// every point is a corner, so Euler's formula fixes the count
+		if want := 2*len(points) - 2 - len(math3d.ConvexHull2D(points, true)); len(tr.Triangles) != want {
+			t.Errorf("Delaunay2D: %s: want %d triangles, got %d\n", name, want, len(tr.Triangles))
+		}
+	}
+
+	square := []math3d.Vec2{math3d.NewVec2(0, 0), math3d.NewVec2(1, 0), math3d.NewVec2(1, 1), math3d.NewVec2(0, 1)}
+	tr, ok := math3d.Delaunay2D(square)
+	if !ok || len(tr.Triangles) != 2 {
+		t.Errorf("Delaunay2D: square: want 2 triangles, got %v %v\n", tr, ok)
+	}
+	checkDelaunay("square", tr, square)
+
+	rng := rand.New(rand.NewSource(847))
+	var scattered []math3d.Vec2
+	for i := 0; i < 300; i++ {
+		scattered = append(scattered, math3d.NewVec2(rng.Float64()*100, rng.Float64()*50))
+	}
+	tr, ok = math3d.Delaunay2D(scattered)
+	if !ok {
+		t.Errorf("Delaunay2D: scattered: want ok, got !ok\n")
+	}
+	checkDelaunay("scattered", tr, scattered)
+
+	// a grid has many points on each circle and on each hull edge
+	var grid []math3d.Vec2
+	for i := 0; i < 100; i++ {
+		grid = append(grid, math3d.NewVec2(float64(i%10)*0.1, float64(i/10)*0.1))
+	}
+	tr, ok = math3d.Delaunay2D(grid)
+	if !ok {
+		t.Errorf("Delaunay2D: grid: want ok, got !ok\n")
+	}
+	checkDelaunay("grid", tr, grid)
+
+	for _, points := range [][]math3d.Vec2{nil, {{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 1}}, {{}, {X: 1, Y: 2}, {X: 2, Y: 4}, {X: -1, Y: -2}}} {
+		if _, ok := math3d.Delaunay2D(points); ok {
+			t.Errorf("Delaunay2D(%v): want !ok, got ok\n", points)
+		}
+	}
+}
@@ -51,6 +51,11 @@ func ZeroVector(n int) Vector {
 	return v
 }
 
+// Abs returns a vector with the absolute value of each component.
+func (v Vector) Abs() Vector {
+	return v.Apply(math.Abs)
+}
+
 func (v Vector) Add(w Vector) Vector {
 	u := make(Vector, len(v), len(v))
 	for i, s := range v {
@@ -59,6 +64,103 @@ func (v Vector) Add(w Vector) Vector {
 	return u
 }
 
+// Apply returns a vector with fn applied to each component.
+func (v Vector) Apply(fn func(float64) float64) Vector {
+	u := make(Vector, len(v), len(v))
+	for i, s := range v {
+		u[i] = fn(s)
+	}
+	return u
+}
+
+// AngleBetween returns the angle, in radians, between the two vectors.
+// It uses Kahan's formula, which stays accurate for nearly parallel and
+// nearly opposite vectors where acos of the normalized dot product does not.
+// Returns 0 if either vector is zero.
+func (v Vector) AngleBetween(w Vector) float64 {
+	if v.IsZero() || w.IsZero() {
+		return 0
+	}
+	a, b := v.Mul(w.Length()), w.Mul(v.Length())
+	return 2 * math.Atan2(a.Sub(b).Length(), a.Add(b).Length())
+}
+
+// ApproxEqual reports whether each component of v is within epsilon of
+// the corresponding component of w. Vectors of different lengths are
+// never equal.
+func (v Vector) ApproxEqual(w Vector, epsilon float64) bool {
+	if len(v) != len(w) {
+		return false
+	}
+	for i, s := range v {
+		if !ApproxEqual(s, w[i], epsilon) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApproxEqualTol reports whether each component of v is equal to the
+// corresponding component of w within the absolute or relative tolerance.
+// Vectors of different lengths are never equal.
+func (v Vector) ApproxEqualTol(w Vector, absTol, relTol float64) bool {
+	if len(v) != len(w) {
+		return false
+	}
+	for i, s := range v {
+		if !ApproxEqualTol(s, w[i], absTol, relTol) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ceil returns a vector with each component rounded up to an integer value.
+func (v Vector) Ceil() Vector {
+	return v.Apply(math.Ceil)
+}
+
+// ChebyshevDistance implements the maximum norm (L∞) of the vector,
+// the largest absolute value of any component.
+func (v Vector) ChebyshevDistance() float64 {
+	var max float64
+	for _, s := range v {
+		max = math.Max(max, math.Abs(s))
+	}
+	return max
+}
+
+// Clamp returns a vector with each component limited to the
+// range given by the corresponding components of lo and hi.
+func (v Vector) Clamp(lo, hi Vector) Vector {
+	u := make(Vector, len(v), len(v))
+	for i, s := range v {
+		u[i] = Clamp(s, lo[i], hi[i])
+	}
+	return u
+}
+
+// ClampLength returns the vector scaled down, if needed, so that its
+// length is no greater than max.
+func (v Vector) ClampLength(max float64) Vector {
+	return v.ClampLengthRange(0, max)
+}
+
+// ClampLengthRange returns the vector rescaled, if needed, so that its
+// length is in the range [min, max]. A zero vector has no direction
+// and is returned unchanged.
+func (v Vector) ClampLengthRange(min, max float64) Vector {
+	length := v.Length()
+	if length == 0 {
+		return v.ZeroVector()
+	} else if length < min {
+		return v.Mul(min / length)
+	} else if length > max {
+		return v.Mul(max / length)
+	}
+	return NewVector(v...)
+}
+
 func (v Vector) Div(scalar float64) Vector {
 	u := make(Vector, len(v), len(v))
 	for i, s := range v {
@@ -67,7 +169,37 @@ func (v Vector) Div(scalar float64) Vector {
 	return u
 }
 
-func (v Vector) Dot(w Vector) Vector {
+// Dot returns the scalar (inner) product of the two vectors.
+func (v Vector) Dot(w Vector) float64 {
+	var sum float64
+	for i, s := range v {
+		sum = sum + s*w[i]
+	}
+	return sum
+}
+
+// EqualWithinULP reports whether each component of v is no more than ulps
+// representable float64 values from the corresponding component of w.
+// Vectors of different lengths are never equal.
+func (v Vector) EqualWithinULP(w Vector, ulps uint64) bool {
+	if len(v) != len(w) {
+		return false
+	}
+	for i, s := range v {
+		if !EqualWithinULP(s, w[i], ulps) {
+			return false
+		}
+	}
+	return true
+}
+
+// Floor returns a vector with each component rounded down to an integer value.
+func (v Vector) Floor() Vector {
+	return v.Apply(math.Floor)
+}
+
+// Hadamard returns the component-wise product of the two vectors.
+func (v Vector) Hadamard(w Vector) Vector {
 	u := make(Vector, len(v), len(v))
 	for i, s := range v {
 		u[i] = s * w[i]
@@ -75,6 +207,26 @@ func (v Vector) Dot(w Vector) Vector {
 	return u
 }
 
+// HasNaN reports whether any component of the vector is NaN.
+func (v Vector) HasNaN() bool {
+	for _, s := range v {
+		if math.IsNaN(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinite reports whether every component of the vector is neither NaN nor infinite.
+func (v Vector) IsFinite() bool {
+	for _, s := range v {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			return false
+		}
+	}
+	return true
+}
+
 func (v Vector) IsZero() bool {
 	for _, s := range v {
 		if s != 0 {
@@ -107,6 +259,33 @@ func (v Vector) ManhattanDistance() float64 {
 	return sum
 }
 
+// Lerp returns the linear interpolation between v and w.
+func (v Vector) Lerp(w Vector, t float64) Vector {
+	u := make(Vector, len(v), len(v))
+	for i, s := range v {
+		u[i] = Lerp(s, w[i], t)
+	}
+	return u
+}
+
+// Max returns the component-wise maximum of the two vectors.
+func (v Vector) Max(w Vector) Vector {
+	u := make(Vector, len(v), len(v))
+	for i, s := range v {
+		u[i] = math.Max(s, w[i])
+	}
+	return u
+}
+
+// Min returns the component-wise minimum of the two vectors.
+func (v Vector) Min(w Vector) Vector {
+	u := make(Vector, len(v), len(v))
+	for i, s := range v {
+		u[i] = math.Min(s, w[i])
+	}
+	return u
+}
+
 func (v Vector) Mul(scalar float64) Vector {
 	u := make(Vector, len(v), len(v))
 	for i, s := range v {
@@ -115,7 +294,39 @@ func (v Vector) Mul(scalar float64) Vector {
 	return u
 }
 
-// Normalize returns a vector with all components divided by the vector's length
+// MulAdd returns v + scale*w, computing each component with a single
+// rounding using a fused multiply-add.
+func (v Vector) MulAdd(scale float64, w Vector) Vector {
+	u := make(Vector, len(v), len(v))
+	for i, s := range v {
+		u[i] = math.FMA(scale, w[i], s)
+	}
+	return u
+}
+
+// Norm implements the Minkowski p-norm of the vector for p ≥ 1.
+// Norm(1) is the Manhattan distance, Norm(2) is the Euclidean length,
+// and Norm(math.Inf(1)) is the Chebyshev distance.
+//
+//	‖v‖ₚ = (Σ|vᵢ|ᵖ)^(1/p)
+func (v Vector) Norm(p float64) float64 {
+	switch {
+	case p == 1:
+		return v.ManhattanDistance()
+	case p == 2:
+		return v.Length()
+	case math.IsInf(p, 1):
+		return v.ChebyshevDistance()
+	}
+	var sum float64
+	for _, s := range v {
+		sum = sum + math.Pow(math.Abs(s), p)
+	}
+	return math.Pow(sum, 1/p)
+}
+
+// Normalize returns a vector with all components divided by the vector's length.
+// It returns the zero vector if v is zero; see Normalized to detect that case.
 func (v Vector) Normalize() Vector {
 	if v.IsZero() {
 		return v.ZeroVector()
@@ -125,6 +336,59 @@ func (v Vector) Normalize() Vector {
 	return v.Mul(reciprocal)
 }
 
+// NormalizeOrZero returns the unit vector in the direction of v,
+// or the zero vector if v has no direction.
+func (v Vector) NormalizeOrZero() Vector {
+	if u, ok := v.Normalized(); ok {
+		return u
+	}
+	return v.ZeroVector()
+}
+
+// Normalized returns the unit vector in the direction of v.
+// It returns false if v is zero or has a component that is not finite,
+// in which case the direction is unavailable. Like math.Hypot, it
+// scales v by its largest component first, so very large and very
+// small vectors do not overflow or underflow.
+func (v Vector) Normalized() (Vector, bool) {
+	var largest float64
+	for _, x := range v {
+		largest = math.Max(largest, math.Abs(x))
+	}
+	if largest == 0 || math.IsInf(largest, 0) || math.IsNaN(largest) {
+		return v.ZeroVector(), false
+	}
+	u := v.Apply(func(x float64) float64 { return x / largest })
+	return u.Mul(1.0 / u.Length()), true
+}
+
+// OuterProduct returns the len(v)×len(w) matrix v⊗w whose element
+// in row i and column j is v[i]*w[j].
+func (v Vector) OuterProduct(w Vector) Matrix {
+	m := NewMatrix(len(v), len(w))
+	for i, s := range v {
+		for j, t := range w {
+			m[i][j] = s * t
+		}
+	}
+	return m
+}
+
+// Round returns a vector with each component rounded to the nearest
+// integer value, rounding half away from zero.
+func (v Vector) Round() Vector {
+	return v.Apply(math.Round)
+}
+
+// SetLength returns a vector with the same direction as v and the given length.
+// A zero vector has no direction and is returned unchanged.
+func (v Vector) SetLength(length float64) Vector {
+	if v.IsZero() {
+		return v.ZeroVector()
+	}
+	return v.Mul(length / v.Length())
+}
+
 func (v Vector) StandardBasis() []Vector {
 	return StandardBasisVector(len(v))
 }
@@ -178,16 +442,105 @@ func UnitVec2() Vec2 {
 	return UnitVector(2).toVec2()
 }
 
+// Abs returns a vector with the absolute value of each component.
+func (v Vec2) Abs() Vec2 {
+	return v.toVector().Abs().toVec2()
+}
+
 func (v Vec2) Add(w Vec2) Vec2 {
 	return v.toVector().Add(w.toVector()).toVec2()
 }
 
+// Apply returns a vector with fn applied to each component.
+func (v Vec2) Apply(fn func(float64) float64) Vec2 {
+	return v.toVector().Apply(fn).toVec2()
+}
+
+// AngleBetween returns the unsigned angle, in radians, between the two vectors.
+func (v Vec2) AngleBetween(w Vec2) float64 {
+	return v.toVector().AngleBetween(w.toVector())
+}
+
+// ApproxEqual reports whether each component of v is within epsilon of
+// the corresponding component of w.
+func (v Vec2) ApproxEqual(w Vec2, epsilon float64) bool {
+	return v.toVector().ApproxEqual(w.toVector(), epsilon)
+}
+
+// ApproxEqualTol reports whether each component of v is equal to the
+// corresponding component of w within the absolute or relative tolerance.
+func (v Vec2) ApproxEqualTol(w Vec2, absTol, relTol float64) bool {
+	return v.toVector().ApproxEqualTol(w.toVector(), absTol, relTol)
+}
+
+// Ceil returns a vector with each component rounded up to an integer value.
+func (v Vec2) Ceil() Vec2 {
+	return v.toVector().Ceil().toVec2()
+}
+
+// ChebyshevDistance implements the maximum norm (L∞) of the vector.
+func (v Vec2) ChebyshevDistance() float64 {
+	return v.toVector().ChebyshevDistance()
+}
+
+// Clamp returns a vector with each component limited to the
+// range given by the corresponding components of lo and hi.
+func (v Vec2) Clamp(lo, hi Vec2) Vec2 {
+	return v.toVector().Clamp(lo.toVector(), hi.toVector()).toVec2()
+}
+
+// Cross returns the z-component of the cross product of the two vectors
+// when they are extended into the xy-plane. The result is positive when
+// w is counter-clockwise from v.
+func (v Vec2) Cross(w Vec2) float64 {
+	return v.X*w.Y - v.Y*w.X
+}
+
+// ClampLength returns the vector scaled down, if needed, so that its
+// length is no greater than max.
+func (v Vec2) ClampLength(max float64) Vec2 {
+	return v.toVector().ClampLength(max).toVec2()
+}
+
+// ClampLengthRange returns the vector rescaled, if needed, so that its
+// length is in the range [min, max].
+func (v Vec2) ClampLengthRange(min, max float64) Vec2 {
+	return v.toVector().ClampLengthRange(min, max).toVec2()
+}
+
 func (v Vec2) Div(scalar float64) Vec2 {
 	return v.toVector().Div(scalar).toVec2()
 }
 
-func (v Vec2) Dot(w Vec2) Vec2 {
-	return v.toVector().Dot(w.toVector()).toVec2()
+// Dot returns the scalar (inner) product of the two vectors.
+func (v Vec2) Dot(w Vec2) float64 {
+	return v.toVector().Dot(w.toVector())
+}
+
+// EqualWithinULP reports whether each component of v is no more than ulps
+// representable float64 values from the corresponding component of w.
+func (v Vec2) EqualWithinULP(w Vec2, ulps uint64) bool {
+	return v.toVector().EqualWithinULP(w.toVector(), ulps)
+}
+
+// Floor returns a vector with each component rounded down to an integer value.
+func (v Vec2) Floor() Vec2 {
+	return v.toVector().Floor().toVec2()
+}
+
+// Hadamard returns the component-wise product of the two vectors.
+func (v Vec2) Hadamard(w Vec2) Vec2 {
+	return v.toVector().Hadamard(w.toVector()).toVec2()
+}
+
+// HasNaN reports whether any component of the vector is NaN.
+func (v Vec2) HasNaN() bool {
+	return v.toVector().HasNaN()
+}
+
+// IsFinite reports whether every component of the vector is neither NaN nor infinite.
+func (v Vec2) IsFinite() bool {
+	return v.toVector().IsFinite()
 }
 
 func (v Vec2) IsZero() bool {
@@ -209,14 +562,89 @@ func (v Vec2) ManhattanDistance() float64 {
 	return v.toVector().ManhattanDistance()
 }
 
+// Lerp returns the linear interpolation between v and w.
+func (v Vec2) Lerp(w Vec2, t float64) Vec2 {
+	return v.toVector().Lerp(w.toVector(), t).toVec2()
+}
+
+// Max returns the component-wise maximum of the two vectors.
+func (v Vec2) Max(w Vec2) Vec2 {
+	return v.toVector().Max(w.toVector()).toVec2()
+}
+
+// Min returns the component-wise minimum of the two vectors.
+func (v Vec2) Min(w Vec2) Vec2 {
+	return v.toVector().Min(w.toVector()).toVec2()
+}
+
 func (v Vec2) Mul(scalar float64) Vec2 {
 	return v.toVector().Mul(scalar).toVec2()
 }
 
+// MulAdd returns v + scale*w, computing each component with a single
+// rounding using a fused multiply-add.
+func (v Vec2) MulAdd(scale float64, w Vec2) Vec2 {
+	return Vec2{X: math.FMA(scale, w.X, v.X), Y: math.FMA(scale, w.Y, v.Y)}
+}
+
+// Norm implements the Minkowski p-norm of the vector for p ≥ 1.
+func (v Vec2) Norm(p float64) float64 {
+	return v.toVector().Norm(p)
+}
+
 func (v Vec2) Normalize() Vec2 {
 	return v.toVector().Normalize().toVec2()
 }
 
+// NormalizeOrZero returns the unit vector in the direction of v,
+// or the zero vector if v has no direction.
+func (v Vec2) NormalizeOrZero() Vec2 {
+	return v.toVector().NormalizeOrZero().toVec2()
+}
+
+// Normalized returns the unit vector in the direction of v.
+// It returns false if v is zero or has a component that is not finite.
+func (v Vec2) Normalized() (Vec2, bool) {
+	u, ok := v.toVector().Normalized()
+	return u.toVec2(), ok
+}
+
+// Perp returns the vector rotated 90° counter-clockwise.
+func (v Vec2) Perp() Vec2 {
+	return Vec2{X: -v.Y, Y: v.X}
+}
+
+// Reflect returns the vector reflected off a surface with the given normal.
+// The normal is expected to be normalized.
+//
+//	r = v − 2(v·n)n
+func (v Vec2) Reflect(normal Vec2) Vec2 {
+	return v.Sub(normal.Mul(2 * v.Dot(normal)))
+}
+
+// Rotate returns the vector rotated counter-clockwise by the given angle in radians.
+func (v Vec2) Rotate(radians float64) Vec2 {
+	sin, cos := math.Sincos(radians)
+	return Vec2{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos}
+}
+
+// Round returns a vector with each component rounded to the nearest integer value.
+func (v Vec2) Round() Vec2 {
+	return v.toVector().Round().toVec2()
+}
+
+// SignedAngle returns the angle, in radians, to rotate v onto w.
+// The result is in the range [−π, π] and is positive when the
+// rotation is counter-clockwise.
+func (v Vec2) SignedAngle(w Vec2) float64 {
+	return math.Atan2(v.Cross(w), v.Dot(w))
+}
+
+// SetLength returns a vector with the same direction as v and the given length.
+func (v Vec2) SetLength(length float64) Vec2 {
+	return v.toVector().SetLength(length).toVec2()
+}
+
 func (v Vec2) StandardBasis() []Vec2 {
 	return StandardBasisVec2()
 }
@@ -242,10 +670,358 @@ type Vec3 struct {
 	X, Y, Z float64
 }
 
+// Abs returns a vector with the absolute value of each component.
+func (v Vec3) Abs() Vec3 {
+	return v.toVector().Abs().toVec3()
+}
+
+func (v Vec3) Add(w Vec3) Vec3 {
+	return v.toVector().Add(w.toVector()).toVec3()
+}
+
+// Apply returns a vector with fn applied to each component.
+func (v Vec3) Apply(fn func(float64) float64) Vec3 {
+	return v.toVector().Apply(fn).toVec3()
+}
+
+// AngleBetween returns the unsigned angle, in radians, between the two vectors.
+func (v Vec3) AngleBetween(w Vec3) float64 {
+	return v.toVector().AngleBetween(w.toVector())
+}
+
+// ApproxEqual reports whether each component of v is within epsilon of
+// the corresponding component of w.
+func (v Vec3) ApproxEqual(w Vec3, epsilon float64) bool {
+	return v.toVector().ApproxEqual(w.toVector(), epsilon)
+}
+
+// ApproxEqualTol reports whether each component of v is equal to the
+// corresponding component of w within the absolute or relative tolerance.
+func (v Vec3) ApproxEqualTol(w Vec3, absTol, relTol float64) bool {
+	return v.toVector().ApproxEqualTol(w.toVector(), absTol, relTol)
+}
+
+// Ceil returns a vector with each component rounded up to an integer value.
+func (v Vec3) Ceil() Vec3 {
+	return v.toVector().Ceil().toVec3()
+}
+
+// ChebyshevDistance implements the maximum norm (L∞) of the vector.
+func (v Vec3) ChebyshevDistance() float64 {
+	return v.toVector().ChebyshevDistance()
+}
+
+// Clamp returns a vector with each component limited to the
+// range given by the corresponding components of lo and hi.
+func (v Vec3) Clamp(lo, hi Vec3) Vec3 {
+	return v.toVector().Clamp(lo.toVector(), hi.toVector()).toVec3()
+}
+
+// Cross returns the cross product of the two vectors.
+//
+//	v × w = ⟨vy*wz − vz*wy, vz*wx − vx*wz, vx*wy − vy*wx⟩
+func (v Vec3) Cross(w Vec3) Vec3 {
+	return Vec3{
+		X: v.Y*w.Z - v.Z*w.Y,
+		Y: v.Z*w.X - v.X*w.Z,
+		Z: v.X*w.Y - v.Y*w.X,
+	}
+}
+
+// ClampLength returns the vector scaled down, if needed, so that its
+// length is no greater than max.
+func (v Vec3) ClampLength(max float64) Vec3 {
+	return v.toVector().ClampLength(max).toVec3()
+}
+
+// ClampLengthRange returns the vector rescaled, if needed, so that its
+// length is in the range [min, max].
+func (v Vec3) ClampLengthRange(min, max float64) Vec3 {
+	return v.toVector().ClampLengthRange(min, max).toVec3()
+}
+
+func (v Vec3) Div(scalar float64) Vec3 {
+	return v.toVector().Div(scalar).toVec3()
+}
+
+// Dot returns the scalar (inner) product of the two vectors.
+func (v Vec3) Dot(w Vec3) float64 {
+	return v.toVector().Dot(w.toVector())
+}
+
+// EqualWithinULP reports whether each component of v is no more than ulps
+// representable float64 values from the corresponding component of w.
+func (v Vec3) EqualWithinULP(w Vec3, ulps uint64) bool {
+	return v.toVector().EqualWithinULP(w.toVector(), ulps)
+}
+
+// Floor returns a vector with each component rounded down to an integer value.
+func (v Vec3) Floor() Vec3 {
+	return v.toVector().Floor().toVec3()
+}
+
+// Hadamard returns the component-wise product of the two vectors.
+func (v Vec3) Hadamard(w Vec3) Vec3 {
+	return v.toVector().Hadamard(w.toVector()).toVec3()
+}
+
+// HasNaN reports whether any component of the vector is NaN.
+func (v Vec3) HasNaN() bool {
+	return v.toVector().HasNaN()
+}
+
+// IsFinite reports whether every component of the vector is neither NaN nor infinite.
+func (v Vec3) IsFinite() bool {
+	return v.toVector().IsFinite()
+}
+
+func (v Vec3) IsZero() bool {
+	return v.toVector().IsZero()
+}
+
+// Length implements the Euclidean norm of the vector.
+func (v Vec3) Length() float64 {
+	return v.toVector().Length()
+}
+
+// LengthSquared implements the square of the Euclidean norm of the vector.
+func (v Vec3) LengthSquared() float64 {
+	return v.toVector().LengthSquared()
+}
+
+// ManhattanDistance implements the step-wise total distance of the vector.
+func (v Vec3) ManhattanDistance() float64 {
+	return v.toVector().ManhattanDistance()
+}
+
+// Lerp returns the linear interpolation between v and w.
+func (v Vec3) Lerp(w Vec3, t float64) Vec3 {
+	return v.toVector().Lerp(w.toVector(), t).toVec3()
+}
+
+// Max returns the component-wise maximum of the two vectors.
+func (v Vec3) Max(w Vec3) Vec3 {
+	return v.toVector().Max(w.toVector()).toVec3()
+}
+
+// Min returns the component-wise minimum of the two vectors.
+func (v Vec3) Min(w Vec3) Vec3 {
+	return v.toVector().Min(w.toVector()).toVec3()
+}
+
+// MoveTowards returns v moved towards target by at most maxDelta.
+// Returns target if it is within maxDelta of v.
+func (v Vec3) MoveTowards(target Vec3, maxDelta float64) Vec3 {
+	d := target.Sub(v)
+	dist := d.Length()
+	if dist <= maxDelta || dist == 0 {
+		return target
+	}
+	return v.Add(d.Mul(maxDelta / dist))
+}
+
+func (v Vec3) Mul(scalar float64) Vec3 {
+	return v.toVector().Mul(scalar).toVec3()
+}
+
+// MulAdd returns v + scale*w, computing each component with a single
+// rounding using a fused multiply-add.
+func (v Vec3) MulAdd(scale float64, w Vec3) Vec3 {
+	return Vec3{X: math.FMA(scale, w.X, v.X), Y: math.FMA(scale, w.Y, v.Y), Z: math.FMA(scale, w.Z, v.Z)}
+}
+
+// Norm implements the Minkowski p-norm of the vector for p ≥ 1.
+func (v Vec3) Norm(p float64) float64 {
+	return v.toVector().Norm(p)
+}
+
+func (v Vec3) Normalize() Vec3 {
+	return v.toVector().Normalize().toVec3()
+}
+
+// NormalizeOrZero returns the unit vector in the direction of v,
+// or the zero vector if v has no direction.
+func (v Vec3) NormalizeOrZero() Vec3 {
+	return v.toVector().NormalizeOrZero().toVec3()
+}
+
+// Normalized returns the unit vector in the direction of v.
+// It returns false if v is zero or has a component that is not finite.
+func (v Vec3) Normalized() (Vec3, bool) {
+	u, ok := v.toVector().Normalized()
+	return u.toVec3(), ok
+}
+
+// OuterProduct returns the 3×3 matrix v⊗w whose element
+// in row i and column j is v[i]*w[j].
+func (v Vec3) OuterProduct(w Vec3) Mat3 {
+	return Mat3{
+		{v.X * w.X, v.X * w.Y, v.X * w.Z},
+		{v.Y * w.X, v.Y * w.Y, v.Y * w.Z},
+		{v.Z * w.X, v.Z * w.Y, v.Z * w.Z},
+	}
+}
+
+// Point returns the point at the position vector v.
+// Use it to mark a Vec3 as a position so transforms apply translation.
+func (v Vec3) Point() Point {
+	return Point{X: v.X, Y: v.Y, Z: v.Z}
+}
+
+// Reflect returns the vector reflected off a surface with the given normal.
+// The normal is expected to be normalized.
+//
+//	r = v − 2(v·n)n
+func (v Vec3) Reflect(normal Vec3) Vec3 {
+	return v.Sub(normal.Mul(2 * v.Dot(normal)))
+}
+
+// Refract returns the vector refracted through a surface with the given
+// normal, where eta is the ratio of the indices of refraction (incident
+// over transmitted). Both v and normal are expected to be normalized.
+// Returns false when the angle of incidence causes total internal reflection.
+func (v Vec3) Refract(normal Vec3, eta float64) (Vec3, bool) {
+	cosi := v.Dot(normal)
+	k := 1 - eta*eta*(1-cosi*cosi)
+	if k < 0 {
+		return Vec3{}, false
+	}
+	return v.Mul(eta).Sub(normal.Mul(eta*cosi + math.Sqrt(k))), true
+}
+
+// RotateTowards returns v rotated towards the direction of target by at
+// most maxRadians. The length of v is preserved. If target is within
+// maxRadians of v, the result points along target.
+func (v Vec3) RotateTowards(target Vec3, maxRadians float64) Vec3 {
+	length := v.Length()
+	if length == 0 || target.IsZero() {
+		return v
+	}
+	angle := v.AngleBetween(target)
+	if angle <= maxRadians {
+		return target.Normalize().Mul(length)
+	}
+	axis := v.Cross(target)
+	if axis.IsZero() {
+		// v and target are opposite, so any perpendicular axis will do
+		axis = v.anyPerpendicular()
+	}
+	axis = axis.Normalize()
+	// Rodrigues' formula with the axis perpendicular to v
+	sin, cos := math.Sincos(maxRadians)
+	return v.Mul(cos).Add(axis.Cross(v).Mul(sin))
+}
+
+// Round returns a vector with each component rounded to the nearest integer value.
+func (v Vec3) Round() Vec3 {
+	return v.toVector().Round().toVec3()
+}
+
+// ScalarTriple returns the scalar triple product v·(b×c), the signed
+// volume of the parallelepiped spanned by the three vectors. It is
+// positive when v, b, and c form a right-handed system.
+func (v Vec3) ScalarTriple(b, c Vec3) float64 {
+	return v.Dot(b.Cross(c))
+}
+
+// SignedAngle returns the angle, in radians, between the two vectors.
+// The sign is positive when the rotation from v to w is counter-clockwise
+// looking down the reference axis (that is, when axis·(v×w) is positive).
+func (v Vec3) SignedAngle(w, axis Vec3) float64 {
+	angle := v.AngleBetween(w)
+	if axis.Dot(v.Cross(w)) < 0 {
+		return -angle
+	}
+	return angle
+}
+
+// SetLength returns a vector with the same direction as v and the given length.
+func (v Vec3) SetLength(length float64) Vec3 {
+	return v.toVector().SetLength(length).toVec3()
+}
+
+// Skew returns the skew-symmetric cross-product matrix [v]×,
+// which satisfies [v]× w = v × w.
+func (v Vec3) Skew() Mat3 {
+	return Mat3{
+		{0, -v.Z, v.Y},
+		{v.Z, 0, -v.X},
+		{-v.Y, v.X, 0},
+	}
+}
+
+// Slerp returns the spherical linear interpolation from the unit vector
+// v to the unit vector w, which turns at a constant angular speed along
+// the great arc between them. When the vectors are nearly parallel it
+// falls back to Lerp. When they are opposite, the arc is taken through
+// an arbitrary perpendicular direction.
+//
+//	slerp(v, w, t) = (sin((1−t)θ) v + sin(tθ) w) / sin θ
+func (v Vec3) Slerp(w Vec3, t float64) Vec3 {
+	theta := v.AngleBetween(w)
+	if theta < 1e-6 {
+		return v.Lerp(w, t)
+	}
+	if math.Pi-theta < 1e-6 {
+		return RotateAboutAxis(v, v.anyPerpendicular().Normalize(), t*theta)
+	}
+	sin := math.Sin(theta)
+	return v.Mul(math.Sin((1-t)*theta) / sin).Add(w.Mul(math.Sin(t*theta) / sin))
+}
+
+func (v Vec3) StandardBasis() []Vec3 {
+	return StandardBasisVec3()
+}
+
+func (v Vec3) Sub(w Vec3) Vec3 {
+	return v.toVector().Sub(w.toVector()).toVec3()
+}
+
+func (v Vec3) UnitVector() Vec3 {
+	return v.toVector().UnitVector().toVec3()
+}
+
+// VectorTriple returns the vector triple product v×(b×c), computed as
+//
+//	v×(b×c) = b(v·c) − c(v·b)
+func (v Vec3) VectorTriple(b, c Vec3) Vec3 {
+	return b.Mul(v.Dot(c)).Sub(c.Mul(v.Dot(b)))
+}
+
+func (v Vec3) ZeroVector() Vec3 {
+	return Vec3{}
+}
+
+// anyPerpendicular returns a vector perpendicular to v.
+// It crosses v with the standard basis vector it is least aligned with.
+func (v Vec3) anyPerpendicular() Vec3 {
+	ax, ay, az := math.Abs(v.X), math.Abs(v.Y), math.Abs(v.Z)
+	if ax <= ay && ax <= az {
+		return v.Cross(Vec3{X: 1})
+	} else if ay <= az {
+		return v.Cross(Vec3{Y: 1})
+	}
+	return v.Cross(Vec3{Z: 1})
+}
+
 func (v Vec3) toVector() Vector {
 	return Vector{v.X, v.Y, v.Z}
 }
 
+// BuildOrthonormalBasis returns unit vectors t and b that complete the
+// unit vector n to a right-handed orthonormal basis, so that t × b = n.
+// It uses the branchless method of Duff et al., "Building an Orthonormal
+// Basis, Revisited" (2017), which is continuous except where n.Z changes
+// sign and stays accurate as n approaches −z.
+func BuildOrthonormalBasis(n Vec3) (t, b Vec3) {
+	sign := math.Copysign(1, n.Z)
+	a := -1 / (sign + n.Z)
+	c := n.X * n.Y * a
+	t = Vec3{X: 1 + sign*n.X*n.X*a, Y: sign * c, Z: -sign * n.X}
+	b = Vec3{X: c, Y: sign + n.Y*n.Y*a, Z: -n.Y}
+	return t, b
+}
+
 func NewVec3(x, y, z float64) Vec3 {
 	return Vec3{X: x, Y: y, Z: z}
 }
@@ -264,6 +1040,148 @@ type Vec4 struct {
 	W, X, Y, Z float64
 }
 
+// Abs returns a vector with the absolute value of each component.
+func (v Vec4) Abs() Vec4 {
+	return v.toVector().Abs().toVec4()
+}
+
+func (v Vec4) Add(w Vec4) Vec4 {
+	return v.toVector().Add(w.toVector()).toVec4()
+}
+
+// Apply returns a vector with fn applied to each component.
+func (v Vec4) Apply(fn func(float64) float64) Vec4 {
+	return v.toVector().Apply(fn).toVec4()
+}
+
+// ApproxEqual reports whether each component of v is within epsilon of
+// the corresponding component of w.
+func (v Vec4) ApproxEqual(w Vec4, epsilon float64) bool {
+	return v.toVector().ApproxEqual(w.toVector(), epsilon)
+}
+
+// ApproxEqualTol reports whether each component of v is equal to the
+// corresponding component of w within the absolute or relative tolerance.
+func (v Vec4) ApproxEqualTol(w Vec4, absTol, relTol float64) bool {
+	return v.toVector().ApproxEqualTol(w.toVector(), absTol, relTol)
+}
+
+// Ceil returns a vector with each component rounded up to an integer value.
+func (v Vec4) Ceil() Vec4 {
+	return v.toVector().Ceil().toVec4()
+}
+
+// ChebyshevDistance implements the maximum norm (L∞) of the vector.
+func (v Vec4) ChebyshevDistance() float64 {
+	return v.toVector().ChebyshevDistance()
+}
+
+// Clamp returns a vector with each component limited to the
+// range given by the corresponding components of lo and hi.
+func (v Vec4) Clamp(lo, hi Vec4) Vec4 {
+	return v.toVector().Clamp(lo.toVector(), hi.toVector()).toVec4()
+}
+
+// ClampLength returns the vector scaled down, if needed, so that its
+// length is no greater than max.
+func (v Vec4) ClampLength(max float64) Vec4 {
+	return v.toVector().ClampLength(max).toVec4()
+}
+
+// ClampLengthRange returns the vector rescaled, if needed, so that its
+// length is in the range [min, max].
+func (v Vec4) ClampLengthRange(min, max float64) Vec4 {
+	return v.toVector().ClampLengthRange(min, max).toVec4()
+}
+
+// Dot returns the scalar (inner) product of the two vectors.
+func (v Vec4) Dot(w Vec4) float64 {
+	return v.toVector().Dot(w.toVector())
+}
+
+// EqualWithinULP reports whether each component of v is no more than ulps
+// representable float64 values from the corresponding component of w.
+func (v Vec4) EqualWithinULP(w Vec4, ulps uint64) bool {
+	return v.toVector().EqualWithinULP(w.toVector(), ulps)
+}
+
+// Floor returns a vector with each component rounded down to an integer value.
+func (v Vec4) Floor() Vec4 {
+	return v.toVector().Floor().toVec4()
+}
+
+// Hadamard returns the component-wise product of the two vectors.
+func (v Vec4) Hadamard(w Vec4) Vec4 {
+	return v.toVector().Hadamard(w.toVector()).toVec4()
+}
+
+// HasNaN reports whether any component of the vector is NaN.
+func (v Vec4) HasNaN() bool {
+	return v.toVector().HasNaN()
+}
+
+// IsFinite reports whether every component of the vector is neither NaN nor infinite.
+func (v Vec4) IsFinite() bool {
+	return v.toVector().IsFinite()
+}
+
+// Lerp returns the linear interpolation between v and w.
+func (v Vec4) Lerp(w Vec4, t float64) Vec4 {
+	return v.toVector().Lerp(w.toVector(), t).toVec4()
+}
+
+// Max returns the component-wise maximum of the two vectors.
+func (v Vec4) Max(w Vec4) Vec4 {
+	return v.toVector().Max(w.toVector()).toVec4()
+}
+
+// Min returns the component-wise minimum of the two vectors.
+func (v Vec4) Min(w Vec4) Vec4 {
+	return v.toVector().Min(w.toVector()).toVec4()
+}
+
+func (v Vec4) Mul(scalar float64) Vec4 {
+	return v.toVector().Mul(scalar).toVec4()
+}
+
+// MulAdd returns v + scale*w, computing each component with a single
+// rounding using a fused multiply-add.
+func (v Vec4) MulAdd(scale float64, w Vec4) Vec4 {
+	return Vec4{W: math.FMA(scale, w.W, v.W), X: math.FMA(scale, w.X, v.X), Y: math.FMA(scale, w.Y, v.Y), Z: math.FMA(scale, w.Z, v.Z)}
+}
+
+// Norm implements the Minkowski p-norm of the vector for p ≥ 1.
+func (v Vec4) Norm(p float64) float64 {
+	return v.toVector().Norm(p)
+}
+
+// NormalizeOrZero returns the unit vector in the direction of v,
+// or the zero vector if v has no direction.
+func (v Vec4) NormalizeOrZero() Vec4 {
+	return v.toVector().NormalizeOrZero().toVec4()
+}
+
+// Normalized returns the unit vector in the direction of v.
+// It returns false if v is zero or has a component that is not finite.
+func (v Vec4) Normalized() (Vec4, bool) {
+	u, ok := v.toVector().Normalized()
+	return u.toVec4(), ok
+}
+
+// Round returns a vector with each component rounded to the nearest integer value.
+func (v Vec4) Round() Vec4 {
+	return v.toVector().Round().toVec4()
+}
+
+// SetLength returns a vector with the same direction as v and the given length.
+func (v Vec4) SetLength(length float64) Vec4 {
+	return v.toVector().SetLength(length).toVec4()
+}
+
+func (v Vec4) Sub(w Vec4) Vec4 {
+	return v.toVector().Sub(w.toVector()).toVec4()
+}
+
 func (v Vec4) toVector() Vector {
 	return Vector{v.W, v.X, v.Y, v.Z}
 }
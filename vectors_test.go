@@ -0,0 +1,423 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"testing"
+)
+
+func TestDot(t *testing.T) {
+	for _, tt := range []struct {
+		v, w   math3d.Vector
+		expect float64
+	}{
+		{math3d.NewVector(1, 0, 0), math3d.NewVector(0, 1, 0), 0},
+		{math3d.NewVector(1, 2, 3), math3d.NewVector(4, 5, 6), 32},
+		{math3d.NewVector(1, 2, 3), math3d.NewVector(-1, -2, -3), -14},
+	} {
+		d := tt.v.Dot(tt.w)
+		if d != tt.expect {
+			t.Errorf("Dot: want %f, got %f\n", tt.expect, d)
+		}
+	}
+
+	if d := math3d.NewVec2(1, 2).Dot(math3d.NewVec2(3, 4)); d != 11 {
+		t.Errorf("Vec2.Dot: want %f, got %f\n", 11.0, d)
+	}
+	if d := math3d.NewVec3(1, 2, 3).Dot(math3d.NewVec3(4, 5, 6)); d != 32 {
+		t.Errorf("Vec3.Dot: want %f, got %f\n", 32.0, d)
+	}
+	if d := math3d.NewVec4(1, 2, 3, 4).Dot(math3d.NewVec4(5, 6, 7, 8)); d != 70 {
+		t.Errorf("Vec4.Dot: want %f, got %f\n", 70.0, d)
+	}
+}
+
+func TestHadamard(t *testing.T) {
+	h := math3d.NewVec3(1, 2, 3).Hadamard(math3d.NewVec3(4, 5, 6))
+	if h != math3d.NewVec3(4, 10, 18) {
+		t.Errorf("Hadamard: want %v, got %v\n", math3d.NewVec3(4, 10, 18), h)
+	}
+}
+
+func TestCross(t *testing.T) {
+	sb := math3d.StandardBasisVec3()
+	for _, tt := range []struct {
+		v, w   math3d.Vec3
+		expect math3d.Vec3
+	}{
+		{sb[0], sb[1], sb[2]},
+		{sb[1], sb[2], sb[0]},
+		{sb[2], sb[0], sb[1]},
+		{sb[1], sb[0], math3d.NewVec3(0, 0, -1)},
+		{math3d.NewVec3(1, 2, 3), math3d.NewVec3(2, 4, 6), math3d.Vec3{}},
+	} {
+		c := tt.v.Cross(tt.w)
+		if c != tt.expect {
+			t.Errorf("Cross: want %v, got %v\n", tt.expect, c)
+		}
+	}
+
+	if c := math3d.NewVec2(1, 0).Cross(math3d.NewVec2(0, 1)); c != 1 {
+		t.Errorf("Vec2.Cross: want %f, got %f\n", 1.0, c)
+	}
+	if c := math3d.NewVec2(0, 1).Cross(math3d.NewVec2(1, 0)); c != -1 {
+		t.Errorf("Vec2.Cross: want %f, got %f\n", -1.0, c)
+	}
+}
+
+func TestReflect(t *testing.T) {
+	r := math3d.NewVec3(1, -1, 0).Reflect(math3d.NewVec3(0, 1, 0))
+	if r != math3d.NewVec3(1, 1, 0) {
+		t.Errorf("Reflect: want %v, got %v\n", math3d.NewVec3(1, 1, 0), r)
+	}
+	r2 := math3d.NewVec2(1, -1).Reflect(math3d.NewVec2(0, 1))
+	if r2 != math3d.NewVec2(1, 1) {
+		t.Errorf("Vec2.Reflect: want %v, got %v\n", math3d.NewVec2(1, 1), r2)
+	}
+}
+
+func TestRefract(t *testing.T) {
+	n := math3d.NewVec3(0, 1, 0)
+
+	// straight through when the indices match
+	v := math3d.NewVec3(1, -1, 0).Normalize()
+	r, ok := v.Refract(n, 1)
+	if !ok {
+		t.Errorf("Refract: want ok, got total internal reflection\n")
+	} else if d := r.Sub(v).Length(); d > 1e-12 {
+		t.Errorf("Refract: want %v, got %v\n", v, r)
+	}
+
+	// glass to air at a shallow angle
+	v = math3d.NewVec3(1, -0.1, 0).Normalize()
+	if _, ok = v.Refract(n, 1.5); ok {
+		t.Errorf("Refract: want total internal reflection, got ok\n")
+	}
+}
+
+func TestAngleBetween(t *testing.T) {
+	for _, tt := range []struct {
+		v, w   math3d.Vec3
+		expect float64
+	}{
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(0, 1, 0), math.Pi / 2},
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(2, 0, 0), 0},
+		{math3d.NewVec3(1, 0, 0), math3d.NewVec3(-3, 0, 0), math.Pi},
+		{math3d.NewVec3(1, 1, 0), math3d.NewVec3(1, 0, 0), math.Pi / 4},
+		{math3d.NewVec3(1, 1e-9, 0), math3d.NewVec3(1, 0, 0), 1e-9},
+	} {
+		a := tt.v.AngleBetween(tt.w)
+		if math.IsNaN(a) || math.Abs(a-tt.expect) > 1e-15 {
+			t.Errorf("AngleBetween: want %g, got %g\n", tt.expect, a)
+		}
+	}
+
+	if a := math3d.NewVec2(1, 0).SignedAngle(math3d.NewVec2(0, -1)); a != -math.Pi/2 {
+		t.Errorf("Vec2.SignedAngle: want %f, got %f\n", -math.Pi/2, a)
+	}
+	z := math3d.NewVec3(0, 0, 1)
+	if a := math3d.NewVec3(1, 0, 0).SignedAngle(math3d.NewVec3(0, -1, 0), z); a != -math.Pi/2 {
+		t.Errorf("Vec3.SignedAngle: want %f, got %f\n", -math.Pi/2, a)
+	}
+}
+
+func TestVec2Rotate(t *testing.T) {
+	if p := math3d.NewVec2(1, 2).Perp(); p != math3d.NewVec2(-2, 1) {
+		t.Errorf("Perp: want %v, got %v\n", math3d.NewVec2(-2, 1), p)
+	}
+	for _, tt := range []struct {
+		v       math3d.Vec2
+		radians float64
+		expect  math3d.Vec2
+	}{
+		{math3d.NewVec2(1, 0), math.Pi / 2, math3d.NewVec2(0, 1)},
+		{math3d.NewVec2(1, 0), math.Pi, math3d.NewVec2(-1, 0)},
+		{math3d.NewVec2(0, 2), -math.Pi / 2, math3d.NewVec2(2, 0)},
+	} {
+		r := tt.v.Rotate(tt.radians)
+		if r.Sub(tt.expect).Length() > 1e-12 {
+			t.Errorf("Rotate: want %v, got %v\n", tt.expect, r)
+		}
+	}
+}
+
+func TestMoveTowards(t *testing.T) {
+	v := math3d.NewVec3(0, 0, 0).MoveTowards(math3d.NewVec3(10, 0, 0), 3)
+	if v != math3d.NewVec3(3, 0, 0) {
+		t.Errorf("MoveTowards: want %v, got %v\n", math3d.NewVec3(3, 0, 0), v)
+	}
+	v = math3d.NewVec3(0, 0, 0).MoveTowards(math3d.NewVec3(1, 0, 0), 3)
+	if v != math3d.NewVec3(1, 0, 0) {
+		t.Errorf("MoveTowards: want %v, got %v\n", math3d.NewVec3(1, 0, 0), v)
+	}
+
+	r := math3d.NewVec3(2, 0, 0).RotateTowards(math3d.NewVec3(0, 5, 0), math.Pi/4)
+	if expect := math3d.NewVec3(math.Sqrt2, math.Sqrt2, 0); r.Sub(expect).Length() > 1e-12 {
+		t.Errorf("RotateTowards: want %v, got %v\n", expect, r)
+	}
+	r = math3d.NewVec3(2, 0, 0).RotateTowards(math3d.NewVec3(0, 5, 0), math.Pi)
+	if expect := math3d.NewVec3(0, 2, 0); r.Sub(expect).Length() > 1e-12 {
+		t.Errorf("RotateTowards: want %v, got %v\n", expect, r)
+	}
+	r = math3d.NewVec3(1, 0, 0).RotateTowards(math3d.NewVec3(-1, 0, 0), math.Pi/2)
+	if a := r.AngleBetween(math3d.NewVec3(1, 0, 0)); math.Abs(a-math.Pi/2) > 1e-12 {
+		t.Errorf("RotateTowards: opposite: want angle %f, got %f\n", math.Pi/2, a)
+	}
+}
+
+func TestClampLength(t *testing.T) {
+	for _, tt := range []struct {
+		v        math3d.Vec3
+		min, max float64
+		expect   math3d.Vec3
+	}{
+		{math3d.NewVec3(3, 4, 0), 0, 10, math3d.NewVec3(3, 4, 0)},
+		{math3d.NewVec3(3, 4, 0), 0, 2.5, math3d.NewVec3(1.5, 2, 0)},
+		{math3d.NewVec3(3, 4, 0), 10, 20, math3d.NewVec3(6, 8, 0)},
+		{math3d.NewVec3(0, 0, 0), 1, 2, math3d.NewVec3(0, 0, 0)},
+	} {
+		c := tt.v.ClampLengthRange(tt.min, tt.max)
+		if c != tt.expect {
+			t.Errorf("ClampLengthRange: want %v, got %v\n", tt.expect, c)
+		}
+	}
+	if c := math3d.NewVec2(3, 4).ClampLength(2.5); c != math3d.NewVec2(1.5, 2) {
+		t.Errorf("ClampLength: want %v, got %v\n", math3d.NewVec2(1.5, 2), c)
+	}
+	if s := math3d.NewVec3(0, 0, 2).SetLength(5); s != math3d.NewVec3(0, 0, 5) {
+		t.Errorf("SetLength: want %v, got %v\n", math3d.NewVec3(0, 0, 5), s)
+	}
+	if s := math3d.NewVec3(0, 0, 0).SetLength(5); !s.IsZero() {
+		t.Errorf("SetLength: want %v, got %v\n", math3d.Vec3{}, s)
+	}
+}
+
+func TestComponentWise(t *testing.T) {
+	v, w := math3d.NewVec3(-1.5, 2, 3.5), math3d.NewVec3(1, -2, 4)
+	for _, tt := range []struct {
+		name        string
+		got, expect math3d.Vec3
+	}{
+		{"Abs", v.Abs(), math3d.NewVec3(1.5, 2, 3.5)},
+		{"Ceil", v.Ceil(), math3d.NewVec3(-1, 2, 4)},
+		{"Floor", v.Floor(), math3d.NewVec3(-2, 2, 3)},
+		{"Round", v.Round(), math3d.NewVec3(-2, 2, 4)},
+		{"Min", v.Min(w), math3d.NewVec3(-1.5, -2, 3.5)},
+		{"Max", v.Max(w), math3d.NewVec3(1, 2, 4)},
+		{"Clamp", v.Clamp(math3d.NewVec3(-1, -1, -1), math3d.NewVec3(1, 1, 1)), math3d.NewVec3(-1, 1, 1)},
+	} {
+		if tt.got != tt.expect {
+			t.Errorf("%s: want %v, got %v\n", tt.name, tt.expect, tt.got)
+		}
+	}
+}
+
+func TestMulAdd(t *testing.T) {
+	v, w := math3d.NewVec3(1, 2, 3), math3d.NewVec3(4, 5, 6)
+	if u := v.MulAdd(2, w); u != math3d.NewVec3(9, 12, 15) {
+		t.Errorf("MulAdd: want %v, got %v\n", math3d.NewVec3(9, 12, 15), u)
+	}
+	u := math3d.NewVector(1, 2).MulAdd(-1, math3d.NewVector(1, 2))
+	if !u.IsZero() {
+		t.Errorf("Vector.MulAdd: want %v, got %v\n", math3d.NewVector(0, 0), u)
+	}
+}
+
+func TestIsFinite(t *testing.T) {
+	for _, tt := range []struct {
+		v              math3d.Vec3
+		finite, hasNaN bool
+	}{
+		{math3d.NewVec3(1, 2, 3), true, false},
+		{math3d.NewVec3(1, math.NaN(), 3), false, true},
+		{math3d.NewVec3(1, 2, math.Inf(-1)), false, false},
+		{math3d.NewVec3(0, 0, 0).Div(0), false, true},
+	} {
+		if ok := tt.v.IsFinite(); ok != tt.finite {
+			t.Errorf("IsFinite(%v): want %v, got %v\n", tt.v, tt.finite, ok)
+		}
+		if ok := tt.v.HasNaN(); ok != tt.hasNaN {
+			t.Errorf("HasNaN(%v): want %v, got %v\n", tt.v, tt.hasNaN, ok)
+		}
+	}
+}
+
+func TestNormalized(t *testing.T) {
+	for _, tt := range []struct {
+		v      math3d.Vec3
+		expect math3d.Vec3
+		ok     bool
+	}{
+		{math3d.NewVec3(0, 3, 0), math3d.NewVec3(0, 1, 0), true},
+		{math3d.NewVec3(0, 0, 0), math3d.NewVec3(0, 0, 0), false},
+		{math3d.NewVec3(math.Inf(1), 0, 0), math3d.NewVec3(0, 0, 0), false},
+		{math3d.NewVec3(math.NaN(), 0, 0), math3d.NewVec3(0, 0, 0), false},
+		{math3d.NewVec3(1e200, 1e200, 0), math3d.NewVec3(1/math.Sqrt2, 1/math.Sqrt2, 0), true},
+		{math3d.NewVec3(1e-200, 0, 0), math3d.NewVec3(1, 0, 0), true},
+	} {
+		u, ok := tt.v.Normalized()
+		if ok != tt.ok || !u.ApproxEqual(tt.expect, 1e-15) {
+			t.Errorf("Normalized(%v): want %v %v, got %v %v\n", tt.v, tt.expect, tt.ok, u, ok)
+		}
+		if u = tt.v.NormalizeOrZero(); !u.ApproxEqual(tt.expect, 1e-15) {
+			t.Errorf("NormalizeOrZero(%v): want %v, got %v\n", tt.v, tt.expect, u)
+		}
+	}
+}
+
+func TestNorm(t *testing.T) {
+	v := math3d.NewVec3(3, -4, 0)
+	for _, tt := range []struct {
+		p, expect float64
+	}{
+		{1, 7},
+		{2, 5},
+		{3, math.Cbrt(91)},
+		{math.Inf(1), 4},
+	} {
+		if n := v.Norm(tt.p); math.Abs(n-tt.expect) > 1e-12 {
+			t.Errorf("Norm(%g): want %f, got %f\n", tt.p, tt.expect, n)
+		}
+	}
+	if d := v.ChebyshevDistance(); d != 4 {
+		t.Errorf("ChebyshevDistance: want %f, got %f\n", 4.0, d)
+	}
+}
+
+func TestSwizzle(t *testing.T) {
+	v := math3d.NewVec3(1, 2, 3)
+	if s := v.XZ(); s != math3d.NewVec2(1, 3) {
+		t.Errorf("XZ: want %v, got %v\n", math3d.NewVec2(1, 3), s)
+	}
+	if s := v.YZX(); s != math3d.NewVec3(2, 3, 1) {
+		t.Errorf("YZX: want %v, got %v\n", math3d.NewVec3(2, 3, 1), s)
+	}
+	if s := math3d.NewVec4(4, 1, 2, 3).XYZ(); s != v {
+		t.Errorf("Vec4.XYZ: want %v, got %v\n", v, s)
+	}
+}
+
+func TestTripleProducts(t *testing.T) {
+	sb := math3d.StandardBasisVec3()
+	if s := sb[0].ScalarTriple(sb[1], sb[2]); s != 1 {
+		t.Errorf("ScalarTriple: want %f, got %f\n", 1.0, s)
+	}
+	if s := sb[1].ScalarTriple(sb[0], sb[2]); s != -1 {
+		t.Errorf("ScalarTriple: want %f, got %f\n", -1.0, s)
+	}
+	a, b, c := math3d.NewVec3(1, 2, 3), math3d.NewVec3(-2, 0, 5), math3d.NewVec3(4, -1, 1)
+	if v, expect := a.VectorTriple(b, c), a.Cross(b.Cross(c)); v != expect {
+		t.Errorf("VectorTriple: want %v, got %v\n", expect, v)
+	}
+}
+
+func TestOuterProduct(t *testing.T) {
+	m := math3d.NewVec3(1, 2, 3).OuterProduct(math3d.NewVec3(4, 5, 6))
+	expect := math3d.Mat3{{4, 5, 6}, {8, 10, 12}, {12, 15, 18}}
+	if m != expect {
+		t.Errorf("OuterProduct: want %v, got %v\n", expect, m)
+	}
+
+	mn := math3d.NewVector(1, 2).OuterProduct(math3d.NewVector(3, 4, 5))
+	if len(mn) != 2 || len(mn[0]) != 3 {
+		t.Fatalf("Vector.OuterProduct: want 2x3, got %dx%d\n", len(mn), len(mn[0]))
+	}
+	if mn[1][2] != 10 {
+		t.Errorf("Vector.OuterProduct: [1][2]: want %f, got %f\n", 10.0, mn[1][2])
+	}
+}
+
+func TestBuildOrthonormalBasis(t *testing.T) {
+	for _, n := range []math3d.Vec3{
+		math3d.NewVec3(0, 0, 1),
+		math3d.NewVec3(0, 0, -1),
+		math3d.NewVec3(1, 0, 0),
+		math3d.NewVec3(0, -1, 0),
+		math3d.NewVec3(1, 2, 3).Normalize(),
+		math3d.NewVec3(1e-9, -2e-9, -1).Normalize(),
+		math3d.NewVec3(-0.3, 0.1, -0.9).Normalize(),
+	} {
+		u, v := math3d.BuildOrthonormalBasis(n)
+		for _, tt := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"t·t", u.Dot(u), 1},
+			{"b·b", v.Dot(v), 1},
+			{"t·b", u.Dot(v), 0},
+			{"t·n", u.Dot(n), 0},
+			{"b·n", v.Dot(n), 0},
+		} {
+			if math.Abs(tt.got-tt.want) > 1e-12 {
+				t.Errorf("BuildOrthonormalBasis(%v): %s: want %v, got %v\n", n, tt.name, tt.want, tt.got)
+			}
+		}
+		if c := u.Cross(v); !c.ApproxEqual(n, 1e-12) {
+			t.Errorf("BuildOrthonormalBasis(%v): t×b: want %v, got %v\n", n, n, c)
+		}
+	}
+}
+
+func TestVec3Slerp(t *testing.T) {
+	x, y := math3d.NewVec3(1, 0, 0), math3d.NewVec3(0, 1, 0)
+	for _, tt := range []struct {
+		t    float64
+		want math3d.Vec3
+	}{
+		{0, x},
+		{1, y},
+		{0.5, math3d.NewVec3(math.Sqrt2/2, math.Sqrt2/2, 0)},
+		{1.0 / 3, math3d.NewVec3(math.Cos(math.Pi/6), math.Sin(math.Pi/6), 0)},
+	} {
+		if got := x.Slerp(y, tt.t); !got.ApproxEqual(tt.want, 1e-12) {
+			t.Errorf("Slerp(%v): want %v, got %v\n", tt.t, tt.want, got)
+		}
+	}
+
+	// nearly parallel vectors fall back to lerp
+	z := math3d.NewVec3(1, 1e-9, 0)
+	if got, want := x.Slerp(z, 0.5), x.Lerp(z, 0.5); !got.ApproxEqual(want, 1e-15) {
+		t.Errorf("Slerp: parallel: want %v, got %v\n", want, got)
+	}
+
+	// opposite vectors turn through a perpendicular at unit length
+	u, w := math3d.NewVec3(0, 0, 1), math3d.NewVec3(0, 0, -1)
+	mid := u.Slerp(w, 0.5)
+	if math.Abs(mid.Length()-1) > 1e-12 || math.Abs(mid.Dot(u)) > 1e-12 {
+		t.Errorf("Slerp: opposite: want unit vector perpendicular to %v, got %v\n", u, mid)
+	}
+	if got := u.Slerp(w, 1); !got.ApproxEqual(w, 1e-12) {
+		t.Errorf("Slerp: opposite: want %v, got %v\n", w, got)
+	}
+	u = math3d.NewVec3(1, 2, 3).Normalize()
+	w = u.Mul(-1)
+	for _, s := range []float64{0.25, 0.5, 0.75} {
+		got := u.Slerp(w, s)
+		if math.Abs(got.Length()-1) > 1e-12 || math.Abs(u.AngleBetween(got)-s*math.Pi) > 1e-9 {
+			t.Errorf("Slerp: opposite %v: want unit vector at angle %f, got %v at %f\n", s, s*math.Pi, got, u.AngleBetween(got))
+		}
+	}
+}
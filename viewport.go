@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// Viewport is the rectangle of the window that normalized device
+// coordinates are mapped onto. X and Y locate the lower-left corner.
+type Viewport struct {
+	X, Y, Width, Height float64
+}
+
+// NDCToWindow maps normalized device coordinates to window coordinates.
+// Depth is mapped from [−1, 1] to [0, 1].
+func (vp Viewport) NDCToWindow(ndc Point) Point {
+	return Point{
+		X: vp.X + (ndc.X+1)*vp.Width/2,
+		Y: vp.Y + (ndc.Y+1)*vp.Height/2,
+		Z: (ndc.Z + 1) / 2,
+	}
+}
+
+// WindowToNDC maps window coordinates to normalized device coordinates.
+// It is the inverse of NDCToWindow.
+func (vp Viewport) WindowToNDC(win Point) Point {
+	return Point{
+		X: 2*(win.X-vp.X)/vp.Width - 1,
+		Y: 2*(win.Y-vp.Y)/vp.Height - 1,
+		Z: 2*win.Z - 1,
+	}
+}
+
+// Project maps a point in object space to window coordinates, like
+// gluProject. The window origin is the lower-left corner of the viewport
+// and the depth is in [0, 1] for projections that follow the OpenGL
+// clip-space convention. It returns false if the point is on the plane
+// through the eye, where it has no projection.
+func Project(obj Point, modelview, projection Mat4, viewport Viewport) (Point, bool) {
+	clip := projection.Mul(modelview).MulVec4(Vec4{X: obj.X, Y: obj.Y, Z: obj.Z, W: 1})
+	if clip.W == 0 {
+		return Point{}, false
+	}
+	ndc := Point{X: clip.X / clip.W, Y: clip.Y / clip.W, Z: clip.Z / clip.W}
+	return viewport.NDCToWindow(ndc), true
+}
+
+// Unproject maps window coordinates back to object space, like
+// gluUnProject. It is the inverse of Project. It returns false if the
+// combined transform is singular or the point maps to infinity.
+func Unproject(win Point, modelview, projection Mat4, viewport Viewport) (Point, bool) {
+	inv, ok := projection.Mul(modelview).Inverse()
+	if !ok {
+		return Point{}, false
+	}
+	ndc := viewport.WindowToNDC(win)
+	obj := inv.MulVec4(Vec4{X: ndc.X, Y: ndc.Y, Z: ndc.Z, W: 1})
+	if obj.W == 0 {
+		return Point{}, false
+	}
+	return Point{X: obj.X / obj.W, Y: obj.Y / obj.W, Z: obj.Z / obj.W}, true
+}
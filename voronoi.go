@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d
+
+// VoronoiCell is the region of the plane nearer to one point, its site,
+// than to any other. Vertices holds its corners in counter-clockwise
+// order. A cell whose site is on the convex hull of the points is
+// unbounded: its boundary comes in from infinity along a ray to the first
+// vertex and leaves along a ray from the last, and FirstRay and LastRay
+// are the unit directions of those rays pointing away from the vertices.
+// For a bounded cell, both are zero.
+type VoronoiCell struct {
+	Vertices          []Vec2
+	FirstRay, LastRay Vec2
+}
+
+// Voronoi2D returns the Voronoi cell of each point, found as the dual of
+// the Delaunay triangulation: the corners of a cell are the centers of
+// the circles of the triangles around its site. Repeated points share a
+// cell. Where four or more points lie on a circle, a corner may appear
+// more than once. It returns false if all the points lie on a line.
+func Voronoi2D(points []Vec2) ([]VoronoiCell, bool) {
+	tr, ok := Delaunay2D(points)
+	if !ok {
+		return nil, false
+	}
+	centers := make([]Vec2, len(tr.Triangles))
+	for t, v := range tr.Triangles {
+		centers[t] = circumcenter2D(points[v[0]], points[v[1]], points[v[2]])
+	}
+
+	// the triangles around a site are linked through their neighbors
+	corner := map[int][2]int{}
+	for t, v := range tr.Triangles {
+		for k, i := range v {
+			corner[i] = [2]int{t, k}
+		}
+	}
+	cells := make([]VoronoiCell, len(points))
+	for i, p := range points {
+		tk, ok := corner[i]
+		if !ok {
+			continue // a repeated point, filled in below
+		}
+		t, k := tk[0], tk[1]
+		// back up clockwise to the hull, or all the way around
+		for start := t; ; {
+			prev := tr.Neighbors[t][k]
+			if prev < 0 || prev == start {
+				break
+			}
+			t, k = prev, cornerOf(tr.Triangles[prev], i)
+		}
+		var cell VoronoiCell
+		first, firstK := t, k
+		for {
+			cell.Vertices = append(cell.Vertices, centers[t])
+			next := tr.Neighbors[t][(k+2)%3]
+			if next < 0 {
+				// the edges from the site to the hull bound the cell with rays
+				v := tr.Triangles[first]
+				out := points[v[(firstK+1)%3]].Sub(p)
+				cell.FirstRay = Vec2{X: out.Y, Y: -out.X}.NormalizeOrZero()
+				in := p.Sub(points[tr.Triangles[t][(k+2)%3]])
+				cell.LastRay = Vec2{X: in.Y, Y: -in.X}.NormalizeOrZero()
+				break
+			}
+			if next == first {
+				break
+			}
+			t, k = next, cornerOf(tr.Triangles[next], i)
+		}
+		cells[i] = cell
+	}
+	rep := map[Vec2]int{}
+	for i := range corner {
+		rep[points[i]] = i
+	}
+	for i, p := range points {
+		if _, ok := corner[i]; !ok {
+			cells[i] = cells[rep[p]]
+		}
+	}
+	return cells, true
+}
+
+// Voronoi2DClipped returns the Voronoi cell of each point clipped to the
+// x and y extent of bounds, as a polygon with its corners in
+// counter-clockwise order. Each cell is the box cut down by the
+// perpendicular bisectors between its site and the sites next to it in
+// the Delaunay triangulation, so every cell is bounded. A cell that lies
+// outside the box is empty. Repeated points share a cell. It returns
+// false if all the points lie on a line.
+func Voronoi2DClipped(points []Vec2, bounds AABB) ([][]Vec2, bool) {
+	tr, ok := Delaunay2D(points)
+	if !ok {
+		return nil, false
+	}
+	neighbors := map[int]map[int]bool{}
+	for _, v := range tr.Triangles {
+		for k, i := range v {
+			if neighbors[i] == nil {
+				neighbors[i] = map[int]bool{}
+			}
+			neighbors[i][v[(k+1)%3]], neighbors[i][v[(k+2)%3]] = true, true
+		}
+	}
+	rep := map[Vec2]int{}
+	for i := range neighbors {
+		rep[points[i]] = i
+	}
+	box := Polygon{
+		{X: bounds.Min.X, Y: bounds.Min.Y},
+		{X: bounds.Max.X, Y: bounds.Min.Y},
+		{X: bounds.Max.X, Y: bounds.Max.Y},
+		{X: bounds.Min.X, Y: bounds.Max.Y},
+	}
+	cells := make([][]Vec2, len(points))
+	for i, p := range points {
+		site := Point{X: p.X, Y: p.Y}
+		poly := box
+		for j := range neighbors[rep[p]] {
+			q := Point{X: points[j].X, Y: points[j].Y}
+			poly = poly.ClipByPlane(PlaneFromPointNormal(site.Lerp(q, 0.5), site.Sub(q)))
+		}
+		for _, c := range poly {
+			cells[i] = append(cells[i], Vec2{X: c.X, Y: c.Y})
+		}
+	}
+	return cells, true
+}
+
+// circumcenter2D returns the center of the circle through a, b, and c.
+func circumcenter2D(a, b, c Vec2) Vec2 {
+	ab, ac := b.Sub(a), c.Sub(a)
+	d := 2 * ab.Cross(ac)
+	return Vec2{
+		X: a.X + (ac.Y*ab.LengthSquared()-ab.Y*ac.LengthSquared())/d,
+		Y: a.Y + (ab.X*ac.LengthSquared()-ac.X*ab.LengthSquared())/d,
+	}
+}
+
+// cornerOf returns the position of the point i among the corners v.
+func cornerOf(v [3]int, i int) int {
+	for k, j := range v {
+		if j == i {
+			return k
+		}
+	}
+	return -1
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 Michael D Henderson
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package math3d_test
+
+import (
+	"github.com/maloquacious/math3d"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVoronoi2D(t *testing.T) {
+	v := math3d.NewVec2
+	cells, ok := math3d.Voronoi2D([]math3d.Vec2{v(0, 0), v(2, 0), v(2, 2), v(0, 2), v(1, 1)})
+	if !ok || len(cells) != 5 {
+		t.Fatalf("Voronoi2D: want 5 cells, got %v %v\n", cells, ok)
+	}
+	// the center's cell is a diamond, in counter-clockwise order
+	diamond := []math3d.Vec2{v(1, 0), v(2, 1), v(1, 2), v(0, 1)}
+	center := cells[4]
+	if len(center.Vertices) != 4 || center.FirstRay != (math3d.Vec2{}) || center.LastRay != (math3d.Vec2{}) {
+		t.Errorf("Voronoi2D: center: want %v, got %v\n", diamond, center)
+	} else {
+		offset := 0
+		for i, c := range center.Vertices {
+			if c.ApproxEqual(diamond[0], 1e-12) {
+				offset = i
+			}
+		}
+		for i, want := range diamond {
+			if got := center.Vertices[(i+offset)%4]; !got.ApproxEqual(want, 1e-12) {
+				t.Errorf("Voronoi2D: center: want %v, got %v\n", diamond, center.Vertices)
+				break
+			}
+		}
+	}
+	corner := cells[0]
+	if !corner.FirstRay.ApproxEqual(v(0, -1), 1e-12) || !corner.LastRay.ApproxEqual(v(-1, 0), 1e-12) {
+		t.Errorf("Voronoi2D: corner: want rays down and left, got %v\n", corner)
+	}
+
+	rng := rand.New(rand.NewSource(848))
+	var points []math3d.Vec2
+	for i := 0; i < 200; i++ {
+		points = append(points, v(rng.Float64()*10, rng.Float64()*10))
+	}
+	points = append(points, points[7])
+	cells, ok = math3d.Voronoi2D(points)
+	if !ok {
+		t.Fatalf("Voronoi2D: want ok, got !ok\n")
+	}
+	// nearest returns the distance from p to the nearest point
+	nearest := func(p math3d.Vec2) float64 {
+		best := math.Inf(1)
+		for _, q := range points {
+			best = math.Min(best, p.Sub(q).Length())
+		}
+		return best
+	}
+	hull := map[math3d.Vec2]bool{}
+	for _, p := range math3d.ConvexHull2D(points, true) {
+		hull[p] = true
+	}
+	for i, cell := range cells {
+		site := points[i]
+		for _, c := range cell.Vertices {
+			if d := c.Sub(site).Length(); math.Abs(d-nearest(c)) > 1e-9 {
+				t.Errorf("Voronoi2D: cell %d: want %v nearest its site, got %v against %v\n", i, c, d, nearest(c))
+			}
+		}
+		if unbounded := cell.FirstRay != (math3d.Vec2{}); unbounded != hull[site] {
+			t.Errorf("Voronoi2D: cell %d: want unbounded %v, got %v\n", i, hull[site], unbounded)
+		} else if unbounded {
+			// far along either ray, the site is still among the nearest
+			for _, far := range []math3d.Vec2{
+				cell.Vertices[0].Add(cell.FirstRay.Mul(100)),
+				cell.Vertices[len(cell.Vertices)-1].Add(cell.LastRay.Mul(100)),
+			} {
+				if d := far.Sub(site).Length(); math.Abs(d-nearest(far)) > 1e-9 {
+					t.Errorf("Voronoi2D: cell %d: want %v nearest its site, got %v against %v\n", i, far, d, nearest(far))
+				}
+			}
+		}
+	}
+
+	bounds := math3d.AABB{Min: math3d.Point{X: 2, Y: 1}, Max: math3d.Point{X: 9, Y: 8}}
+	clipped, ok := math3d.Voronoi2DClipped(points, bounds)
+	if !ok {
+		t.Fatalf("Voronoi2DClipped: want ok, got !ok\n")
+	}
+	var area float64
+	for i, poly := range clipped {
+		if i == len(points)-1 {
+			continue // a repeat of point 7
+		}
+		for j, a := range poly {
+			area += a.Cross(poly[(j+1)%len(poly)]) / 2
+		}
+		p := math3d.Point{X: points[i].X, Y: points[i].Y}
+		if bounds.ContainsPoint(p) && len(poly) < 3 {
+			t.Errorf("Voronoi2DClipped: cell %d: want a polygon around %v, got %v\n", i, p, poly)
+		}
+	}
+	if math.Abs(area-49) > 1e-9 {
+		t.Errorf("Voronoi2DClipped: want cells covering 49, got %v\n", area)
+	}
+	if len(clipped[len(points)-1]) != len(clipped[7]) {
+		t.Errorf("Voronoi2DClipped: want repeated points to share a cell, got %v and %v\n", clipped[7], clipped[len(points)-1])
+	}
+}